@@ -10,19 +10,119 @@ type ScheduledSnapshotSpec struct {
 	Schedule string `json:"schedule"`
 	// Target is the URL to take a screenshot of
 	Target string `json:"target"`
-	// ScreenshotDiffFormat specifies the format for diff generation ("pixel" or "rectangle")
-	// +kubebuilder:validation:Enum=pixel;rectangle
+	// ScreenshotDiffFormat specifies the format for diff generation ("pixel", "rectangle", "ssim" or "shift").
+	// The "shift" format aligns screenshot rows first and reports inserted and removed bands separately
+	// +kubebuilder:validation:Enum=pixel;rectangle;ssim;shift
 	// +kubebuilder:validation:Required
 	// +kubebuilder:default="pixel"
 	ScreenshotDiffFormat string `json:"screenshotDiffFormat"`
-	// HTMLDiffFormat specifies the format for HTML diff generation ("line")
-	// +kubebuilder:validation:Enum=line
+	// HTMLDiffFormat specifies the format for HTML diff generation ("line" or "dom").
+	// The "dom" format compares the element trees and reports added, removed, modified and moved elements by their path,
+	// matching nodes across reordering so that a moved node is reported once with its former path
+	// +kubebuilder:validation:Enum=line;dom
 	// +kubebuilder:validation:Required
 	// +kubebuilder:default="line"
 	HTMLDiffFormat string `json:"htmlDiffFormat"`
+	// HTMLDiffContextLines is the number of unchanged lines shown around each change of the "line" HTML diff, which is
+	// rendered in the unified diff format. Defaults to 3
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	HTMLDiffContextLines *int `json:"htmlDiffContextLines,omitempty"`
+	// HTMLDiffHighlight specifies how the "line" HTML diff highlights the changes within modified lines ("word" or
+	// "character"), in its changes and in its rendering as HTML
+	// +kubebuilder:validation:Enum=word;character
+	// +kubebuilder:default="word"
+	// +optional
+	HTMLDiffHighlight string `json:"htmlDiffHighlight,omitempty"`
+	// HTMLNormalization rewrites the captured HTML before the HTML diff
+	// +optional
+	HTMLNormalization *HTMLNormalization `json:"htmlNormalization,omitempty"`
+	// HTMLIgnore excludes elements, attributes and lines from the HTML diff, such as the markup of MaskSelectors
+	// +optional
+	HTMLIgnore *HTMLIgnore `json:"htmlIgnore,omitempty"`
+	// TextDiffFormat specifies the format for visible text diff generation ("word")
+	// +kubebuilder:validation:Enum=word
+	// +kubebuilder:default="word"
+	// +optional
+	TextDiffFormat string `json:"textDiffFormat,omitempty"`
+	// ScreenshotDiffPalette specifies the colors of the screenshot diff rendering ("default" or "color-blind"). The
+	// "color-blind" palette uses colors distinguishable with color vision deficiency and dims unchanged pixels
+	// +kubebuilder:validation:Enum=default;color-blind
+	// +kubebuilder:default="default"
+	// +optional
+	ScreenshotDiffPalette string `json:"screenshotDiffPalette,omitempty"`
+	// ScreenshotDiffStrokeWidth is the width in pixels of the rectangles drawn by the "rectangle" screenshot diff format
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	// +optional
+	ScreenshotDiffStrokeWidth int `json:"screenshotDiffStrokeWidth,omitempty"`
+	// ColorDistance specifies how the "pixel" screenshot diff compares colors ("brightness" or "perceptual"). The
+	// "perceptual" distance additionally flags pixels whose hue changed at the same brightness
+	// +kubebuilder:validation:Enum=brightness;perceptual
+	// +kubebuilder:default="brightness"
+	// +optional
+	ColorDistance string `json:"colorDistance,omitempty"`
+	// ColorTolerance is the perceptual color distance (0.0 to 1.0) below which colors are considered equal
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +kubebuilder:default=0.1
+	// +optional
+	ColorTolerance *float64 `json:"colorTolerance,omitempty"`
+	// PerceptualHashDistance treats screenshots of the same size as unchanged, without comparing their pixels, when their
+	// perceptual hashes differ in at most this many of their 64 bits. Identical screenshots are always skipped
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=64
+	// +optional
+	PerceptualHashDistance *int `json:"perceptualHashDistance,omitempty"`
+	// DetectAntiAliasing excludes pixels changed by anti-aliasing from the pixel diff and renders them in yellow
+	// +optional
+	DetectAntiAliasing bool `json:"detectAntiAliasing,omitempty"`
+	// IgnoreRegions are areas excluded from the "pixel", "rectangle" and "shift" screenshot diffs, given as pixel
+	// rectangles or as CSS selectors resolved to bounding boxes on both captures. Pixel rectangles also apply to each
+	// printed page of the "pdf" capture mode, while selectors only apply to the screenshot
+	// +optional
+	IgnoreRegions []IgnoreRegion `json:"ignoreRegions,omitempty"`
+	// ScreenshotComposites are additional renderings of the screenshot diff ("side-by-side", "overlay", "onion-skin" or
+	// "blink"). The "onion-skin" and "blink" composites are animated GIFs
+	// +optional
+	ScreenshotComposites []CompositeFormat `json:"screenshotComposites,omitempty"`
+	// ScreenshotFormat specifies the image format of the screenshots and the screenshot diffs ("jpeg" or "png"). The
+	// "png" format is lossless and keeps compression noise out of the comparison
+	// +kubebuilder:validation:Enum=jpeg;png
+	// +kubebuilder:default="jpeg"
+	// +optional
+	ScreenshotFormat string `json:"screenshotFormat,omitempty"`
+	// SizeMismatch specifies how screenshots of different sizes are compared ("union", "crop-to-common",
+	// "pad-with-color" or "scale-to-baseline"). The "union" policy treats the area outside of the smaller screenshot as
+	// white
+	// +kubebuilder:validation:Enum=union;crop-to-common;pad-with-color;scale-to-baseline
+	// +kubebuilder:default="union"
+	// +optional
+	SizeMismatch string `json:"sizeMismatch,omitempty"`
+	// PadColor is the color in #rrggbb form that fills the missing area with the "pad-with-color" size mismatch policy
+	// +kubebuilder:validation:Pattern=`^#[0-9a-fA-F]{6}$`
+	// +kubebuilder:default="#ffffff"
+	// +optional
+	PadColor string `json:"padColor,omitempty"`
 	// MaskSelectors is a list of CSS selectors to mask during capture to avoid diff noise
 	// +optional
 	MaskSelectors []string `json:"maskSelectors,omitempty"`
+	// StyleSelectors is a list of CSS selectors whose computed styles are captured and compared
+	// +optional
+	StyleSelectors []string `json:"styleSelectors,omitempty"`
+	// StyleProperties limits the computed style properties captured for StyleSelectors (all properties when empty)
+	// +optional
+	StyleProperties []string `json:"styleProperties,omitempty"`
+	// CaptureMode specifies how pages are captured ("screen", "pdf" or "http"). The "pdf" mode additionally renders
+	// the page as PDF and compares each printed page. The "http" mode fetches the HTML without a browser and skips
+	// the screenshot diff
+	// +kubebuilder:validation:Enum=screen;pdf;http
+	// +kubebuilder:default="screen"
+	// +optional
+	CaptureMode string `json:"captureMode,omitempty"`
+	// PDF configures the paper used by the "pdf" capture mode
+	// +optional
+	PDF *PDFSpec `json:"pdf,omitempty"`
 	// Headers are optional HTTP headers to use when capturing the target URL
 	// +optional
 	Headers map[string]string `json:"headers,omitempty"`
@@ -38,18 +138,77 @@ type ScheduledSnapshotStatus struct {
 	BaselineHTMLURL string `json:"baselineHtmlUrl,omitempty"`
 	// TargetHTMLURL is the storage URL where the target HTML is stored
 	TargetHTMLURL string `json:"targetHtmlUrl,omitempty"`
+	// BaselineTextURL is the storage URL where the baseline visible text is stored
+	BaselineTextURL string `json:"baselineTextUrl,omitempty"`
+	// TargetTextURL is the storage URL where the target visible text is stored
+	TargetTextURL string `json:"targetTextUrl,omitempty"`
+	// BaselineStylesURL is the storage URL where the baseline computed styles are stored
+	BaselineStylesURL string `json:"baselineStylesUrl,omitempty"`
+	// TargetStylesURL is the storage URL where the target computed styles are stored
+	TargetStylesURL string `json:"targetStylesUrl,omitempty"`
+	// BaselinePDFURL is the storage URL where the baseline PDF is stored
+	BaselinePDFURL string `json:"baselinePdfUrl,omitempty"`
+	// TargetPDFURL is the storage URL where the target PDF is stored
+	TargetPDFURL string `json:"targetPdfUrl,omitempty"`
+	// BaselinePageURLs are the storage URLs where the screenshots of the baseline printed pages are stored
+	BaselinePageURLs []string `json:"baselinePageUrls,omitempty"`
+	// TargetPageURLs are the storage URLs where the screenshots of the target printed pages are stored
+	TargetPageURLs []string `json:"targetPageUrls,omitempty"`
 	// ScreenshotDiffURL is the storage URL where the screenshot diff image is stored
 	ScreenshotDiffURL string `json:"screenshotDiffUrl,omitempty"`
+	// ScreenshotDiffRegionsURL is the storage URL where the changed regions are stored as JSON, reported by the
+	// "rectangle" screenshot diff format
+	ScreenshotDiffRegionsURL string `json:"screenshotDiffRegionsUrl,omitempty"`
+	// ScreenshotComposites are the additional renderings of the screenshot diff
+	ScreenshotComposites []Composite `json:"screenshotComposites,omitempty"`
 	// ScreenshotDiffAmount is the percentage of screenshot difference (0.0 to 1.0)
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=1
 	ScreenshotDiffAmount float64 `json:"screenshotDiffAmount,omitempty"`
+	// ScreenshotInsertedBands are the rows of the target screenshot that do not exist in the baseline, reported by the
+	// "shift" screenshot diff format
+	// +optional
+	ScreenshotInsertedBands []Band `json:"screenshotInsertedBands,omitempty"`
+	// ScreenshotRemovedBands are the rows of the baseline screenshot that do not exist in the target, reported by the
+	// "shift" screenshot diff format
+	// +optional
+	ScreenshotRemovedBands []Band `json:"screenshotRemovedBands,omitempty"`
+	// ScreenshotDimensionChange is the size difference between the baseline and target screenshots, empty when they
+	// have the same size
+	// +optional
+	ScreenshotDimensionChange *DimensionChange `json:"screenshotDimensionChange,omitempty"`
 	// HTMLDiffURL is the storage URL where the HTML diff is stored
 	HTMLDiffURL string `json:"htmlDiffUrl,omitempty"`
+	// HTMLDiffChangesURL is the storage URL where the changes of the HTML diff are stored as JSON, reported by the
+	// line and dom formats when there are any
+	HTMLDiffChangesURL string `json:"htmlDiffChangesUrl,omitempty"`
+	// HTMLDiffRenderedURL is the storage URL where the "line" HTML diff rendered as HTML is stored, with the changes
+	// within modified lines in <del> and <ins>
+	HTMLDiffRenderedURL string `json:"htmlDiffRenderedUrl,omitempty"`
 	// HTMLDiffAmount is the percentage of HTML difference (0.0 to 1.0)
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=1
 	HTMLDiffAmount float64 `json:"htmlDiffAmount,omitempty"`
+	// TextDiffURL is the storage URL where the visible text diff is stored
+	TextDiffURL string `json:"textDiffUrl,omitempty"`
+	// TextDiffAmount is the percentage of visible text difference (0.0 to 1.0)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	TextDiffAmount float64 `json:"textDiffAmount,omitempty"`
+	// StyleDiffURL is the storage URL where the computed style diff is stored
+	StyleDiffURL string `json:"styleDiffUrl,omitempty"`
+	// StyleDiffAmount is the percentage of changed computed style properties (0.0 to 1.0)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	StyleDiffAmount float64 `json:"styleDiffAmount,omitempty"`
+	// PageDiffs are the screenshot diffs of each printed page
+	PageDiffs []PageDiff `json:"pageDiffs,omitempty"`
+	// BaselineIgnoreRegions are the bounding boxes of the IgnoreRegions selectors on the baseline screenshot
+	// +optional
+	BaselineIgnoreRegions []Region `json:"baselineIgnoreRegions,omitempty"`
+	// TargetIgnoreRegions are the bounding boxes of the IgnoreRegions selectors on the target screenshot
+	// +optional
+	TargetIgnoreRegions []Region `json:"targetIgnoreRegions,omitempty"`
 	// LastSnapshotTime is the time when the last snapshot was taken
 	LastSnapshotTime *metaV1.Time `json:"lastSnapshotTime,omitempty"`
 }
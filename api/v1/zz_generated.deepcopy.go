@@ -8,6 +8,193 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Band) DeepCopyInto(out *Band) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Band.
+func (in *Band) DeepCopy() *Band {
+	if in == nil {
+		return nil
+	}
+	out := new(Band)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Composite) DeepCopyInto(out *Composite) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Composite.
+func (in *Composite) DeepCopy() *Composite {
+	if in == nil {
+		return nil
+	}
+	out := new(Composite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DimensionChange) DeepCopyInto(out *DimensionChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DimensionChange.
+func (in *DimensionChange) DeepCopy() *DimensionChange {
+	if in == nil {
+		return nil
+	}
+	out := new(DimensionChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTMLIgnore) DeepCopyInto(out *HTMLIgnore) {
+	*out = *in
+	if in.Selectors != nil {
+		in, out := &in.Selectors, &out.Selectors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Lines != nil {
+		in, out := &in.Lines, &out.Lines
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTMLIgnore.
+func (in *HTMLIgnore) DeepCopy() *HTMLIgnore {
+	if in == nil {
+		return nil
+	}
+	out := new(HTMLIgnore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTMLNormalization) DeepCopyInto(out *HTMLNormalization) {
+	*out = *in
+	if in.Replacements != nil {
+		in, out := &in.Replacements, &out.Replacements
+		*out = make([]HTMLReplacement, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTMLNormalization.
+func (in *HTMLNormalization) DeepCopy() *HTMLNormalization {
+	if in == nil {
+		return nil
+	}
+	out := new(HTMLNormalization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTMLReplacement) DeepCopyInto(out *HTMLReplacement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTMLReplacement.
+func (in *HTMLReplacement) DeepCopy() *HTMLReplacement {
+	if in == nil {
+		return nil
+	}
+	out := new(HTMLReplacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IgnoreRegion) DeepCopyInto(out *IgnoreRegion) {
+	*out = *in
+	out.Region = in.Region
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IgnoreRegion.
+func (in *IgnoreRegion) DeepCopy() *IgnoreRegion {
+	if in == nil {
+		return nil
+	}
+	out := new(IgnoreRegion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PDFMargin) DeepCopyInto(out *PDFMargin) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDFMargin.
+func (in *PDFMargin) DeepCopy() *PDFMargin {
+	if in == nil {
+		return nil
+	}
+	out := new(PDFMargin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PDFSpec) DeepCopyInto(out *PDFSpec) {
+	*out = *in
+	out.Margin = in.Margin
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDFSpec.
+func (in *PDFSpec) DeepCopy() *PDFSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PDFSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PageDiff) DeepCopyInto(out *PageDiff) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PageDiff.
+func (in *PageDiff) DeepCopy() *PageDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(PageDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Region) DeepCopyInto(out *Region) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Region.
+func (in *Region) DeepCopy() *Region {
+	if in == nil {
+		return nil
+	}
+	out := new(Region)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScheduledSnapshot) DeepCopyInto(out *ScheduledSnapshot) {
 	*out = *in
@@ -70,11 +257,68 @@ func (in *ScheduledSnapshotList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScheduledSnapshotSpec) DeepCopyInto(out *ScheduledSnapshotSpec) {
 	*out = *in
+	if in.HTMLDiffContextLines != nil {
+		in, out := &in.HTMLDiffContextLines, &out.HTMLDiffContextLines
+		*out = new(int)
+		**out = **in
+	}
+	if in.HTMLNormalization != nil {
+		in, out := &in.HTMLNormalization, &out.HTMLNormalization
+		*out = new(HTMLNormalization)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTMLIgnore != nil {
+		in, out := &in.HTMLIgnore, &out.HTMLIgnore
+		*out = new(HTMLIgnore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ColorTolerance != nil {
+		in, out := &in.ColorTolerance, &out.ColorTolerance
+		*out = new(float64)
+		**out = **in
+	}
+	if in.PerceptualHashDistance != nil {
+		in, out := &in.PerceptualHashDistance, &out.PerceptualHashDistance
+		*out = new(int)
+		**out = **in
+	}
+	if in.IgnoreRegions != nil {
+		in, out := &in.IgnoreRegions, &out.IgnoreRegions
+		*out = make([]IgnoreRegion, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScreenshotComposites != nil {
+		in, out := &in.ScreenshotComposites, &out.ScreenshotComposites
+		*out = make([]CompositeFormat, len(*in))
+		copy(*out, *in)
+	}
 	if in.MaskSelectors != nil {
 		in, out := &in.MaskSelectors, &out.MaskSelectors
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.StyleSelectors != nil {
+		in, out := &in.StyleSelectors, &out.StyleSelectors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StyleProperties != nil {
+		in, out := &in.StyleProperties, &out.StyleProperties
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PDF != nil {
+		in, out := &in.PDF, &out.PDF
+		*out = new(PDFSpec)
+		**out = **in
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSnapshotSpec.
@@ -90,6 +334,51 @@ func (in *ScheduledSnapshotSpec) DeepCopy() *ScheduledSnapshotSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScheduledSnapshotStatus) DeepCopyInto(out *ScheduledSnapshotStatus) {
 	*out = *in
+	if in.BaselinePageURLs != nil {
+		in, out := &in.BaselinePageURLs, &out.BaselinePageURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetPageURLs != nil {
+		in, out := &in.TargetPageURLs, &out.TargetPageURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScreenshotComposites != nil {
+		in, out := &in.ScreenshotComposites, &out.ScreenshotComposites
+		*out = make([]Composite, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScreenshotInsertedBands != nil {
+		in, out := &in.ScreenshotInsertedBands, &out.ScreenshotInsertedBands
+		*out = make([]Band, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScreenshotRemovedBands != nil {
+		in, out := &in.ScreenshotRemovedBands, &out.ScreenshotRemovedBands
+		*out = make([]Band, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScreenshotDimensionChange != nil {
+		in, out := &in.ScreenshotDimensionChange, &out.ScreenshotDimensionChange
+		*out = new(DimensionChange)
+		**out = **in
+	}
+	if in.PageDiffs != nil {
+		in, out := &in.PageDiffs, &out.PageDiffs
+		*out = make([]PageDiff, len(*in))
+		copy(*out, *in)
+	}
+	if in.BaselineIgnoreRegions != nil {
+		in, out := &in.BaselineIgnoreRegions, &out.BaselineIgnoreRegions
+		*out = make([]Region, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetIgnoreRegions != nil {
+		in, out := &in.TargetIgnoreRegions, &out.TargetIgnoreRegions
+		*out = make([]Region, len(*in))
+		copy(*out, *in)
+	}
 	if in.LastSnapshotTime != nil {
 		in, out := &in.LastSnapshotTime, &out.LastSnapshotTime
 		*out = (*in).DeepCopy()
@@ -168,11 +457,68 @@ func (in *SnapshotList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SnapshotSpec) DeepCopyInto(out *SnapshotSpec) {
 	*out = *in
+	if in.HTMLDiffContextLines != nil {
+		in, out := &in.HTMLDiffContextLines, &out.HTMLDiffContextLines
+		*out = new(int)
+		**out = **in
+	}
+	if in.HTMLNormalization != nil {
+		in, out := &in.HTMLNormalization, &out.HTMLNormalization
+		*out = new(HTMLNormalization)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTMLIgnore != nil {
+		in, out := &in.HTMLIgnore, &out.HTMLIgnore
+		*out = new(HTMLIgnore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ColorTolerance != nil {
+		in, out := &in.ColorTolerance, &out.ColorTolerance
+		*out = new(float64)
+		**out = **in
+	}
+	if in.PerceptualHashDistance != nil {
+		in, out := &in.PerceptualHashDistance, &out.PerceptualHashDistance
+		*out = new(int)
+		**out = **in
+	}
+	if in.IgnoreRegions != nil {
+		in, out := &in.IgnoreRegions, &out.IgnoreRegions
+		*out = make([]IgnoreRegion, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScreenshotComposites != nil {
+		in, out := &in.ScreenshotComposites, &out.ScreenshotComposites
+		*out = make([]CompositeFormat, len(*in))
+		copy(*out, *in)
+	}
 	if in.MaskSelectors != nil {
 		in, out := &in.MaskSelectors, &out.MaskSelectors
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.StyleSelectors != nil {
+		in, out := &in.StyleSelectors, &out.StyleSelectors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StyleProperties != nil {
+		in, out := &in.StyleProperties, &out.StyleProperties
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PDF != nil {
+		in, out := &in.PDF, &out.PDF
+		*out = new(PDFSpec)
+		**out = **in
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotSpec.
@@ -188,6 +534,41 @@ func (in *SnapshotSpec) DeepCopy() *SnapshotSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SnapshotStatus) DeepCopyInto(out *SnapshotStatus) {
 	*out = *in
+	if in.BaselinePageURLs != nil {
+		in, out := &in.BaselinePageURLs, &out.BaselinePageURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetPageURLs != nil {
+		in, out := &in.TargetPageURLs, &out.TargetPageURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScreenshotComposites != nil {
+		in, out := &in.ScreenshotComposites, &out.ScreenshotComposites
+		*out = make([]Composite, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScreenshotInsertedBands != nil {
+		in, out := &in.ScreenshotInsertedBands, &out.ScreenshotInsertedBands
+		*out = make([]Band, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScreenshotRemovedBands != nil {
+		in, out := &in.ScreenshotRemovedBands, &out.ScreenshotRemovedBands
+		*out = make([]Band, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScreenshotDimensionChange != nil {
+		in, out := &in.ScreenshotDimensionChange, &out.ScreenshotDimensionChange
+		*out = new(DimensionChange)
+		**out = **in
+	}
+	if in.PageDiffs != nil {
+		in, out := &in.PageDiffs, &out.PageDiffs
+		*out = make([]PageDiff, len(*in))
+		copy(*out, *in)
+	}
 	if in.LastSnapshotTime != nil {
 		in, out := &in.LastSnapshotTime, &out.LastSnapshotTime
 		*out = (*in).DeepCopy()
@@ -18,8 +18,13 @@ import (
 )
 
 type SnapshotResult struct {
-	ScreenshotPath string `json:"screenshotPath"`
-	HTMLPath       string `json:"htmlPath"`
+	ScreenshotPath string                `json:"screenshotPath,omitempty"`
+	HTMLPath       string                `json:"htmlPath"`
+	TextPath       string                `json:"textPath,omitempty"`
+	StylesPath     string                `json:"stylesPath,omitempty"`
+	PDFPath        string                `json:"pdfPath,omitempty"`
+	PagePaths      []string              `json:"pagePaths,omitempty"`
+	IgnoreRegions  []capture.BoundingBox `json:"ignoreRegions,omitempty"`
 }
 
 type headers []string
@@ -79,6 +84,13 @@ func main() {
 	var directory string
 	var format string
 	var maskSelectors string
+	var styleSelectors string
+	var ignoreSelectors string
+	var styleProperties string
+	var captureMode string
+	var pdfFormat string
+	var pdfLandscape bool
+	var pdfMargin string
 	var delay time.Duration
 	var viewportWidth int
 	var viewportHeight int
@@ -87,6 +99,13 @@ func main() {
 	flag.StringVar(&directory, "directory", envOrDefaultValue("DIRECTORY", "/tmp"), "Output directory")
 	flag.StringVar(&format, "format", envOrDefaultValue("FORMAT", "jpeg"), "Output format (jpeg or png)")
 	flag.StringVar(&maskSelectors, "mask-selectors", envOrDefaultValue("MASK_SELECTORS", ""), "Comma-separated list of CSS selectors to mask during capture")
+	flag.StringVar(&styleSelectors, "style-selectors", envOrDefaultValue("STYLE_SELECTORS", ""), "Comma-separated list of CSS selectors whose computed styles are captured")
+	flag.StringVar(&ignoreSelectors, "ignore-selectors", envOrDefaultValue("IGNORE_SELECTORS", ""), "Comma-separated list of CSS selectors whose bounding boxes are reported as ignored regions")
+	flag.StringVar(&styleProperties, "style-properties", envOrDefaultValue("STYLE_PROPERTIES", ""), "Comma-separated list of computed style properties to capture (all properties when empty)")
+	flag.StringVar(&captureMode, "capture-mode", envOrDefaultValue("CAPTURE_MODE", "screen"), "Capture mode (screen, pdf or http)")
+	flag.StringVar(&pdfFormat, "pdf-format", envOrDefaultValue("PDF_FORMAT", "A4"), "Paper format of the pdf capture mode (e.g., A4 or Letter)")
+	flag.BoolVar(&pdfLandscape, "pdf-landscape", envOrDefaultValue("PDF_LANDSCAPE", false), "Print in landscape orientation in the pdf capture mode")
+	flag.StringVar(&pdfMargin, "pdf-margin", envOrDefaultValue("PDF_MARGIN", ""), "Comma-separated paper margins of the pdf capture mode in top,right,bottom,left order (e.g., 10mm,10mm,10mm,10mm)")
 	flag.DurationVar(&delay, "delay", envOrDefaultValue("DELAY", 3*time.Second), "Delay before capturing")
 	flag.IntVar(&viewportWidth, "viewport-width", envOrDefaultValue("VIEWPORT_WIDTH", 1920), "Viewport width in pixels")
 	flag.IntVar(&viewportHeight, "viewport-height", envOrDefaultValue("VIEWPORT_HEIGHT", 1080), "Viewport height in pixels")
@@ -130,7 +149,12 @@ func main() {
 		config.ViewportHeight = viewportHeight
 	}
 
-	capturer, err := capture.NewPlaywrightCapturer(ctx, config)
+	var capturer capture.Capturer
+	if captureMode == "http" {
+		capturer, err = capture.NewHTTPCapturer(ctx, capture.DefaultHTTPConfig())
+	} else {
+		capturer, err = capture.NewPlaywrightCapturer(ctx, config)
+	}
 	if err != nil {
 		log.Fatalf("Failed to create capturer: %v", err)
 	}
@@ -142,6 +166,43 @@ func main() {
 			captureOptions.MaskSelectors[i] = strings.TrimSpace(captureOptions.MaskSelectors[i])
 		}
 	}
+	if styleSelectors != "" {
+		captureOptions.StyleSelectors = strings.Split(styleSelectors, ",")
+		for i := range captureOptions.StyleSelectors {
+			captureOptions.StyleSelectors[i] = strings.TrimSpace(captureOptions.StyleSelectors[i])
+		}
+	}
+	if ignoreSelectors != "" {
+		captureOptions.IgnoreSelectors = strings.Split(ignoreSelectors, ",")
+		for i := range captureOptions.IgnoreSelectors {
+			captureOptions.IgnoreSelectors[i] = strings.TrimSpace(captureOptions.IgnoreSelectors[i])
+		}
+	}
+	if styleProperties != "" {
+		captureOptions.StyleProperties = strings.Split(styleProperties, ",")
+		for i := range captureOptions.StyleProperties {
+			captureOptions.StyleProperties[i] = strings.TrimSpace(captureOptions.StyleProperties[i])
+		}
+	}
+	if captureMode == "pdf" {
+		captureOptions.PDF = &capture.PDFOptions{
+			Format:    pdfFormat,
+			Landscape: pdfLandscape,
+		}
+		if pdfMargin != "" {
+			margins := strings.Split(pdfMargin, ",")
+			for i := range margins {
+				margins[i] = strings.TrimSpace(margins[i])
+			}
+			for len(margins) < 4 {
+				margins = append(margins, "")
+			}
+			captureOptions.PDF.MarginTop = margins[0]
+			captureOptions.PDF.MarginRight = margins[1]
+			captureOptions.PDF.MarginBottom = margins[2]
+			captureOptions.PDF.MarginLeft = margins[3]
+		}
+	}
 	if len(headers) > 0 {
 		for _, header := range headers {
 			parts := strings.SplitN(header, ":", 2)
@@ -176,19 +237,25 @@ func main() {
 
 	var imagePath string
 	var htmlPath string
+	var textPath string
+	var stylesPath string
+	var pdfPath string
+	pagePaths := make([]string, len(result.Pages))
 
 	{
 		eg, ctx := errgroup.WithContext(ctx)
 
-		eg.Go(func() error {
-			imageKey := fmt.Sprintf("%s.%s", baseKey, config.Format)
-			path, err := s.Put(ctx, imageKey, result.Screenshot)
-			if err != nil {
-				return err
-			}
-			imagePath = path
-			return nil
-		})
+		if result.Screenshot != nil {
+			eg.Go(func() error {
+				imageKey := fmt.Sprintf("%s.%s", baseKey, config.Format)
+				path, err := s.Put(ctx, imageKey, result.Screenshot)
+				if err != nil {
+					return err
+				}
+				imagePath = path
+				return nil
+			})
+		}
 
 		eg.Go(func() error {
 			htmlKey := fmt.Sprintf("%s.html", baseKey)
@@ -200,6 +267,54 @@ func main() {
 			return nil
 		})
 
+		if result.Text != nil {
+			eg.Go(func() error {
+				textKey := fmt.Sprintf("%s.txt", baseKey)
+				path, err := s.Put(ctx, textKey, result.Text)
+				if err != nil {
+					return err
+				}
+				textPath = path
+				return nil
+			})
+		}
+
+		if result.Styles != nil {
+			eg.Go(func() error {
+				stylesKey := fmt.Sprintf("%s-styles.json", baseKey)
+				path, err := s.Put(ctx, stylesKey, result.Styles)
+				if err != nil {
+					return err
+				}
+				stylesPath = path
+				return nil
+			})
+		}
+
+		if result.PDF != nil {
+			eg.Go(func() error {
+				pdfKey := fmt.Sprintf("%s.pdf", baseKey)
+				path, err := s.Put(ctx, pdfKey, result.PDF)
+				if err != nil {
+					return err
+				}
+				pdfPath = path
+				return nil
+			})
+		}
+
+		for i, page := range result.Pages {
+			eg.Go(func() error {
+				pageKey := fmt.Sprintf("%s-page-%d.%s", baseKey, i+1, config.Format)
+				path, err := s.Put(ctx, pageKey, page)
+				if err != nil {
+					return err
+				}
+				pagePaths[i] = path
+				return nil
+			})
+		}
+
 		if err := eg.Wait(); err != nil {
 			log.Fatalf("Failed to upload: %v", err)
 		}
@@ -208,6 +323,11 @@ func main() {
 	if err := json.NewEncoder(os.Stdout).Encode(SnapshotResult{
 		ScreenshotPath: imagePath,
 		HTMLPath:       htmlPath,
+		TextPath:       textPath,
+		StylesPath:     stylesPath,
+		PDFPath:        pdfPath,
+		PagePaths:      pagePaths,
+		IgnoreRegions:  result.IgnoreRegions,
 	}); err != nil {
 		log.Fatalf("Failed to encode result: %v", err)
 	}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"image"
+	"image/color"
 	_ "image/jpeg"
 	"image/png"
 	"io"
@@ -22,6 +23,7 @@ import (
 	difftext "snapshot-controller/internal/diff/text"
 	"snapshot-controller/internal/myhttp"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -253,8 +255,19 @@ func (s *Server) Start(ctx context.Context) error {
 }
 
 type DiffResponse struct {
-	DiffData   string  `json:"diffData"`
-	DiffAmount float64 `json:"diffAmount"`
+	DiffData      string                    `json:"diffData"`
+	DiffAmount    float64                   `json:"diffAmount"`
+	InsertedBands []diffimage.Band          `json:"insertedBands,omitempty"`
+	RemovedBands  []diffimage.Band          `json:"removedBands,omitempty"`
+	Regions       []diffimage.ChangedRegion `json:"regions,omitempty"`
+	// DimensionChange is reported when the screenshots have different sizes.
+	DimensionChange *diffimage.DimensionChange `json:"dimensionChange,omitempty"`
+	// Composites maps the requested composite formats to their base64 encoded renderings.
+	Composites map[string]string `json:"composites,omitempty"`
+	// Changes are the changes of the line and dom formats, returned when the changes form value is true.
+	Changes []difftext.Change `json:"changes,omitempty"`
+	// RenderedData is the base64 encoded line diff rendered as HTML.
+	RenderedData string `json:"renderedData,omitempty"`
 }
 
 func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
@@ -268,14 +281,61 @@ func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
 		format = "pixel"
 	}
 
-	baselineFile, _, err := r.FormFile("baseline")
+	detectAntiAliasing, _ := strconv.ParseBool(r.FormValue("detectAntiAliasing"))
+	ignoreRegions, err := parseRectangles(r.FormValue("ignoreRectangles"))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	var padColor color.Color
+	if value := r.FormValue("padColor"); value != "" {
+		padColor, err = diffimage.ParseHexColor(value)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+	}
+	palette, err := diffimage.PaletteByName(r.FormValue("palette"))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	diffOptions := []diffimage.Option{
+		diffimage.WithAntiAliasingDetection(detectAntiAliasing),
+		diffimage.WithIgnoreRegions(ignoreRegions...),
+		diffimage.WithSizeMismatch(r.FormValue("sizeMismatch"), padColor),
+		diffimage.WithPalette(palette),
+	}
+	if strokeWidth, err := strconv.Atoi(r.FormValue("strokeWidth")); err == nil && strokeWidth > 0 {
+		diffOptions = append(diffOptions, diffimage.WithStrokeWidth(strokeWidth))
+	}
+	if distance, err := strconv.Atoi(r.FormValue("perceptualHashDistance")); err == nil && distance >= 0 {
+		diffOptions = append(diffOptions, diffimage.WithPerceptualHash(distance))
+	}
+	if r.FormValue("colorDistance") == "perceptual" {
+		colorTolerance, err := strconv.ParseFloat(r.FormValue("colorTolerance"), 64)
+		if err != nil {
+			colorTolerance = 0.1
+		}
+		diffOptions = append(diffOptions, diffimage.WithPerceptualColorDistance(colorTolerance))
+	}
+
+	var composites []string
+	if value := r.FormValue("composites"); value != "" {
+		composites = strings.Split(value, ",")
+		for i := range composites {
+			composites[i] = strings.TrimSpace(composites[i])
+		}
+	}
+
+	baselineFile, baselineHeader, err := r.FormFile("baseline")
 	if err != nil {
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 	defer baselineFile.Close()
 
-	targetFile, _, err := r.FormFile("target")
+	targetFile, targetHeader, err := r.FormFile("target")
 	if err != nil {
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
@@ -295,25 +355,33 @@ func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
 	}
 
 	switch format {
-	case "pixel", "rectangle":
+	case "pixel", "rectangle", "ssim", "shift":
 		baselineImage, err := decodeImage(baselineData)
 		if err != nil {
 			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 			return
 		}
 
-		targetImage, err := decodeImage(targetData)
-		if err != nil {
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-			return
+		targetImage := baselineImage
+		if !bytes.Equal(baselineData, targetData) {
+			targetImage, err = decodeImage(targetData)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
 		}
 
 		var diffResult *diffimage.DiffResult
 
-		if format == "pixel" {
-			diffResult = diffimage.NewPixelDiff(0.1).Calculate(baselineImage, targetImage)
-		} else {
-			diffResult = diffimage.NewRectangleDiff().Calculate(baselineImage, targetImage)
+		switch format {
+		case "pixel":
+			diffResult = diffimage.NewPixelDiff(0.1, diffOptions...).Calculate(baselineImage, targetImage)
+		case "rectangle":
+			diffResult = diffimage.NewRectangleDiff(diffOptions...).Calculate(baselineImage, targetImage)
+		case "ssim":
+			diffResult = diffimage.NewSSIMDiff(diffOptions...).Calculate(baselineImage, targetImage)
+		case "shift":
+			diffResult = diffimage.NewShiftDiff(0.1, diffOptions...).Calculate(baselineImage, targetImage)
 		}
 
 		var buffer bytes.Buffer
@@ -322,23 +390,88 @@ func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		var compositeData map[string]string
+		for _, composite := range composites {
+			data, _, err := diffimage.RenderComposite(composite, baselineImage, targetImage, diffResult.Image, "png")
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			if compositeData == nil {
+				compositeData = make(map[string]string, len(composites))
+			}
+			compositeData[composite] = base64.StdEncoding.EncodeToString(data)
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(DiffResponse{
-			DiffData:   base64.StdEncoding.EncodeToString(buffer.Bytes()),
-			DiffAmount: diffResult.DiffAmount,
+			DiffData:        base64.StdEncoding.EncodeToString(buffer.Bytes()),
+			DiffAmount:      diffResult.DiffAmount,
+			InsertedBands:   diffResult.InsertedBands,
+			RemovedBands:    diffResult.RemovedBands,
+			Regions:         diffResult.Regions,
+			DimensionChange: diffResult.DimensionChange,
+			Composites:      compositeData,
 		}); err != nil {
 			slog.Error("Failed to encode response", "error", err)
 		}
 		return
 
-	case "line", "dom":
+	case "line", "dom", "word", "style":
 		var diffResult *difftext.DiffResult
 		var err error
 
-		if format == "line" {
-			diffResult, err = difftext.NewLineDiff().Calculate(baselineData, targetData)
-		} else {
-			diffResult, err = difftext.NewDOMDiff().Calculate(baselineData, targetData)
+		if value := r.FormValue("htmlNormalization"); value != "" && (format == "line" || format == "dom") {
+			var normalizer difftext.Normalizer
+			if err := json.Unmarshal([]byte(value), &normalizer); err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			if err := normalizer.Validate(); err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			baselineData, err = normalizer.Normalize(baselineData)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			targetData, err = normalizer.Normalize(targetData)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+		}
+
+		var ignore *difftext.Ignore
+		if value := r.FormValue("htmlIgnore"); value != "" && (format == "line" || format == "dom") {
+			ignore = &difftext.Ignore{}
+			if err := json.Unmarshal([]byte(value), ignore); err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			if err := ignore.Validate(); err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+		}
+
+		switch format {
+		case "line":
+			lineDiffOptions := []difftext.LineDiffOption{difftext.WithLabels(baselineHeader.Filename, targetHeader.Filename), difftext.WithIgnore(ignore)}
+			if contextLines, err := strconv.Atoi(r.FormValue("contextLines")); err == nil {
+				lineDiffOptions = append(lineDiffOptions, difftext.WithContextLines(contextLines))
+			}
+			if highlight := r.FormValue("highlight"); highlight != "" {
+				lineDiffOptions = append(lineDiffOptions, difftext.WithHighlight(highlight))
+			}
+			diffResult, err = difftext.NewLineDiff(lineDiffOptions...).Calculate(baselineData, targetData)
+		case "dom":
+			diffResult, err = difftext.NewDOMDiff(difftext.WithDOMIgnore(ignore)).Calculate(baselineData, targetData)
+		case "style":
+			diffResult, err = difftext.NewStyleDiff().Calculate(baselineData, targetData)
+		default:
+			diffResult, err = difftext.NewWordDiff().Calculate(baselineData, targetData)
 		}
 
 		if err != nil {
@@ -346,11 +479,19 @@ func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(DiffResponse{
+		response := DiffResponse{
 			DiffData:   base64.StdEncoding.EncodeToString(diffResult.Diff),
 			DiffAmount: diffResult.DiffAmount,
-		}); err != nil {
+		}
+		if changes, _ := strconv.ParseBool(r.FormValue("changes")); changes {
+			response.Changes = diffResult.Changes
+		}
+		if diffResult.Rendered != nil {
+			response.RenderedData = base64.StdEncoding.EncodeToString(diffResult.Rendered)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
 			slog.Error("Failed to encode response", "error", err)
 		}
 		return
@@ -377,3 +518,30 @@ func main() {
 		log.Fatalf("Server failed: %v", err)
 	}
 }
+
+// parseRectangles parses a semicolon-separated list of x,y,width,height rectangles.
+func parseRectangles(value string) ([]diffimage.Rectangle, error) {
+	var rectangles []diffimage.Rectangle
+	for _, item := range strings.Split(value, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.Split(item, ",")
+		if len(parts) != 4 {
+			return nil, xerrors.Errorf("invalid rectangle %q: expected x,y,width,height", item)
+		}
+
+		values := make([]int, len(parts))
+		for i, part := range parts {
+			v, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return nil, xerrors.Errorf("invalid rectangle %q: %w", item, err)
+			}
+			values[i] = v
+		}
+		rectangles = append(rectangles, diffimage.Rectangle{X: values[0], Y: values[1], Width: values[2], Height: values[3]})
+	}
+	return rectangles, nil
+}
@@ -16,12 +16,22 @@ import (
 	difftext "snapshot-controller/internal/diff/text"
 	"snapshot-controller/internal/storage"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type DiffOutput struct {
-	DiffPath   string  `json:"diffPath"`
-	DiffAmount float64 `json:"diffAmount"`
+	DiffPath      string                    `json:"diffPath"`
+	DiffAmount    float64                   `json:"diffAmount"`
+	InsertedBands []diffimage.Band          `json:"insertedBands,omitempty"`
+	RemovedBands  []diffimage.Band          `json:"removedBands,omitempty"`
+	Regions       []diffimage.ChangedRegion `json:"regions,omitempty"`
+	// DimensionChange is reported when the screenshots have different sizes.
+	DimensionChange *diffimage.DimensionChange `json:"dimensionChange,omitempty"`
+	// ChangesPath is where the changes of the line and dom formats are stored as JSON.
+	ChangesPath string `json:"changesPath,omitempty"`
+	// RenderedPath is where the line diff rendered as HTML is stored.
+	RenderedPath string `json:"renderedPath,omitempty"`
 }
 
 func envOrDefaultValue[T any](key string, defaultValue T) T {
@@ -69,8 +79,34 @@ func envOrDefaultValue[T any](key string, defaultValue T) T {
 func main() {
 	var directory string
 	var format string
+	var detectAntiAliasing bool
+	var ignoreRectangles string
+	var sizeMismatch string
+	var padColor string
+	var palette string
+	var strokeWidth int
+	var colorDistance string
+	var colorTolerance float64
+	var perceptualHashDistance int
+	var contextLines int
+	var highlight string
+	var htmlNormalization string
+	var htmlIgnoreRules string
 	flag.StringVar(&directory, "directory", envOrDefaultValue("DIRECTORY", "/tmp"), "Output directory")
-	flag.StringVar(&format, "format", envOrDefaultValue("FORMAT", "pixel"), "Output format (pixel, rectangle, line, dom)")
+	flag.StringVar(&format, "format", envOrDefaultValue("FORMAT", "pixel"), "Output format (pixel, rectangle, ssim, shift, line, dom, word, style)")
+	flag.BoolVar(&detectAntiAliasing, "detect-anti-aliasing", envOrDefaultValue("DETECT_ANTI_ALIASING", false), "Exclude pixels changed by anti-aliasing from the pixel diff")
+	flag.StringVar(&ignoreRectangles, "ignore-rectangles", envOrDefaultValue("IGNORE_RECTANGLES", ""), "Semicolon-separated list of x,y,width,height rectangles excluded from the screenshot diff (e.g., 0,0,1920,80;0,1000,300,80)")
+	flag.StringVar(&htmlNormalization, "html-normalization", envOrDefaultValue("HTML_NORMALIZATION", ""), "JSON encoded rules normalizing HTML before the line and dom diffs (prettyPrint, sortAttributes, collapseWhitespace, dropComments and replacements)")
+	flag.StringVar(&htmlIgnoreRules, "html-ignore", envOrDefaultValue("HTML_IGNORE", ""), "JSON encoded rules excluding parts of HTML from the line and dom diffs (selectors, attributes and lines)")
+	flag.IntVar(&contextLines, "context-lines", envOrDefaultValue("CONTEXT_LINES", 3), "Number of unchanged lines shown around each change of the line diff")
+	flag.StringVar(&highlight, "highlight", envOrDefaultValue("HIGHLIGHT", "word"), "How the line diff highlights the changes within modified lines (word or character)")
+	flag.IntVar(&perceptualHashDistance, "perceptual-hash-distance", envOrDefaultValue("PERCEPTUAL_HASH_DISTANCE", -1), "Perceptual hash distance in bits within which images are treated as unchanged (negative to disable)")
+	flag.StringVar(&colorDistance, "color-distance", envOrDefaultValue("COLOR_DISTANCE", "brightness"), "How the pixel diff compares colors (brightness or perceptual)")
+	flag.Float64Var(&colorTolerance, "color-tolerance", envOrDefaultValue("COLOR_TOLERANCE", 0.1), "Perceptual color distance (0.0 to 1.0) below which colors are considered equal")
+	flag.StringVar(&palette, "palette", envOrDefaultValue("PALETTE", "default"), "Colors of the screenshot diff rendering (default or color-blind)")
+	flag.IntVar(&strokeWidth, "stroke-width", envOrDefaultValue("STROKE_WIDTH", 3), "Width in pixels of the rectangles drawn by the rectangle diff")
+	flag.StringVar(&sizeMismatch, "size-mismatch", envOrDefaultValue("SIZE_MISMATCH", "union"), "How screenshots of different sizes are compared (union, crop-to-common, pad-with-color or scale-to-baseline)")
+	flag.StringVar(&padColor, "pad-color", envOrDefaultValue("PAD_COLOR", "#ffffff"), "Color in #rrggbb form that fills the missing area with the pad-with-color size mismatch policy")
 
 	flag.Parse()
 
@@ -87,6 +123,51 @@ func main() {
 		log.Fatalf("Failed to create storage backend: %v", err)
 	}
 
+	ignoreRegions, err := parseRectangles(ignoreRectangles)
+	if err != nil {
+		log.Fatalf("Failed to parse ignore rectangles: %v", err)
+	}
+	pad, err := diffimage.ParseHexColor(padColor)
+	if err != nil {
+		log.Fatalf("Failed to parse pad color: %v", err)
+	}
+	diffPalette, err := diffimage.PaletteByName(palette)
+	if err != nil {
+		log.Fatalf("Failed to select palette: %v", err)
+	}
+	diffOptions := []diffimage.Option{
+		diffimage.WithAntiAliasingDetection(detectAntiAliasing),
+		diffimage.WithIgnoreRegions(ignoreRegions...),
+		diffimage.WithSizeMismatch(sizeMismatch, pad),
+		diffimage.WithPalette(diffPalette),
+		diffimage.WithStrokeWidth(strokeWidth),
+	}
+	if perceptualHashDistance >= 0 {
+		diffOptions = append(diffOptions, diffimage.WithPerceptualHash(perceptualHashDistance))
+	}
+	if colorDistance == "perceptual" {
+		diffOptions = append(diffOptions, diffimage.WithPerceptualColorDistance(colorTolerance))
+	}
+
+	var htmlNormalizer *difftext.Normalizer
+	if htmlNormalization != "" {
+		htmlNormalizer = &difftext.Normalizer{}
+		if err := json.Unmarshal([]byte(htmlNormalization), htmlNormalizer); err != nil {
+			log.Fatalf("Failed to parse HTML normalization: %v", err)
+		}
+		if err := htmlNormalizer.Validate(); err != nil {
+			log.Fatalf("Invalid HTML normalization: %v", err)
+		}
+	}
+
+	var htmlIgnore *difftext.Ignore
+	if htmlIgnoreRules != "" {
+		htmlIgnore = &difftext.Ignore{}
+		if err := json.Unmarshal([]byte(htmlIgnoreRules), htmlIgnore); err != nil {
+			log.Fatalf("Failed to parse HTML ignore: %v", err)
+		}
+	}
+
 	baselinePath := args[0]
 	targetPath := args[1]
 
@@ -98,6 +179,12 @@ func main() {
 
 	var diffPath string
 	var diffAmount float64
+	var insertedBands []diffimage.Band
+	var removedBands []diffimage.Band
+	var regions []diffimage.ChangedRegion
+	var dimensionChange *diffimage.DimensionChange
+	var changes []difftext.Change
+	var rendered []byte
 	switch format {
 	case "pixel":
 		baselineImage, err := loadImage(baselinePath)
@@ -110,7 +197,7 @@ func main() {
 			log.Fatalf("Failed to load target image: %v", err)
 		}
 
-		diffResult := diffimage.NewPixelDiff(0.1).Calculate(baselineImage, targetImage)
+		diffResult := diffimage.NewPixelDiff(0.1, diffOptions...).Calculate(baselineImage, targetImage)
 
 		var buffer bytes.Buffer
 		if err := png.Encode(&buffer, diffResult.Image); err != nil {
@@ -123,6 +210,7 @@ func main() {
 			log.Fatalf("Failed to save diff image: %v", err)
 		}
 		diffAmount = diffResult.DiffAmount
+		dimensionChange = diffResult.DimensionChange
 	case "rectangle":
 		baselineImage, err := loadImage(baselinePath)
 		if err != nil {
@@ -134,7 +222,58 @@ func main() {
 			log.Fatalf("Failed to load target image: %v", err)
 		}
 
-		diffResult := diffimage.NewRectangleDiff().Calculate(baselineImage, targetImage)
+		diffResult := diffimage.NewRectangleDiff(diffOptions...).Calculate(baselineImage, targetImage)
+
+		var buffer bytes.Buffer
+		if err := png.Encode(&buffer, diffResult.Image); err != nil {
+			log.Fatalf("Failed to encode diff image: %v", err)
+		}
+
+		key := fmt.Sprintf("Snapshot/diff/%s/%s.png", hash, timestamp)
+		diffPath, err = s.Put(ctx, key, buffer.Bytes())
+		if err != nil {
+			log.Fatalf("Failed to save diff image: %v", err)
+		}
+		diffAmount = diffResult.DiffAmount
+		regions = diffResult.Regions
+		dimensionChange = diffResult.DimensionChange
+	case "ssim":
+		baselineImage, err := loadImage(baselinePath)
+		if err != nil {
+			log.Fatalf("Failed to load baseline image: %v", err)
+		}
+
+		targetImage, err := loadImage(targetPath)
+		if err != nil {
+			log.Fatalf("Failed to load target image: %v", err)
+		}
+
+		diffResult := diffimage.NewSSIMDiff(diffOptions...).Calculate(baselineImage, targetImage)
+
+		var buffer bytes.Buffer
+		if err := png.Encode(&buffer, diffResult.Image); err != nil {
+			log.Fatalf("Failed to encode diff image: %v", err)
+		}
+
+		key := fmt.Sprintf("Snapshot/diff/%s/%s.png", hash, timestamp)
+		diffPath, err = s.Put(ctx, key, buffer.Bytes())
+		if err != nil {
+			log.Fatalf("Failed to save diff image: %v", err)
+		}
+		diffAmount = diffResult.DiffAmount
+		dimensionChange = diffResult.DimensionChange
+	case "shift":
+		baselineImage, err := loadImage(baselinePath)
+		if err != nil {
+			log.Fatalf("Failed to load baseline image: %v", err)
+		}
+
+		targetImage, err := loadImage(targetPath)
+		if err != nil {
+			log.Fatalf("Failed to load target image: %v", err)
+		}
+
+		diffResult := diffimage.NewShiftDiff(0.1, diffOptions...).Calculate(baselineImage, targetImage)
 
 		var buffer bytes.Buffer
 		if err := png.Encode(&buffer, diffResult.Image); err != nil {
@@ -147,6 +286,9 @@ func main() {
 			log.Fatalf("Failed to save diff image: %v", err)
 		}
 		diffAmount = diffResult.DiffAmount
+		dimensionChange = diffResult.DimensionChange
+		insertedBands = diffResult.InsertedBands
+		removedBands = diffResult.RemovedBands
 	case "line":
 		baselineHTML, err := os.ReadFile(baselinePath)
 		if err != nil {
@@ -158,7 +300,18 @@ func main() {
 			log.Fatalf("Failed to read target HTML file: %v", err)
 		}
 
-		diffResult, err := difftext.NewLineDiff().Calculate(baselineHTML, targetHTML)
+		if htmlNormalizer != nil {
+			baselineHTML, err = htmlNormalizer.Normalize(baselineHTML)
+			if err != nil {
+				log.Fatalf("Failed to normalize baseline HTML: %v", err)
+			}
+			targetHTML, err = htmlNormalizer.Normalize(targetHTML)
+			if err != nil {
+				log.Fatalf("Failed to normalize target HTML: %v", err)
+			}
+		}
+
+		diffResult, err := difftext.NewLineDiff(difftext.WithContextLines(contextLines), difftext.WithLabels(baselinePath, targetPath), difftext.WithHighlight(highlight), difftext.WithIgnore(htmlIgnore)).Calculate(baselineHTML, targetHTML)
 		if err != nil {
 			log.Fatalf("Failed to calculate line diff: %v", err)
 		}
@@ -166,12 +319,14 @@ func main() {
 		var buffer bytes.Buffer
 		buffer.Write(diffResult.Diff)
 
-		key := fmt.Sprintf("Snapshot/diff/%s/%s.txt", hash, timestamp)
+		key := fmt.Sprintf("Snapshot/diff/%s/%s.diff", hash, timestamp)
 		diffPath, err = s.Put(ctx, key, buffer.Bytes())
 		if err != nil {
 			log.Fatalf("Failed to save diff image: %v", err)
 		}
 		diffAmount = diffResult.DiffAmount
+		changes = diffResult.Changes
+		rendered = diffResult.Rendered
 	case "dom":
 		baselineHTML, err := os.ReadFile(baselinePath)
 		if err != nil {
@@ -183,7 +338,18 @@ func main() {
 			log.Fatalf("Failed to read target HTML file: %v", err)
 		}
 
-		diffResult, err := difftext.NewDOMDiff().Calculate(baselineHTML, targetHTML)
+		if htmlNormalizer != nil {
+			baselineHTML, err = htmlNormalizer.Normalize(baselineHTML)
+			if err != nil {
+				log.Fatalf("Failed to normalize baseline HTML: %v", err)
+			}
+			targetHTML, err = htmlNormalizer.Normalize(targetHTML)
+			if err != nil {
+				log.Fatalf("Failed to normalize target HTML: %v", err)
+			}
+		}
+
+		diffResult, err := difftext.NewDOMDiff(difftext.WithDOMIgnore(htmlIgnore)).Calculate(baselineHTML, targetHTML)
 		if err != nil {
 			log.Fatalf("Failed to calculate DOM diff: %v", err)
 		}
@@ -191,6 +357,57 @@ func main() {
 		var buffer bytes.Buffer
 		buffer.Write(diffResult.Diff)
 
+		key := fmt.Sprintf("Snapshot/diff/%s/%s.txt", hash, timestamp)
+		diffPath, err = s.Put(ctx, key, buffer.Bytes())
+		if err != nil {
+			log.Fatalf("Failed to save diff file: %v", err)
+		}
+		diffAmount = diffResult.DiffAmount
+		changes = diffResult.Changes
+	case "word":
+		baselineText, err := os.ReadFile(baselinePath)
+		if err != nil {
+			log.Fatalf("Failed to read baseline text file: %v", err)
+		}
+
+		targetText, err := os.ReadFile(targetPath)
+		if err != nil {
+			log.Fatalf("Failed to read target text file: %v", err)
+		}
+
+		diffResult, err := difftext.NewWordDiff().Calculate(baselineText, targetText)
+		if err != nil {
+			log.Fatalf("Failed to calculate word diff: %v", err)
+		}
+
+		var buffer bytes.Buffer
+		buffer.Write(diffResult.Diff)
+
+		key := fmt.Sprintf("Snapshot/diff/%s/%s.txt", hash, timestamp)
+		diffPath, err = s.Put(ctx, key, buffer.Bytes())
+		if err != nil {
+			log.Fatalf("Failed to save diff file: %v", err)
+		}
+		diffAmount = diffResult.DiffAmount
+	case "style":
+		baselineStyles, err := os.ReadFile(baselinePath)
+		if err != nil {
+			log.Fatalf("Failed to read baseline styles file: %v", err)
+		}
+
+		targetStyles, err := os.ReadFile(targetPath)
+		if err != nil {
+			log.Fatalf("Failed to read target styles file: %v", err)
+		}
+
+		diffResult, err := difftext.NewStyleDiff().Calculate(baselineStyles, targetStyles)
+		if err != nil {
+			log.Fatalf("Failed to calculate style diff: %v", err)
+		}
+
+		var buffer bytes.Buffer
+		buffer.Write(diffResult.Diff)
+
 		key := fmt.Sprintf("Snapshot/diff/%s/%s.txt", hash, timestamp)
 		diffPath, err = s.Put(ctx, key, buffer.Bytes())
 		if err != nil {
@@ -201,9 +418,36 @@ func main() {
 		log.Fatalf("Unknown diff type: %s", format)
 	}
 
+	var changesPath string
+	if len(changes) > 0 {
+		data, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to marshal changes: %v", err)
+		}
+		changesPath, err = s.Put(ctx, fmt.Sprintf("Snapshot/diff/%s/%s-changes.json", hash, timestamp), data)
+		if err != nil {
+			log.Fatalf("Failed to save changes: %v", err)
+		}
+	}
+
+	var renderedPath string
+	if rendered != nil {
+		var err error
+		renderedPath, err = s.Put(ctx, fmt.Sprintf("Snapshot/diff/%s/%s.html", hash, timestamp), rendered)
+		if err != nil {
+			log.Fatalf("Failed to save rendered diff: %v", err)
+		}
+	}
+
 	if err := json.NewEncoder(os.Stdout).Encode(DiffOutput{
-		DiffPath:   diffPath,
-		DiffAmount: diffAmount,
+		DiffPath:        diffPath,
+		DiffAmount:      diffAmount,
+		InsertedBands:   insertedBands,
+		RemovedBands:    removedBands,
+		Regions:         regions,
+		DimensionChange: dimensionChange,
+		ChangesPath:     changesPath,
+		RenderedPath:    renderedPath,
 	}); err != nil {
 		log.Fatalf("Failed to encode result: %v", err)
 	}
@@ -223,3 +467,30 @@ func loadImage(path string) (image.Image, error) {
 
 	return i, nil
 }
+
+// parseRectangles parses a semicolon-separated list of x,y,width,height rectangles.
+func parseRectangles(value string) ([]diffimage.Rectangle, error) {
+	var rectangles []diffimage.Rectangle
+	for _, item := range strings.Split(value, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.Split(item, ",")
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("invalid rectangle %q: expected x,y,width,height", item)
+		}
+
+		values := make([]int, len(parts))
+		for i, part := range parts {
+			v, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return nil, fmt.Errorf("invalid rectangle %q: %w", item, err)
+			}
+			values[i] = v
+		}
+		rectangles = append(rectangles, diffimage.Rectangle{X: values[0], Y: values[1], Width: values[2], Height: values[3]})
+	}
+	return rectangles, nil
+}
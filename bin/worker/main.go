@@ -7,7 +7,10 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"image/jpeg"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
 	"log"
 	"net/http"
 	"os"
@@ -25,14 +28,57 @@ import (
 )
 
 type WorkerOutput struct {
-	BaselineURL          string  `json:"baselineURL"`
-	TargetURL            string  `json:"targetURL"`
-	BaselineHTMLURL      string  `json:"baselineHTMLURL"`
-	TargetHTMLURL        string  `json:"targetHTMLURL"`
-	ScreenshotDiffURL    string  `json:"screenshotDiffURL"`
-	ScreenshotDiffAmount float64 `json:"screenshotDiffAmount"`
-	HTMLDiffURL          string  `json:"htmlDiffURL"`
-	HTMLDiffAmount       float64 `json:"htmlDiffAmount"`
+	BaselineURL               string           `json:"baselineURL"`
+	TargetURL                 string           `json:"targetURL"`
+	BaselineHTMLURL           string           `json:"baselineHTMLURL"`
+	TargetHTMLURL             string           `json:"targetHTMLURL"`
+	BaselineTextURL           string           `json:"baselineTextURL"`
+	TargetTextURL             string           `json:"targetTextURL"`
+	ScreenshotDiffURL         string           `json:"screenshotDiffURL"`
+	ScreenshotDiffRegionsURL  string           `json:"screenshotDiffRegionsURL"`
+	ScreenshotDiffAmount      float64          `json:"screenshotDiffAmount"`
+	ScreenshotComposites      []Composite      `json:"screenshotComposites"`
+	ScreenshotInsertedBands   []Band           `json:"screenshotInsertedBands"`
+	ScreenshotRemovedBands    []Band           `json:"screenshotRemovedBands"`
+	ScreenshotDimensionChange *DimensionChange `json:"screenshotDimensionChange"`
+	HTMLDiffURL               string           `json:"htmlDiffURL"`
+	HTMLDiffChangesURL        string           `json:"htmlDiffChangesURL"`
+	HTMLDiffRenderedURL       string           `json:"htmlDiffRenderedURL"`
+	HTMLDiffAmount            float64          `json:"htmlDiffAmount"`
+	TextDiffURL               string           `json:"textDiffURL"`
+	TextDiffAmount            float64          `json:"textDiffAmount"`
+	BaselineStylesURL         string           `json:"baselineStylesURL"`
+	TargetStylesURL           string           `json:"targetStylesURL"`
+	StyleDiffURL              string           `json:"styleDiffURL"`
+	StyleDiffAmount           float64          `json:"styleDiffAmount"`
+	BaselinePDFURL            string           `json:"baselinePDFURL"`
+	TargetPDFURL              string           `json:"targetPDFURL"`
+	BaselinePageURLs          []string         `json:"baselinePageURLs"`
+	TargetPageURLs            []string         `json:"targetPageURLs"`
+	PageDiffs                 []PageDiff       `json:"pageDiffs"`
+}
+
+type Band struct {
+	Y      int `json:"y"`
+	Height int `json:"height"`
+}
+
+type DimensionChange struct {
+	BaselineWidth  int `json:"baselineWidth"`
+	BaselineHeight int `json:"baselineHeight"`
+	TargetWidth    int `json:"targetWidth"`
+	TargetHeight   int `json:"targetHeight"`
+}
+
+type Composite struct {
+	Format string `json:"format"`
+	URL    string `json:"url"`
+}
+
+type PageDiff struct {
+	Page       int     `json:"page"`
+	DiffURL    string  `json:"diffUrl"`
+	DiffAmount float64 `json:"diffAmount"`
 }
 
 type headers []string
@@ -47,10 +93,27 @@ func (h *headers) Set(value string) error {
 }
 
 type Worker struct {
-	Capturer             capture.Capturer
-	Storage              storage.Storage
-	ScreenshotDiffFormat string
-	HTMLDiffFormat       string
+	Capturer               capture.Capturer
+	Storage                storage.Storage
+	ScreenshotFormat       string
+	ScreenshotDiffFormat   string
+	HTMLDiffFormat         string
+	HTMLDiffContextLines   int
+	HTMLDiffHighlight      string
+	HTMLNormalizer         *difftext.Normalizer
+	HTMLIgnore             *difftext.Ignore
+	TextDiffFormat         string
+	DetectAntiAliasing     bool
+	IgnoreRectangles       []diffimage.Rectangle
+	ScreenshotComposites   []string
+	DiffMemoryBudget       int
+	SizeMismatch           string
+	PadColor               color.Color
+	Palette                diffimage.Palette
+	StrokeWidth            int
+	ColorDistance          string
+	ColorTolerance         float64
+	PerceptualHashDistance int
 }
 
 func envOrDefaultValue[T any](key string, defaultValue T) T {
@@ -100,23 +163,69 @@ func main() {
 
 	var screenshotFormat string
 	var maskSelectors string
+	var styleSelectors string
+	var ignoreSelectors string
+	var ignoreRectangles string
+	var styleProperties string
+	var captureMode string
+	var pdfFormat string
+	var pdfLandscape bool
+	var pdfMargin string
 	var delay time.Duration
 	var viewportWidth int
 	var viewportHeight int
 	var chromeDevtoolsProtocolURL string
 	var screenshotDiffFormat string
 	var htmlDiffFormat string
+	var htmlDiffContextLines int
+	var htmlDiffHighlight string
+	var htmlNormalization string
+	var htmlIgnoreRules string
+	var textDiffFormat string
+	var detectAntiAliasing bool
+	var screenshotComposites string
+	var diffMemoryBudget int
+	var sizeMismatch string
+	var padColor string
+	var palette string
+	var strokeWidth int
+	var colorDistance string
+	var colorTolerance float64
+	var perceptualHashDistance int
 	var storageBackend string
 	var callbackURL string
 	var headers headers
 	flag.StringVar(&screenshotFormat, "screenshot-format", envOrDefaultValue("SCREENSHOT_FORMAT", "jpeg"), "Screenshot format (jpeg or png)")
 	flag.StringVar(&maskSelectors, "mask-selectors", envOrDefaultValue("MASK_SELECTORS", ""), "Comma-separated list of CSS selectors to mask during capture")
+	flag.StringVar(&styleSelectors, "style-selectors", envOrDefaultValue("STYLE_SELECTORS", ""), "JSON encoded list of CSS selectors whose computed styles are captured, such as [\"h1, h2\"]")
+	flag.StringVar(&styleProperties, "style-properties", envOrDefaultValue("STYLE_PROPERTIES", ""), "Comma-separated list of computed style properties to capture (all properties when empty)")
+	flag.StringVar(&captureMode, "capture-mode", envOrDefaultValue("CAPTURE_MODE", "screen"), "Capture mode (screen, pdf or http)")
+	flag.StringVar(&pdfFormat, "pdf-format", envOrDefaultValue("PDF_FORMAT", "A4"), "Paper format of the pdf capture mode (e.g., A4 or Letter)")
+	flag.BoolVar(&pdfLandscape, "pdf-landscape", envOrDefaultValue("PDF_LANDSCAPE", false), "Print in landscape orientation in the pdf capture mode")
+	flag.StringVar(&pdfMargin, "pdf-margin", envOrDefaultValue("PDF_MARGIN", ""), "Comma-separated paper margins of the pdf capture mode in top,right,bottom,left order (e.g., 10mm,10mm,10mm,10mm)")
 	flag.DurationVar(&delay, "delay", envOrDefaultValue("DELAY", 3*time.Second), "Delay before capturing")
 	flag.IntVar(&viewportWidth, "viewport-width", envOrDefaultValue("VIEWPORT_WIDTH", 1920), "Viewport width in pixels")
 	flag.IntVar(&viewportHeight, "viewport-height", envOrDefaultValue("VIEWPORT_HEIGHT", 1080), "Viewport height in pixels")
 	flag.StringVar(&chromeDevtoolsProtocolURL, "chrome-devtools-protocol-url", envOrDefaultValue("CHROME_DEVTOOLS_PROTOCOL_URL", ""), "Connect to existing browser via Chrome DevTools Protocol URL (e.g., http://localhost:9222)")
-	flag.StringVar(&screenshotDiffFormat, "screenshot-diff-format", envOrDefaultValue("SCREENSHOT_DIFF_FORMAT", "pixel"), "Diff format (pixel or rectangle)")
-	flag.StringVar(&htmlDiffFormat, "html-diff-format", envOrDefaultValue("HTML_DIFF_FORMAT", "line"), "Diff format (line)")
+	flag.StringVar(&screenshotDiffFormat, "screenshot-diff-format", envOrDefaultValue("SCREENSHOT_DIFF_FORMAT", "pixel"), "Diff format (pixel, rectangle, ssim or shift)")
+	flag.StringVar(&htmlDiffFormat, "html-diff-format", envOrDefaultValue("HTML_DIFF_FORMAT", "line"), "Diff format (line or dom)")
+	flag.StringVar(&htmlNormalization, "html-normalization", envOrDefaultValue("HTML_NORMALIZATION", ""), "JSON encoded rules normalizing HTML before the HTML diff (prettyPrint, sortAttributes, collapseWhitespace, dropComments and replacements)")
+	flag.StringVar(&htmlIgnoreRules, "html-ignore", envOrDefaultValue("HTML_IGNORE", ""), "JSON encoded rules excluding parts of HTML from the HTML diff (selectors, attributes and lines)")
+	flag.IntVar(&htmlDiffContextLines, "html-diff-context-lines", envOrDefaultValue("HTML_DIFF_CONTEXT_LINES", 3), "Number of unchanged lines shown around each change of the line HTML diff")
+	flag.StringVar(&htmlDiffHighlight, "html-diff-highlight", envOrDefaultValue("HTML_DIFF_HIGHLIGHT", "word"), "How the line HTML diff highlights the changes within modified lines (word or character)")
+	flag.StringVar(&textDiffFormat, "text-diff-format", envOrDefaultValue("TEXT_DIFF_FORMAT", "word"), "Visible text diff format (word)")
+	flag.StringVar(&ignoreSelectors, "ignore-selectors", envOrDefaultValue("IGNORE_SELECTORS", ""), "JSON encoded list of CSS selectors whose bounding boxes are excluded from the screenshot diff, such as [\".ad, .banner\"]")
+	flag.StringVar(&ignoreRectangles, "ignore-rectangles", envOrDefaultValue("IGNORE_RECTANGLES", ""), "Semicolon-separated list of x,y,width,height rectangles excluded from the screenshot diff (e.g., 0,0,1920,80;0,1000,300,80)")
+	flag.BoolVar(&detectAntiAliasing, "detect-anti-aliasing", envOrDefaultValue("DETECT_ANTI_ALIASING", false), "Exclude pixels changed by anti-aliasing from the pixel diff")
+	flag.StringVar(&screenshotComposites, "screenshot-composites", envOrDefaultValue("SCREENSHOT_COMPOSITES", ""), "Comma-separated list of additional renderings of the screenshot diff (side-by-side, overlay, onion-skin or blink)")
+	flag.IntVar(&perceptualHashDistance, "perceptual-hash-distance", envOrDefaultValue("PERCEPTUAL_HASH_DISTANCE", -1), "Perceptual hash distance in bits within which screenshots are treated as unchanged (negative to disable)")
+	flag.StringVar(&colorDistance, "color-distance", envOrDefaultValue("COLOR_DISTANCE", "brightness"), "How the pixel diff compares colors (brightness or perceptual)")
+	flag.Float64Var(&colorTolerance, "color-tolerance", envOrDefaultValue("COLOR_TOLERANCE", 0.1), "Perceptual color distance (0.0 to 1.0) below which colors are considered equal")
+	flag.StringVar(&palette, "screenshot-diff-palette", envOrDefaultValue("SCREENSHOT_DIFF_PALETTE", "default"), "Colors of the screenshot diff rendering (default or color-blind)")
+	flag.IntVar(&strokeWidth, "screenshot-diff-stroke-width", envOrDefaultValue("SCREENSHOT_DIFF_STROKE_WIDTH", 3), "Width in pixels of the rectangles drawn by the rectangle screenshot diff")
+	flag.StringVar(&sizeMismatch, "size-mismatch", envOrDefaultValue("SIZE_MISMATCH", "union"), "How screenshots of different sizes are compared (union, crop-to-common, pad-with-color or scale-to-baseline)")
+	flag.StringVar(&padColor, "pad-color", envOrDefaultValue("PAD_COLOR", "#ffffff"), "Color in #rrggbb form that fills the missing area with the pad-with-color size mismatch policy")
+	flag.IntVar(&diffMemoryBudget, "diff-memory-budget", envOrDefaultValue("DIFF_MEMORY_BUDGET", 0), "Bytes allocated for comparing screenshots, excluding the decoded screenshots themselves, above which they are compared in strips (0 for no budget)")
 	flag.StringVar(&storageBackend, "storage-backend", envOrDefaultValue("STORAGE_BACKEND", "file"), "Storage backend (file or s3)")
 	flag.StringVar(&callbackURL, "callback-url", envOrDefaultValue("CALLBACK_URL", ""), "Callback URL to send results to")
 	flag.Var(&headers, "H", "Add HTTP header (can be used multiple times, e.g., -H 'Accept: text/html' -H 'Authorization: Bearer token')")
@@ -153,7 +262,13 @@ func main() {
 		config.ViewportHeight = viewportHeight
 	}
 
-	capturer, err := capture.NewPlaywrightCapturer(ctx, config)
+	var capturer capture.Capturer
+	var err error
+	if captureMode == "http" {
+		capturer, err = capture.NewHTTPCapturer(ctx, capture.DefaultHTTPConfig())
+	} else {
+		capturer, err = capture.NewPlaywrightCapturer(ctx, config)
+	}
 	if err != nil {
 		log.Fatalf("failed to initialize capturer: %v", err)
 	}
@@ -165,6 +280,43 @@ func main() {
 			captureOptions.MaskSelectors[i] = strings.TrimSpace(captureOptions.MaskSelectors[i])
 		}
 	}
+	if styleSelectors != "" {
+		// Selectors may contain commas, so they are passed as JSON instead of a comma-separated list.
+		if err := json.Unmarshal([]byte(styleSelectors), &captureOptions.StyleSelectors); err != nil {
+			log.Fatalf("failed to parse style selectors: %v", err)
+		}
+	}
+	if ignoreSelectors != "" {
+		// Selectors may contain commas, so they are passed as JSON instead of a comma-separated list.
+		if err := json.Unmarshal([]byte(ignoreSelectors), &captureOptions.IgnoreSelectors); err != nil {
+			log.Fatalf("failed to parse ignore selectors: %v", err)
+		}
+	}
+	if styleProperties != "" {
+		captureOptions.StyleProperties = strings.Split(styleProperties, ",")
+		for i := range captureOptions.StyleProperties {
+			captureOptions.StyleProperties[i] = strings.TrimSpace(captureOptions.StyleProperties[i])
+		}
+	}
+	if captureMode == "pdf" {
+		captureOptions.PDF = &capture.PDFOptions{
+			Format:    pdfFormat,
+			Landscape: pdfLandscape,
+		}
+		if pdfMargin != "" {
+			margins := strings.Split(pdfMargin, ",")
+			for i := range margins {
+				margins[i] = strings.TrimSpace(margins[i])
+			}
+			for len(margins) < 4 {
+				margins = append(margins, "")
+			}
+			captureOptions.PDF.MarginTop = margins[0]
+			captureOptions.PDF.MarginRight = margins[1]
+			captureOptions.PDF.MarginBottom = margins[2]
+			captureOptions.PDF.MarginLeft = margins[3]
+		}
+	}
 	if len(headers) > 0 {
 		for _, header := range headers {
 			parts := strings.SplitN(header, ":", 2)
@@ -184,6 +336,28 @@ func main() {
 		}
 	}
 
+	var htmlNormalizer *difftext.Normalizer
+	if htmlNormalization != "" {
+		htmlNormalizer = &difftext.Normalizer{}
+		if err := json.Unmarshal([]byte(htmlNormalization), htmlNormalizer); err != nil {
+			log.Fatalf("failed to parse HTML normalization: %v", err)
+		}
+		if err := htmlNormalizer.Validate(); err != nil {
+			log.Fatalf("invalid HTML normalization: %v", err)
+		}
+	}
+
+	var htmlIgnore *difftext.Ignore
+	if htmlIgnoreRules != "" {
+		htmlIgnore = &difftext.Ignore{}
+		if err := json.Unmarshal([]byte(htmlIgnoreRules), htmlIgnore); err != nil {
+			log.Fatalf("failed to parse HTML ignore: %v", err)
+		}
+		if err := htmlIgnore.Validate(); err != nil {
+			log.Fatalf("invalid HTML ignore: %v", err)
+		}
+	}
+
 	var s storage.Storage
 	switch storageBackend {
 	case "file":
@@ -203,10 +377,45 @@ func main() {
 	}
 
 	worker := &Worker{
-		Capturer:             capturer,
-		Storage:              s,
-		ScreenshotDiffFormat: screenshotDiffFormat,
-		HTMLDiffFormat:       htmlDiffFormat,
+		Capturer:               capturer,
+		Storage:                s,
+		ScreenshotFormat:       screenshotFormat,
+		ScreenshotDiffFormat:   screenshotDiffFormat,
+		HTMLDiffFormat:         htmlDiffFormat,
+		HTMLDiffContextLines:   htmlDiffContextLines,
+		HTMLDiffHighlight:      htmlDiffHighlight,
+		HTMLNormalizer:         htmlNormalizer,
+		HTMLIgnore:             htmlIgnore,
+		TextDiffFormat:         textDiffFormat,
+		DetectAntiAliasing:     detectAntiAliasing,
+		DiffMemoryBudget:       diffMemoryBudget,
+		SizeMismatch:           sizeMismatch,
+		StrokeWidth:            strokeWidth,
+		ColorDistance:          colorDistance,
+		ColorTolerance:         colorTolerance,
+		PerceptualHashDistance: perceptualHashDistance,
+	}
+	worker.Palette, err = diffimage.PaletteByName(palette)
+	if err != nil {
+		log.Fatalf("failed to select palette: %v", err)
+	}
+	if padColor != "" {
+		worker.PadColor, err = diffimage.ParseHexColor(padColor)
+		if err != nil {
+			log.Fatalf("failed to parse pad color: %v", err)
+		}
+	}
+	if ignoreRectangles != "" {
+		worker.IgnoreRectangles, err = parseRectangles(ignoreRectangles)
+		if err != nil {
+			log.Fatalf("failed to parse ignore rectangles: %v", err)
+		}
+	}
+	if screenshotComposites != "" {
+		worker.ScreenshotComposites = strings.Split(screenshotComposites, ",")
+		for i := range worker.ScreenshotComposites {
+			worker.ScreenshotComposites[i] = strings.TrimSpace(worker.ScreenshotComposites[i])
+		}
 	}
 
 	result, err := worker.processSnapshot(ctx, baseline, target, captureOptions)
@@ -260,64 +469,183 @@ func (w *Worker) processSnapshot(ctx context.Context, baseline string, target st
 	}
 
 	// Step 2: Generate diff image
-	diffImage, diffAmount, err := w.generateDiff(baselineResult.Screenshot, targetResult.Screenshot, w.ScreenshotDiffFormat)
-	if err != nil {
-		return nil, xerrors.Errorf("failed to generate diff: %w", err)
+	var diffImage []byte
+	var diffAmount float64
+	var diffRegions []byte
+	var composites []renderedComposite
+	var insertedBands []Band
+	var removedBands []Band
+	var dimensionChange *DimensionChange
+	var err error
+	if baselineResult.Screenshot != nil && targetResult.Screenshot != nil {
+		var baselineImage, targetImage image.Image
+		baselineImage, targetImage, err = w.decodeScreenshots(baselineResult.Screenshot, targetResult.Screenshot)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decode screenshots: %w", err)
+		}
+		var diffResult *diffimage.DiffResult
+		diffImage, diffResult, err = w.generateDiff(baselineImage, targetImage, w.ScreenshotDiffFormat, baselineResult.ScreenshotFormat, w.diffOptions(baselineResult.IgnoreRegions, targetResult.IgnoreRegions)...)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to generate diff: %w", err)
+		}
+		diffAmount = diffResult.DiffAmount
+		insertedBands = w.bands(diffResult.InsertedBands)
+		removedBands = w.bands(diffResult.RemovedBands)
+		dimensionChange = w.dimensionChange(diffResult.DimensionChange)
+		if len(diffResult.Regions) > 0 {
+			diffRegions, err = json.MarshalIndent(diffResult.Regions, "", "  ")
+			if err != nil {
+				return nil, xerrors.Errorf("failed to marshal diff regions: %w", err)
+			}
+		}
+		composites, err = w.generateComposites(baselineImage, targetImage, diffResult.Image, baselineResult.ScreenshotFormat)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to generate composites: %w", err)
+		}
 	}
 
 	// Step 2.5: Generate HTML diff
-	htmlDiff, htmlDiffAmount, err := w.generateHTMLDiff(baselineResult.HTML, targetResult.HTML, w.HTMLDiffFormat)
+	htmlDiffResult, err := w.generateHTMLDiff(baselineResult.HTML, targetResult.HTML, w.HTMLDiffFormat, baseline, target)
 	if err != nil {
 		return nil, xerrors.Errorf("failed to generate HTML diff: %w", err)
 	}
+	htmlDiff, htmlDiffAmount, htmlDiffRendered := htmlDiffResult.Diff, htmlDiffResult.DiffAmount, htmlDiffResult.Rendered
+	var htmlDiffChanges []byte
+	if len(htmlDiffResult.Changes) > 0 {
+		htmlDiffChanges, err = json.MarshalIndent(htmlDiffResult.Changes, "", "  ")
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshal HTML diff changes: %w", err)
+		}
+	}
+
+	// Step 2.6: Generate visible text diff
+	var textDiff []byte
+	var textDiffAmount float64
+	if baselineResult.Text != nil && targetResult.Text != nil {
+		textDiff, textDiffAmount, err = w.generateTextDiff(baselineResult.Text, targetResult.Text, w.TextDiffFormat)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to generate text diff: %w", err)
+		}
+	}
+
+	// Step 2.7: Generate computed style diff
+	var styleDiff []byte
+	var styleDiffAmount float64
+	if baselineResult.Styles != nil && targetResult.Styles != nil {
+		styleDiff, styleDiffAmount, err = w.generateStyleDiff(baselineResult.Styles, targetResult.Styles)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to generate style diff: %w", err)
+		}
+	}
+
+	// Step 2.8: Generate diff images of printed pages
+	var pageDiffImages [][]byte
+	var pageDiffs []PageDiff
+	if baselineResult.Pages != nil && targetResult.Pages != nil {
+		pageDiffImages, pageDiffs, err = w.generatePageDiffs(baselineResult.Pages, targetResult.Pages, w.ScreenshotDiffFormat, baselineResult.ScreenshotFormat, w.diffOptions()...)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to generate page diffs: %w", err)
+		}
+	}
 
 	// Step 3: Upload all images in parallel
-	output := &WorkerOutput{}
+	output := &WorkerOutput{
+		PageDiffs:            pageDiffs,
+		ScreenshotComposites: make([]Composite, len(composites)),
+	}
 	{
 		eg, ctx := errgroup.WithContext(ctx)
 
 		eg.Go(func() error {
-			imageURL, htmlURL, err := w.uploadCapture(ctx, baseline, baselineResult)
+			urls, err := w.uploadCapture(ctx, baseline, baselineResult)
 			if err != nil {
 				return err
 			}
-			output.BaselineURL = imageURL
-			output.BaselineHTMLURL = htmlURL
+			output.BaselineURL = urls.screenshotURL
+			output.BaselineHTMLURL = urls.htmlURL
+			output.BaselineTextURL = urls.textURL
+			output.BaselineStylesURL = urls.stylesURL
+			output.BaselinePDFURL = urls.pdfURL
+			output.BaselinePageURLs = urls.pageURLs
 			return nil
 		})
 
 		eg.Go(func() error {
-			imageURL, htmlURL, err := w.uploadCapture(ctx, target, targetResult)
+			urls, err := w.uploadCapture(ctx, target, targetResult)
 			if err != nil {
 				return err
 			}
-			output.TargetURL = imageURL
-			output.TargetHTMLURL = htmlURL
+			output.TargetURL = urls.screenshotURL
+			output.TargetHTMLURL = urls.htmlURL
+			output.TargetTextURL = urls.textURL
+			output.TargetStylesURL = urls.stylesURL
+			output.TargetPDFURL = urls.pdfURL
+			output.TargetPageURLs = urls.pageURLs
 			return nil
 		})
 
-		eg.Go(func() error {
-			timestamp := time.Now().Format("20060102150405")
-			h := sha256.New()
-			h.Write([]byte(baseline + target))
-			hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
-			diffKey := fmt.Sprintf("Snapshot/diff/%s/%s.jpeg", hash, timestamp)
+		if diffImage != nil {
+			eg.Go(func() error {
+				timestamp := time.Now().Format("20060102150405")
+				h := sha256.New()
+				h.Write([]byte(baseline + target))
+				hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
+				diffKey := fmt.Sprintf("Snapshot/diff/%s/%s.%s", hash, timestamp, diffimage.Extension(baselineResult.ScreenshotFormat))
 
-			url, err := w.Storage.Put(ctx, diffKey, diffImage)
-			if err != nil {
-				return xerrors.Errorf("failed to upload diff image: %w", err)
-			}
-			output.ScreenshotDiffURL = url
-			output.ScreenshotDiffAmount = diffAmount
-			return nil
-		})
+				url, err := w.Storage.Put(ctx, diffKey, diffImage)
+				if err != nil {
+					return xerrors.Errorf("failed to upload diff image: %w", err)
+				}
+				output.ScreenshotDiffURL = url
+				output.ScreenshotDiffAmount = diffAmount
+				output.ScreenshotInsertedBands = insertedBands
+				output.ScreenshotRemovedBands = removedBands
+				output.ScreenshotDimensionChange = dimensionChange
+				return nil
+			})
+		}
+
+		if diffRegions != nil {
+			eg.Go(func() error {
+				timestamp := time.Now().Format("20060102150405")
+				h := sha256.New()
+				h.Write([]byte(baseline + target))
+				hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
+				diffRegionsKey := fmt.Sprintf("Snapshot/diff/%s/%s-regions.json", hash, timestamp)
+
+				url, err := w.Storage.Put(ctx, diffRegionsKey, diffRegions)
+				if err != nil {
+					return xerrors.Errorf("failed to upload diff regions: %w", err)
+				}
+				output.ScreenshotDiffRegionsURL = url
+				return nil
+			})
+		}
+
+		for i, composite := range composites {
+			output.ScreenshotComposites[i].Format = composite.format
+			eg.Go(func() error {
+				timestamp := time.Now().Format("20060102150405")
+				h := sha256.New()
+				h.Write([]byte(baseline + target))
+				hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
+				compositeKey := fmt.Sprintf("Snapshot/diff/%s/%s-%s.%s", hash, timestamp, composite.format, composite.extension)
+
+				url, err := w.Storage.Put(ctx, compositeKey, composite.data)
+				if err != nil {
+					return xerrors.Errorf("failed to upload %s composite: %w", composite.format, err)
+				}
+				output.ScreenshotComposites[i].URL = url
+				return nil
+			})
+		}
 
 		eg.Go(func() error {
 			timestamp := time.Now().Format("20060102150405")
 			h := sha256.New()
 			h.Write([]byte(baseline + target))
 			hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
-			htmlDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s.txt", hash, timestamp)
+			htmlDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s.%s", hash, timestamp, difftext.Extension(w.HTMLDiffFormat))
 
 			url, err := w.Storage.Put(ctx, htmlDiffKey, htmlDiff)
 			if err != nil {
@@ -328,6 +656,96 @@ func (w *Worker) processSnapshot(ctx context.Context, baseline string, target st
 			return nil
 		})
 
+		if htmlDiffChanges != nil {
+			eg.Go(func() error {
+				timestamp := time.Now().Format("20060102150405")
+				h := sha256.New()
+				h.Write([]byte(baseline + target))
+				hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
+				htmlDiffChangesKey := fmt.Sprintf("Snapshot/diff/%s/%s-changes.json", hash, timestamp)
+
+				url, err := w.Storage.Put(ctx, htmlDiffChangesKey, htmlDiffChanges)
+				if err != nil {
+					return xerrors.Errorf("failed to upload HTML diff changes: %w", err)
+				}
+				output.HTMLDiffChangesURL = url
+				return nil
+			})
+		}
+
+		if htmlDiffRendered != nil {
+			eg.Go(func() error {
+				timestamp := time.Now().Format("20060102150405")
+				h := sha256.New()
+				h.Write([]byte(baseline + target))
+				hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
+				htmlDiffRenderedKey := fmt.Sprintf("Snapshot/diff/%s/%s.html", hash, timestamp)
+
+				url, err := w.Storage.Put(ctx, htmlDiffRenderedKey, htmlDiffRendered)
+				if err != nil {
+					return xerrors.Errorf("failed to upload rendered HTML diff: %w", err)
+				}
+				output.HTMLDiffRenderedURL = url
+				return nil
+			})
+		}
+
+		if textDiff != nil {
+			eg.Go(func() error {
+				timestamp := time.Now().Format("20060102150405")
+				h := sha256.New()
+				h.Write([]byte(baseline + target))
+				hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
+				textDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s-text.txt", hash, timestamp)
+
+				url, err := w.Storage.Put(ctx, textDiffKey, textDiff)
+				if err != nil {
+					return xerrors.Errorf("failed to upload text diff: %w", err)
+				}
+				output.TextDiffURL = url
+				output.TextDiffAmount = textDiffAmount
+				return nil
+			})
+		}
+
+		if styleDiff != nil {
+			eg.Go(func() error {
+				timestamp := time.Now().Format("20060102150405")
+				h := sha256.New()
+				h.Write([]byte(baseline + target))
+				hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
+				styleDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s-styles.txt", hash, timestamp)
+
+				url, err := w.Storage.Put(ctx, styleDiffKey, styleDiff)
+				if err != nil {
+					return xerrors.Errorf("failed to upload style diff: %w", err)
+				}
+				output.StyleDiffURL = url
+				output.StyleDiffAmount = styleDiffAmount
+				return nil
+			})
+		}
+
+		for i, pageDiffImage := range pageDiffImages {
+			if pageDiffImage == nil {
+				continue
+			}
+			eg.Go(func() error {
+				timestamp := time.Now().Format("20060102150405")
+				h := sha256.New()
+				h.Write([]byte(baseline + target))
+				hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
+				pageDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s-page-%d.%s", hash, timestamp, i+1, diffimage.Extension(baselineResult.ScreenshotFormat))
+
+				url, err := w.Storage.Put(ctx, pageDiffKey, pageDiffImage)
+				if err != nil {
+					return xerrors.Errorf("failed to upload diff image of page %d: %w", i+1, err)
+				}
+				output.PageDiffs[i].DiffURL = url
+				return nil
+			})
+		}
+
 		if err := eg.Wait(); err != nil {
 			return nil, err
 		}
@@ -336,9 +754,25 @@ func (w *Worker) processSnapshot(ctx context.Context, baseline string, target st
 	return output, nil
 }
 
-func (w *Worker) uploadCapture(ctx context.Context, url string, result *capture.CaptureResult) (string, string, error) {
-	var imageURL string
-	var htmlURL string
+// renderedComposite is an encoded composite of the screenshot diff.
+type renderedComposite struct {
+	format    string
+	extension string
+	data      []byte
+}
+
+// captureURLs holds the storage URLs of the artifacts of a single capture.
+type captureURLs struct {
+	screenshotURL string
+	htmlURL       string
+	textURL       string
+	stylesURL     string
+	pdfURL        string
+	pageURLs      []string
+}
+
+func (w *Worker) uploadCapture(ctx context.Context, url string, result *capture.CaptureResult) (*captureURLs, error) {
+	urls := &captureURLs{}
 	{
 		eg, ctx := errgroup.WithContext(ctx)
 
@@ -350,15 +784,17 @@ func (w *Worker) uploadCapture(ctx context.Context, url string, result *capture.
 
 		baseKey := fmt.Sprintf("Snapshot/capture/%s/%s", urlHash, timestamp)
 
-		eg.Go(func() error {
-			imageKey := baseKey + ".jpeg"
-			path, err := w.Storage.Put(ctx, imageKey, result.Screenshot)
-			if err != nil {
-				return xerrors.Errorf("failed to upload screenshot: %w", err)
-			}
-			imageURL = path
-			return nil
-		})
+		if result.Screenshot != nil {
+			eg.Go(func() error {
+				imageKey := baseKey + "." + diffimage.Extension(result.ScreenshotFormat)
+				path, err := w.Storage.Put(ctx, imageKey, result.Screenshot)
+				if err != nil {
+					return xerrors.Errorf("failed to upload screenshot: %w", err)
+				}
+				urls.screenshotURL = path
+				return nil
+			})
+		}
 
 		eg.Go(func() error {
 			htmlKey := baseKey + ".html"
@@ -366,67 +802,291 @@ func (w *Worker) uploadCapture(ctx context.Context, url string, result *capture.
 			if err != nil {
 				return xerrors.Errorf("failed to upload HTML: %w", err)
 			}
-			htmlURL = path
+			urls.htmlURL = path
 			return nil
 		})
 
+		if result.Text != nil {
+			eg.Go(func() error {
+				textKey := baseKey + ".txt"
+				path, err := w.Storage.Put(ctx, textKey, result.Text)
+				if err != nil {
+					return xerrors.Errorf("failed to upload text: %w", err)
+				}
+				urls.textURL = path
+				return nil
+			})
+		}
+
+		if result.Styles != nil {
+			eg.Go(func() error {
+				stylesKey := baseKey + "-styles.json"
+				path, err := w.Storage.Put(ctx, stylesKey, result.Styles)
+				if err != nil {
+					return xerrors.Errorf("failed to upload styles: %w", err)
+				}
+				urls.stylesURL = path
+				return nil
+			})
+		}
+
+		if result.PDF != nil {
+			eg.Go(func() error {
+				pdfKey := baseKey + ".pdf"
+				path, err := w.Storage.Put(ctx, pdfKey, result.PDF)
+				if err != nil {
+					return xerrors.Errorf("failed to upload PDF: %w", err)
+				}
+				urls.pdfURL = path
+				return nil
+			})
+		}
+
+		if result.Pages != nil {
+			urls.pageURLs = make([]string, len(result.Pages))
+		}
+		for i, page := range result.Pages {
+			eg.Go(func() error {
+				pageKey := fmt.Sprintf("%s-page-%d.%s", baseKey, i+1, diffimage.Extension(result.ScreenshotFormat))
+				path, err := w.Storage.Put(ctx, pageKey, page)
+				if err != nil {
+					return xerrors.Errorf("failed to upload page %d: %w", i+1, err)
+				}
+				urls.pageURLs[i] = path
+				return nil
+			})
+		}
+
 		if err := eg.Wait(); err != nil {
-			return "", "", err
+			return nil, err
 		}
 	}
 
-	return imageURL, htmlURL, nil
+	return urls, nil
 }
 
-func (w *Worker) generateDiff(baselineData []byte, targetData []byte, format string) ([]byte, float64, error) {
-	baselineImage, err := jpeg.Decode(bytes.NewReader(baselineData))
+// decodeScreenshots decodes the screenshots once for the diff and the composites.
+func (w *Worker) decodeScreenshots(baselineData []byte, targetData []byte) (image.Image, image.Image, error) {
+	baselineImage, _, err := image.Decode(bytes.NewReader(baselineData))
 	if err != nil {
-		return nil, 0.0, xerrors.Errorf("failed to decode baseline image: %w", err)
+		return nil, nil, xerrors.Errorf("failed to decode baseline image: %w", err)
 	}
 
-	targetImage, err := jpeg.Decode(bytes.NewReader(targetData))
-	if err != nil {
-		return nil, 0.0, xerrors.Errorf("failed to decode target image: %w", err)
+	// Byte-identical screenshots share the decoded image, which the differs return as is without comparing pixels.
+	targetImage := baselineImage
+	if !bytes.Equal(baselineData, targetData) {
+		targetImage, _, err = image.Decode(bytes.NewReader(targetData))
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to decode target image: %w", err)
+		}
 	}
+	return baselineImage, targetImage, nil
+}
 
+func (w *Worker) generateDiff(baselineImage image.Image, targetImage image.Image, format string, screenshotFormat string, opts ...diffimage.Option) ([]byte, *diffimage.DiffResult, error) {
 	var differ diffimage.Differ
 	switch format {
 	case "rectangle":
-		differ = diffimage.NewRectangleDiff()
+		differ = diffimage.NewRectangleDiff(opts...)
+	case "ssim":
+		differ = diffimage.NewSSIMDiff(opts...)
+	case "shift":
+		differ = diffimage.NewShiftDiff(0.1, opts...)
 	case "pixel":
-		differ = diffimage.NewPixelDiff(0.1)
+		differ = diffimage.NewPixelDiff(0.1, opts...)
 	default:
-		return nil, 0.0, xerrors.Errorf("unknown diff format: %s", format)
+		return nil, nil, xerrors.Errorf("unknown diff format: %s", format)
 	}
 
 	diffResult := differ.Calculate(baselineImage, targetImage)
 
-	var buffer bytes.Buffer
-	err = jpeg.Encode(&buffer, diffResult.Image, &jpeg.Options{Quality: 90})
+	diffImage, err := diffimage.Encode(diffResult.Image, screenshotFormat)
 	if err != nil {
-		return nil, 0.0, xerrors.Errorf("failed to encode diff image: %w", err)
+		return nil, nil, xerrors.Errorf("failed to encode diff image: %w", err)
+	}
+
+	return diffImage, diffResult, nil
+}
+
+func (w *Worker) generateComposites(baselineImage image.Image, targetImage image.Image, diffImage image.Image, screenshotFormat string) ([]renderedComposite, error) {
+	if len(w.ScreenshotComposites) == 0 {
+		return nil, nil
 	}
 
-	return buffer.Bytes(), diffResult.DiffAmount, nil
+	composites := make([]renderedComposite, len(w.ScreenshotComposites))
+	for i, format := range w.ScreenshotComposites {
+		data, extension, err := diffimage.RenderComposite(format, baselineImage, targetImage, diffImage, screenshotFormat)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to render %s composite: %w", format, err)
+		}
+		composites[i] = renderedComposite{
+			format:    format,
+			extension: extension,
+			data:      data,
+		}
+	}
+	return composites, nil
 }
 
-func (w *Worker) generateHTMLDiff(baselineHTML []byte, targetHTML []byte, format string) ([]byte, float64, error) {
+func (w *Worker) generateHTMLDiff(baselineHTML []byte, targetHTML []byte, format string, baselineLabel string, targetLabel string) (*difftext.DiffResult, error) {
+	if w.HTMLNormalizer != nil {
+		var err error
+		baselineHTML, err = w.HTMLNormalizer.Normalize(baselineHTML)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to normalize baseline HTML: %w", err)
+		}
+		targetHTML, err = w.HTMLNormalizer.Normalize(targetHTML)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to normalize target HTML: %w", err)
+		}
+	}
+
 	var differ difftext.Differ
 	switch format {
 	case "line":
-		differ = difftext.NewLineDiff()
+		differ = difftext.NewLineDiff(difftext.WithContextLines(w.HTMLDiffContextLines), difftext.WithLabels(baselineLabel, targetLabel), difftext.WithHighlight(w.HTMLDiffHighlight), difftext.WithIgnore(w.HTMLIgnore))
+	case "dom":
+		differ = difftext.NewDOMDiff(difftext.WithDOMIgnore(w.HTMLIgnore))
 	default:
-		return nil, 0.0, xerrors.Errorf("unknown HTML diff format: %s", format)
+		return nil, xerrors.Errorf("unknown HTML diff format: %s", format)
 	}
 
 	diffResult, err := differ.Calculate(baselineHTML, targetHTML)
 
 	if err != nil {
-		return nil, 0.0, xerrors.Errorf("failed to calculate HTML diff: %w", err)
+		return nil, xerrors.Errorf("failed to calculate HTML diff: %w", err)
+	}
+	return diffResult, nil
+}
+
+func (w *Worker) generateTextDiff(baselineText []byte, targetText []byte, format string) ([]byte, float64, error) {
+	var differ difftext.Differ
+	switch format {
+	case "word":
+		differ = difftext.NewWordDiff()
+	default:
+		return nil, 0.0, xerrors.Errorf("unknown text diff format: %s", format)
+	}
+
+	diffResult, err := differ.Calculate(baselineText, targetText)
+
+	if err != nil {
+		return nil, 0.0, xerrors.Errorf("failed to calculate text diff: %w", err)
+	}
+	return diffResult.Diff, diffResult.DiffAmount, nil
+}
+
+func (w *Worker) generateStyleDiff(baselineStyles []byte, targetStyles []byte) ([]byte, float64, error) {
+	diffResult, err := difftext.NewStyleDiff().Calculate(baselineStyles, targetStyles)
+	if err != nil {
+		return nil, 0.0, xerrors.Errorf("failed to calculate style diff: %w", err)
 	}
 	return diffResult.Diff, diffResult.DiffAmount, nil
 }
 
+// diffOptions combines the ignored rectangles with the bounding boxes resolved from the ignored selectors on each capture.
+func (w *Worker) diffOptions(boundingBoxes ...[]capture.BoundingBox) []diffimage.Option {
+	ignoreRegions := append([]diffimage.Rectangle{}, w.IgnoreRectangles...)
+	for _, boxes := range boundingBoxes {
+		for _, box := range boxes {
+			ignoreRegions = append(ignoreRegions, diffimage.Rectangle{X: box.X, Y: box.Y, Width: box.Width, Height: box.Height})
+		}
+	}
+
+	opts := []diffimage.Option{
+		diffimage.WithAntiAliasingDetection(w.DetectAntiAliasing),
+		diffimage.WithIgnoreRegions(ignoreRegions...),
+		diffimage.WithMemoryBudget(w.DiffMemoryBudget),
+		diffimage.WithSizeMismatch(w.SizeMismatch, w.PadColor),
+		diffimage.WithPalette(w.Palette),
+		diffimage.WithStrokeWidth(w.StrokeWidth),
+	}
+	if w.PerceptualHashDistance >= 0 {
+		opts = append(opts, diffimage.WithPerceptualHash(w.PerceptualHashDistance))
+	}
+	if w.ColorDistance == "perceptual" {
+		opts = append(opts, diffimage.WithPerceptualColorDistance(w.ColorTolerance))
+	}
+	return opts
+}
+
+func (w *Worker) dimensionChange(change *diffimage.DimensionChange) *DimensionChange {
+	if change == nil {
+		return nil
+	}
+
+	return &DimensionChange{
+		BaselineWidth:  change.BaselineWidth,
+		BaselineHeight: change.BaselineHeight,
+		TargetWidth:    change.TargetWidth,
+		TargetHeight:   change.TargetHeight,
+	}
+}
+
+func (w *Worker) bands(bands []diffimage.Band) []Band {
+	if len(bands) == 0 {
+		return nil
+	}
+
+	result := make([]Band, len(bands))
+	for i, band := range bands {
+		result[i] = Band{Y: band.Y, Height: band.Height}
+	}
+	return result
+}
+
+func (w *Worker) generatePageDiffs(baselinePages [][]byte, targetPages [][]byte, format string, screenshotFormat string, opts ...diffimage.Option) ([][]byte, []PageDiff, error) {
+	pageCount := max(len(baselinePages), len(targetPages))
+	diffImages := make([][]byte, pageCount)
+	pageDiffs := make([]PageDiff, pageCount)
+	for i := 0; i < pageCount; i++ {
+		pageDiffs[i].Page = i + 1
+		if i >= len(baselinePages) || i >= len(targetPages) {
+			pageDiffs[i].DiffAmount = 1.0
+			continue
+		}
+
+		baselineImage, targetImage, err := w.decodeScreenshots(baselinePages[i], targetPages[i])
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to decode page %d: %w", i+1, err)
+		}
+		diffImage, diffResult, err := w.generateDiff(baselineImage, targetImage, format, screenshotFormat, opts...)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to generate diff of page %d: %w", i+1, err)
+		}
+		diffImages[i] = diffImage
+		pageDiffs[i].DiffAmount = diffResult.DiffAmount
+	}
+	return diffImages, pageDiffs, nil
+}
+
+// parseRectangles parses a semicolon-separated list of x,y,width,height rectangles.
+func parseRectangles(value string) ([]diffimage.Rectangle, error) {
+	var rectangles []diffimage.Rectangle
+	for _, item := range strings.Split(value, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.Split(item, ",")
+		if len(parts) != 4 {
+			return nil, xerrors.Errorf("invalid rectangle %q: expected x,y,width,height", item)
+		}
+
+		values := make([]int, len(parts))
+		for i, part := range parts {
+			v, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return nil, xerrors.Errorf("invalid rectangle %q: %w", item, err)
+			}
+			values[i] = v
+		}
+		rectangles = append(rectangles, diffimage.Rectangle{X: values[0], Y: values[1], Width: values[2], Height: values[3]})
+	}
+	return rectangles, nil
+}
+
 func callback(ctx context.Context, callbackURL string, data []byte) error {
 	request, err := http.NewRequestWithContext(ctx, "PATCH", callbackURL, bytes.NewReader(data))
 	if err != nil {
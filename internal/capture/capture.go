@@ -6,18 +6,60 @@ import (
 
 type CaptureResult struct {
 	Screenshot []byte
-	HTML       []byte
+	// ScreenshotFormat is the image format of Screenshot and Pages ("jpeg" or "png").
+	ScreenshotFormat string
+	HTML             []byte
+	Text             []byte
+	// Styles is the JSON encoded list of ElementStyle captured for CaptureOptions.StyleSelectors, or nil when no
+	// selectors are configured.
+	Styles []byte
+	// PDF is the printed document and Pages are the screenshots of each printed page, captured only when
+	// CaptureOptions.PDF is set.
+	PDF   []byte
+	Pages [][]byte
+	// IgnoreRegions are the bounding boxes of the elements matched by CaptureOptions.IgnoreSelectors in screenshot
+	// coordinates.
+	IgnoreRegions []BoundingBox
+}
+
+// BoundingBox is the area of an element in screenshot pixels.
+type BoundingBox struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// ElementStyle is the computed style of a single element matched by one of CaptureOptions.StyleSelectors.
+type ElementStyle struct {
+	Selector   string            `json:"selector"`
+	Index      int               `json:"index"`
+	Properties map[string]string `json:"properties"`
 }
 
 type CaptureOptions struct {
 	MaskSelectors []string
 	Headers       map[string]string
+	// StyleSelectors are CSS selectors whose matched elements have their computed styles captured.
+	StyleSelectors []string
+	// StyleProperties limits the captured computed style properties. All properties are captured when empty.
+	StyleProperties []string
+	// PDF enables the print rendering of the page when set.
+	PDF *PDFOptions
+	// IgnoreSelectors are CSS selectors whose matched elements have their bounding boxes returned as
+	// CaptureResult.IgnoreRegions.
+	IgnoreSelectors []string
+	// ScreenshotFormat overrides the screenshot format of the capturer ("jpeg" or "png") when set.
+	ScreenshotFormat string
 }
 
 func NewCaptureOptions() CaptureOptions {
 	return CaptureOptions{
-		MaskSelectors: make([]string, 0),
-		Headers:       make(map[string]string),
+		MaskSelectors:   make([]string, 0),
+		Headers:         make(map[string]string),
+		StyleSelectors:  make([]string, 0),
+		StyleProperties: make([]string, 0),
+		IgnoreSelectors: make([]string, 0),
 	}
 }
 
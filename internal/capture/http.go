@@ -0,0 +1,80 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"snapshot-controller/internal/retry"
+	"time"
+)
+
+type HTTPConfig struct {
+	Timeout time.Duration
+
+	MaxRetryCount uint
+
+	// MaxBodySize is the size in bytes above which a response is rejected instead of being read into memory.
+	MaxBodySize int64
+}
+
+func DefaultHTTPConfig() HTTPConfig {
+	return HTTPConfig{
+		Timeout:       30 * time.Second,
+		MaxRetryCount: 3,
+		MaxBodySize:   32 << 20,
+	}
+}
+
+// httpCapturer fetches the HTML as served without rendering it in a browser, so the result has neither screenshot
+// nor visible text.
+type httpCapturer struct {
+	client      *http.Client
+	maxBodySize int64
+}
+
+func NewHTTPCapturer(ctx context.Context, h HTTPConfig) (Capturer, error) {
+	return &httpCapturer{
+		client: &http.Client{
+			Timeout: h.Timeout,
+			Transport: &retry.Transport{
+				Base:          http.DefaultTransport,
+				RetryStrategy: retry.NewExponentialBackOff(10*time.Millisecond, 1*time.Second, h.MaxRetryCount, nil),
+				RetryOn:       retry.NewDefaultRetryOn(),
+			},
+		},
+		maxBodySize: h.MaxBodySize,
+	}, nil
+}
+
+func (c *httpCapturer) Capture(ctx context.Context, url string, captureOptions CaptureOptions) (*CaptureResult, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, value := range captureOptions.Headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to fetch %s: unexpected status %s", url, response.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, c.maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response of %s: %w", url, err)
+	}
+	if int64(len(body)) > c.maxBodySize {
+		return nil, fmt.Errorf("failed to read response of %s: larger than %d bytes", url, c.maxBodySize)
+	}
+
+	return &CaptureResult{
+		HTML: body,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package capture_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"snapshot-controller/internal/capture"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHTTPCapturerCapture(t *testing.T) {
+	type in struct {
+		handler        http.HandlerFunc
+		captureOptions capture.CaptureOptions
+	}
+
+	type want struct {
+		first *capture.CaptureResult
+	}
+
+	tests := []struct {
+		name            string
+		in              in
+		want            want
+		wantErrorString string
+	}{
+		{
+			func() string {
+				_, _, line, _ := runtime.Caller(1)
+				return fmt.Sprintf("L%d", line)
+			}(),
+			in{
+				func(w http.ResponseWriter, r *http.Request) {
+					if r.Header.Get("Authorization") != "Bearer fake" || r.Header.Get("X-Fake") != "fake" {
+						w.WriteHeader(http.StatusForbidden)
+						return
+					}
+					w.Header().Set("Content-Type", "text/html")
+					fmt.Fprint(w, "<p>fake</p>")
+				},
+				capture.CaptureOptions{
+					Headers: map[string]string{
+						"Authorization": "Bearer fake",
+						"X-Fake":        "fake",
+					},
+				},
+			},
+			want{
+				&capture.CaptureResult{
+					HTML: []byte("<p>fake</p>"),
+				},
+			},
+			"",
+		},
+		{
+			func() string {
+				_, _, line, _ := runtime.Caller(1)
+				return fmt.Sprintf("L%d", line)
+			}(),
+			in{
+				func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+				},
+				capture.CaptureOptions{},
+			},
+			want{
+				nil,
+			},
+			"failed to fetch %s: unexpected status 404 Not Found",
+		},
+		{
+			func() string {
+				_, _, line, _ := runtime.Caller(1)
+				return fmt.Sprintf("L%d", line)
+			}(),
+			in{
+				func(w http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(w, strings.Repeat("a", int(capture.DefaultHTTPConfig().MaxBodySize)+1))
+				},
+				capture.CaptureOptions{},
+			},
+			want{
+				nil,
+			},
+			fmt.Sprintf("failed to read response of %%s: larger than %d bytes", capture.DefaultHTTPConfig().MaxBodySize),
+		},
+	}
+
+	for _, tt := range tests {
+		name := tt.name
+		in := tt.in
+		want := tt.want
+		wantErrorString := tt.wantErrorString
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(in.handler)
+			defer server.Close()
+
+			capturer, err := capture.NewHTTPCapturer(context.Background(), capture.DefaultHTTPConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := capturer.Capture(context.Background(), server.URL, in.captureOptions)
+			if diff := cmp.Diff(want.first, got); diff != "" {
+				t.Errorf("(-want +got):\n%s", diff)
+			}
+
+			if err == nil {
+				if diff := cmp.Diff(wantErrorString, ""); diff != "" {
+					t.Errorf("(-want +got):\n%s", diff)
+				}
+			} else {
+				if diff := cmp.Diff(fmt.Sprintf(wantErrorString, server.URL), err.Error()); diff != "" {
+					t.Errorf("(-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package capture
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PDFOptions configures the print rendering captured for the "pdf" capture mode.
+type PDFOptions struct {
+	// Format is the paper format such as "A4" or "Letter".
+	Format    string
+	Landscape bool
+	// Margins accept CSS lengths labeled with px, in, cm or mm. Unlabeled values are treated as pixels.
+	MarginTop    string
+	MarginRight  string
+	MarginBottom string
+	MarginLeft   string
+}
+
+const pixelsPerInch = 96.0
+
+// paperSizes are the paper formats supported by page.PDF in inches.
+var paperSizes = map[string][2]float64{
+	"letter":  {8.5, 11},
+	"legal":   {8.5, 14},
+	"tabloid": {11, 17},
+	"ledger":  {17, 11},
+	"a0":      {33.1, 46.8},
+	"a1":      {23.4, 33.1},
+	"a2":      {16.54, 23.4},
+	"a3":      {11.7, 16.54},
+	"a4":      {8.27, 11.7},
+	"a5":      {5.83, 8.27},
+	"a6":      {4.13, 5.83},
+}
+
+// contentSize returns the printable area of a single page in CSS pixels.
+func (o PDFOptions) contentSize() (int, int, error) {
+	size, ok := paperSizes[strings.ToLower(o.Format)]
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown paper format: %s", o.Format)
+	}
+
+	width := size[0] * pixelsPerInch
+	height := size[1] * pixelsPerInch
+	if o.Landscape {
+		width, height = height, width
+	}
+
+	margins := make([]float64, 4)
+	for i, margin := range []string{o.MarginTop, o.MarginRight, o.MarginBottom, o.MarginLeft} {
+		length, err := parseLength(margin)
+		if err != nil {
+			return 0, 0, err
+		}
+		margins[i] = length
+	}
+
+	contentWidth := int(math.Floor(width - margins[1] - margins[3]))
+	contentHeight := int(math.Floor(height - margins[0] - margins[2]))
+	if contentWidth <= 0 || contentHeight <= 0 {
+		return 0, 0, fmt.Errorf("margins exceed the paper size %s", o.Format)
+	}
+
+	return contentWidth, contentHeight, nil
+}
+
+// parseLength converts a CSS length to pixels.
+func parseLength(length string) (float64, error) {
+	length = strings.TrimSpace(length)
+	if length == "" {
+		return 0, nil
+	}
+
+	unit := 1.0
+	for suffix, pixels := range map[string]float64{
+		"px": 1,
+		"in": pixelsPerInch,
+		"cm": pixelsPerInch / 2.54,
+		"mm": pixelsPerInch / 25.4,
+	} {
+		if strings.HasSuffix(length, suffix) {
+			length = strings.TrimSuffix(length, suffix)
+			unit = pixels
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(length, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid length %q: %w", length, err)
+	}
+	return value * unit, nil
+}
@@ -0,0 +1,84 @@
+package capture
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPDFOptions_ContentSize(t *testing.T) {
+	tests := []struct {
+		name           string
+		options        PDFOptions
+		expectedWidth  int
+		expectedHeight int
+		expectError    bool
+	}{
+		{name: "A4", options: PDFOptions{Format: "A4"}, expectedWidth: 793, expectedHeight: 1123},
+		{name: "Landscape", options: PDFOptions{Format: "a4", Landscape: true}, expectedWidth: 1123, expectedHeight: 793},
+		{
+			name:           "Margins",
+			options:        PDFOptions{Format: "Letter", MarginTop: "1in", MarginRight: "10mm", MarginBottom: "2.54cm", MarginLeft: "20"},
+			expectedWidth:  758,
+			expectedHeight: 864,
+		},
+		{name: "UnknownFormat", options: PDFOptions{Format: "B5"}, expectError: true},
+		{name: "InvalidMargin", options: PDFOptions{Format: "A4", MarginTop: "1pt"}, expectError: true},
+		{name: "MarginsExceedPaper", options: PDFOptions{Format: "A6", MarginLeft: "3in", MarginRight: "3in"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height, err := tt.options.contentSize()
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected an error, got %dx%d", width, height)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if width != tt.expectedWidth || height != tt.expectedHeight {
+				t.Errorf("Expected %dx%d, got %dx%d", tt.expectedWidth, tt.expectedHeight, width, height)
+			}
+		})
+	}
+}
+
+func TestParseLength(t *testing.T) {
+	tests := []struct {
+		name        string
+		length      string
+		expected    float64
+		expectError bool
+	}{
+		{name: "Empty", length: "", expected: 0},
+		{name: "Unlabeled", length: "12", expected: 12},
+		{name: "Pixels", length: " 12.5px ", expected: 12.5},
+		{name: "Inches", length: "1in", expected: 96},
+		{name: "Centimeters", length: "2.54cm", expected: 96},
+		{name: "Millimeters", length: "25.4mm", expected: 96},
+		{name: "UnknownUnit", length: "1pt", expectError: true},
+		{name: "Invalid", length: "px", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := parseLength(tt.length)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected an error, got %f", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if math.Abs(actual-tt.expected) > 1e-9 {
+				t.Errorf("Expected %f, got %f", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/playwright-community/playwright-go"
@@ -113,6 +115,14 @@ func (c *playwrightCapturer) Capture(ctx context.Context, url string, captureOpt
 		}
 	}
 
+	var styles []byte
+	if len(captureOptions.StyleSelectors) > 0 {
+		styles, err = c.captureStyles(page, captureOptions.StyleSelectors, captureOptions.StyleProperties)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if len(captureOptions.MaskSelectors) > 0 {
 		unique := make([]byte, 8)
 		if _, err := rand.Read(unique); err != nil {
@@ -159,19 +169,38 @@ func (c *playwrightCapturer) Capture(ctx context.Context, url string, captureOpt
 		}
 	}
 
-	htmlContent, err := page.Content()
+	var ignoreRegions []BoundingBox
+	if len(captureOptions.IgnoreSelectors) > 0 {
+		ignoreRegions, err = c.captureBoundingBoxes(page, captureOptions.IgnoreSelectors)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	htmlContent, err := c.serializeFrame(page.MainFrame())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get HTML content: %w", err)
 	}
 
+	textContent, err := page.Evaluate(`() => document.body ? document.body.innerText : ""`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get visible text: %w", err)
+	}
+	visibleText, _ := textContent.(string)
+
 	options := playwright.PageScreenshotOptions{
 		FullPage: playwright.Bool(c.config.FullPage),
 	}
 
-	switch c.config.Format {
+	format := c.config.Format
+	if captureOptions.ScreenshotFormat != "" {
+		format = captureOptions.ScreenshotFormat
+	}
+	switch format {
 	case "png":
 		options.Type = playwright.ScreenshotTypePng
 	default:
+		format = "jpeg"
 		options.Type = playwright.ScreenshotTypeJpeg
 		if c.config.Quality > 0 {
 			options.Quality = playwright.Int(c.config.Quality)
@@ -183,8 +212,281 @@ func (c *playwrightCapturer) Capture(ctx context.Context, url string, captureOpt
 		return nil, fmt.Errorf("failed to take screenshot: %w", err)
 	}
 
+	var pdf []byte
+	var pages [][]byte
+	if captureOptions.PDF != nil {
+		pdf, pages, err = c.capturePDF(page, *captureOptions.PDF, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &CaptureResult{
-		Screenshot: screenshotBytes,
-		HTML:       []byte(htmlContent),
+		Screenshot:       screenshotBytes,
+		ScreenshotFormat: format,
+		HTML:             []byte(htmlContent),
+		Text:             []byte(visibleText),
+		Styles:           styles,
+		PDF:              pdf,
+		Pages:            pages,
+		IgnoreRegions:    ignoreRegions,
 	}, nil
 }
+
+// capturePDF renders the page as PDF and screenshots each printed page with print media emulation, using a viewport
+// sized to the printable area of the paper.
+func (c *playwrightCapturer) capturePDF(page playwright.Page, pdfOptions PDFOptions, screenshotOptions playwright.PageScreenshotOptions) ([]byte, [][]byte, error) {
+	width, height, err := pdfOptions.contentSize()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	margin := &playwright.Margin{}
+	if pdfOptions.MarginTop != "" {
+		margin.Top = playwright.String(pdfOptions.MarginTop)
+	}
+	if pdfOptions.MarginRight != "" {
+		margin.Right = playwright.String(pdfOptions.MarginRight)
+	}
+	if pdfOptions.MarginBottom != "" {
+		margin.Bottom = playwright.String(pdfOptions.MarginBottom)
+	}
+	if pdfOptions.MarginLeft != "" {
+		margin.Left = playwright.String(pdfOptions.MarginLeft)
+	}
+
+	pdf, err := page.PDF(playwright.PagePdfOptions{
+		Format:          playwright.String(pdfOptions.Format),
+		Landscape:       playwright.Bool(pdfOptions.Landscape),
+		Margin:          margin,
+		PrintBackground: playwright.Bool(true),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+
+	if err := page.EmulateMedia(playwright.PageEmulateMediaOptions{
+		Media: playwright.MediaPrint,
+	}); err != nil {
+		return nil, nil, fmt.Errorf("failed to emulate print media: %w", err)
+	}
+
+	if err := page.SetViewportSize(width, height); err != nil {
+		return nil, nil, fmt.Errorf("failed to set viewport size: %w", err)
+	}
+
+	result, err := page.Evaluate(`() => document.documentElement.scrollHeight`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get document height: %w", err)
+	}
+
+	var documentHeight float64
+	switch value := result.(type) {
+	case int:
+		documentHeight = float64(value)
+	case float64:
+		documentHeight = value
+	default:
+		return nil, nil, fmt.Errorf("unexpected document height: %T", result)
+	}
+
+	pageCount := max(1, int(math.Ceil(documentHeight/float64(height))))
+	pages := make([][]byte, 0, pageCount)
+	for i := 0; i < pageCount; i++ {
+		options := screenshotOptions
+		options.FullPage = playwright.Bool(true)
+		options.Clip = &playwright.Rect{
+			X:      0,
+			Y:      float64(i * height),
+			Width:  float64(width),
+			Height: float64(height),
+		}
+
+		screenshot, err := page.Screenshot(options)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to take screenshot of page %d: %w", i+1, err)
+		}
+		pages = append(pages, screenshot)
+	}
+
+	return pdf, pages, nil
+}
+
+// styleScript collects the computed styles of every element matched by the given selectors, in document order.
+const styleScript = `({selectors, properties}) => {
+	const results = [];
+	selectors.forEach((selector) => {
+		document.querySelectorAll(selector).forEach((element, index) => {
+			const computedStyle = window.getComputedStyle(element);
+			const names = properties && properties.length > 0 ? properties : Array.from(computedStyle);
+			const values = {};
+			names.forEach((name) => {
+				values[name] = computedStyle.getPropertyValue(name);
+			});
+			results.push({selector: selector, index: index, properties: values});
+		});
+	});
+	return JSON.stringify(results);
+}`
+
+func (c *playwrightCapturer) captureStyles(page playwright.Page, selectors []string, properties []string) ([]byte, error) {
+	result, err := page.Evaluate(styleScript, map[string]interface{}{
+		"selectors":  selectors,
+		"properties": properties,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get computed styles: %w", err)
+	}
+
+	content, ok := result.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected computed styles result: %T", result)
+	}
+
+	var elementStyles []ElementStyle
+	if err := json.Unmarshal([]byte(content), &elementStyles); err != nil {
+		return nil, fmt.Errorf("failed to decode computed styles: %w", err)
+	}
+
+	styles, err := json.MarshalIndent(elementStyles, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode computed styles: %w", err)
+	}
+
+	return styles, nil
+}
+
+// boundingBoxScript returns the bounding boxes of every visible element matched by the given selectors. The boxes are
+// relative to the document in full page screenshots and to the viewport otherwise.
+const boundingBoxScript = `({selectors, fullPage}) => {
+	const results = [];
+	selectors.forEach((selector) => {
+		document.querySelectorAll(selector).forEach((element) => {
+			const rect = element.getBoundingClientRect();
+			if (rect.width === 0 || rect.height === 0) {
+				return;
+			}
+			const left = rect.left + (fullPage ? window.scrollX : 0);
+			const top = rect.top + (fullPage ? window.scrollY : 0);
+			const ratio = window.devicePixelRatio || 1;
+			const x = Math.floor(left * ratio);
+			const y = Math.floor(top * ratio);
+			results.push({
+				x: x,
+				y: y,
+				width: Math.ceil((left + rect.width) * ratio) - x,
+				height: Math.ceil((top + rect.height) * ratio) - y,
+			});
+		});
+	});
+	return JSON.stringify(results);
+}`
+
+func (c *playwrightCapturer) captureBoundingBoxes(page playwright.Page, selectors []string) ([]BoundingBox, error) {
+	result, err := page.Evaluate(boundingBoxScript, map[string]interface{}{
+		"selectors": selectors,
+		"fullPage":  c.config.FullPage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bounding boxes: %w", err)
+	}
+
+	content, ok := result.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected bounding boxes result: %T", result)
+	}
+
+	var boundingBoxes []BoundingBox
+	if err := json.Unmarshal([]byte(content), &boundingBoxes); err != nil {
+		return nil, fmt.Errorf("failed to decode bounding boxes: %w", err)
+	}
+
+	return boundingBoxes, nil
+}
+
+// frameAttribute marks the templates that hold the documents of iframes inlined by serializeScript.
+const frameAttribute = "data-snapshot-frame"
+
+// serializeScript serializes the document like page.Content(), except that open shadow roots are emitted as
+// declarative shadow DOM (<template shadowrootmode="open">) and each iframe of the given frames is replaced by a
+// <template data-snapshot-frame> carrying the iframe attributes and the serialized frame document as escaped text, as
+// the template contents of a parser would drop its doctype, html, head and body. The frames pair the iframe elements
+// with their documents, so that the page is serialized without being marked.
+const serializeScript = `(frames) => {
+	const documents = new Map(frames.map((frame) => [frame.element, frame.document]));
+	const voidElements = new Set(["area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "param", "source", "track", "wbr"]);
+	const rawTextElements = new Set(["iframe", "noembed", "noframes", "noscript", "plaintext", "script", "style", "xmp"]);
+
+	const escapeText = (text) => text.replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;").replace(/\u00a0/g, "&nbsp;");
+	const escapeAttribute = (value) => value.replace(/&/g, "&amp;").replace(/"/g, "&quot;").replace(/\u00a0/g, "&nbsp;");
+	const serializeAttributes = (element) => Array.from(element.attributes).map((attribute) => " " + attribute.name + "=\"" + escapeAttribute(attribute.value) + "\"").join("");
+	const serializeChildren = (node) => Array.from(node.childNodes).map((child) => serialize(child)).join("");
+
+	const serialize = (node) => {
+		switch (node.nodeType) {
+			case Node.ELEMENT_NODE: {
+				const tag = node.localName;
+				if (documents.has(node)) {
+					return "<template ` + frameAttribute + `" + serializeAttributes(node) + ">" + escapeText(documents.get(node)) + "</template>";
+				}
+
+				let html = "<" + tag + serializeAttributes(node) + ">";
+				if (voidElements.has(tag)) {
+					return html;
+				}
+				if (node.shadowRoot !== null) {
+					html += "<template shadowrootmode=\"" + node.shadowRoot.mode + "\">" + serializeChildren(node.shadowRoot) + "</template>";
+				}
+				html += serializeChildren(tag === "template" ? node.content : node);
+				return html + "</" + tag + ">";
+			}
+			case Node.TEXT_NODE: {
+				const parent = node.parentNode;
+				if (parent !== null && parent.nodeType === Node.ELEMENT_NODE && rawTextElements.has(parent.localName)) {
+					return node.data;
+				}
+				return escapeText(node.data);
+			}
+			case Node.COMMENT_NODE:
+				return "<!--" + node.data + "-->";
+			case Node.DOCUMENT_TYPE_NODE:
+				return "<!DOCTYPE " + node.name + ">";
+			default:
+				return "";
+		}
+	};
+
+	return serializeChildren(document);
+}`
+
+func (c *playwrightCapturer) serializeFrame(frame playwright.Frame) (string, error) {
+	frames := []map[string]interface{}{}
+	for _, childFrame := range frame.ChildFrames() {
+		if childFrame.IsDetached() {
+			continue
+		}
+
+		element, err := childFrame.FrameElement()
+		if err != nil {
+			return "", fmt.Errorf("failed to get frame element: %w", err)
+		}
+
+		document, err := c.serializeFrame(childFrame)
+		if err != nil {
+			return "", err
+		}
+		frames = append(frames, map[string]interface{}{"element": element, "document": document})
+	}
+
+	result, err := frame.Evaluate(serializeScript, frames)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize frame %s: %w", frame.URL(), err)
+	}
+
+	content, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected serialization result of frame %s: %T", frame.URL(), result)
+	}
+
+	return content, nil
+}
@@ -4,14 +4,19 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
-	"image/jpeg"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
 	"os"
 	ssV1 "snapshot-controller/api/v1"
 	"snapshot-controller/internal/capture"
 	diffimage "snapshot-controller/internal/diff/image"
 	difftext "snapshot-controller/internal/diff/text"
 	"snapshot-controller/internal/storage"
+	"strconv"
 	"strings"
 	"time"
 
@@ -39,10 +44,16 @@ type ScheduledSnapshotReconciler struct {
 	Recorder record.EventRecorder
 	Capturer capture.Capturer
 	Storage  storage.Storage
+	// HTTPCapturer captures snapshots in the "http" capture mode
+	HTTPCapturer capture.Capturer
 
 	Distributed             bool
 	DistributedCallbackHost string
 	DistributedWorkerImage  string
+
+	// DiffMemoryBudget bounds the bytes allocated for comparing screenshots, excluding the decoded screenshots
+	// themselves. Larger screenshots are compared in strips.
+	DiffMemoryBudget int
 }
 
 func (r *ScheduledSnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -54,6 +65,11 @@ func (r *ScheduledSnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	if err := r.validateSpec(&scheduledSnapshot.Spec); err != nil {
+		r.Recorder.Eventf(scheduledSnapshot, coreV1.EventTypeWarning, "InvalidSpec", "Scheduled snapshot %q is not processed: %v", scheduledSnapshot.Name, err)
+		return ctrl.Result{}, nil
+	}
+
 	if r.Distributed {
 		if err := r.createOrUpdateCronJob(ctx, scheduledSnapshot); err != nil {
 			return ctrl.Result{}, err
@@ -87,29 +103,69 @@ func (r *ScheduledSnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 func (r *ScheduledSnapshotReconciler) processSnapshot(ctx context.Context, scheduledSnapshot *ssV1.ScheduledSnapshot) error {
 	captureOptions := capture.CaptureOptions{
-		MaskSelectors: scheduledSnapshot.Spec.MaskSelectors,
-		Headers:       scheduledSnapshot.Spec.Headers,
+		MaskSelectors:    scheduledSnapshot.Spec.MaskSelectors,
+		Headers:          scheduledSnapshot.Spec.Headers,
+		StyleSelectors:   scheduledSnapshot.Spec.StyleSelectors,
+		StyleProperties:  scheduledSnapshot.Spec.StyleProperties,
+		IgnoreSelectors:  r.ignoreSelectors(scheduledSnapshot.Spec.IgnoreRegions),
+		ScreenshotFormat: scheduledSnapshot.Spec.ScreenshotFormat,
+	}
+	if scheduledSnapshot.Spec.CaptureMode == "pdf" {
+		captureOptions.PDF = r.pdfOptions(scheduledSnapshot.Spec.PDF)
 	}
 
-	result, err := r.Capturer.Capture(ctx, scheduledSnapshot.Spec.Target, captureOptions)
+	result, err := r.capturer(scheduledSnapshot.Spec.CaptureMode).Capture(ctx, scheduledSnapshot.Spec.Target, captureOptions)
 	if err != nil {
 		return xerrors.Errorf("failed to download screenshot: %w", err)
 	}
 
 	var diffImage []byte
 	var diffAmount float64
+	var diffRegions []byte
+	var composites []renderedComposite
+	var insertedBands []ssV1.Band
+	var removedBands []ssV1.Band
+	var dimensionChange *ssV1.DimensionChange
 	var htmlDiff []byte
 	var htmlDiffAmount float64
-	if scheduledSnapshot.Status.BaselineURL != "" {
+	var htmlDiffChanges []byte
+	var htmlDiffRendered []byte
+	var textDiff []byte
+	var textDiffAmount float64
+	var styleDiff []byte
+	var styleDiffAmount float64
+	var pageDiffImages [][]byte
+	var pageDiffs []ssV1.PageDiff
+	if scheduledSnapshot.Status.BaselineURL != "" && result.Screenshot != nil {
 		baselineData, err := r.Storage.Get(ctx, scheduledSnapshot.Status.BaselineURL)
 		if err != nil {
 			return xerrors.Errorf("failed to generate diff: %w", err)
 		}
 
-		diffImage, diffAmount, err = r.generateDiff(baselineData, result.Screenshot, scheduledSnapshot.Spec.ScreenshotDiffFormat)
+		var baselineImage, targetImage image.Image
+		baselineImage, targetImage, err = r.decodeScreenshots(baselineData, result.Screenshot)
+		if err != nil {
+			return xerrors.Errorf("failed to decode screenshots: %w", err)
+		}
+		var diffResult *diffimage.DiffResult
+		diffImage, diffResult, err = r.generateDiff(baselineImage, targetImage, scheduledSnapshot.Spec.ScreenshotDiffFormat, result.ScreenshotFormat, r.diffOptions(&scheduledSnapshot.Spec, r.ignoreRegions(scheduledSnapshot.Spec.IgnoreRegions, scheduledSnapshot.Status.BaselineIgnoreRegions, r.regions(result.IgnoreRegions)))...)
 		if err != nil {
 			return xerrors.Errorf("failed to generate diff: %w", err)
 		}
+		diffAmount = diffResult.DiffAmount
+		insertedBands = r.bands(diffResult.InsertedBands)
+		removedBands = r.bands(diffResult.RemovedBands)
+		dimensionChange = r.dimensionChange(diffResult.DimensionChange)
+		if len(diffResult.Regions) > 0 {
+			diffRegions, err = json.MarshalIndent(diffResult.Regions, "", "  ")
+			if err != nil {
+				return xerrors.Errorf("failed to marshal diff regions: %w", err)
+			}
+		}
+		composites, err = r.generateComposites(baselineImage, targetImage, diffResult.Image, scheduledSnapshot.Spec.ScreenshotComposites, result.ScreenshotFormat)
+		if err != nil {
+			return xerrors.Errorf("failed to generate composites: %w", err)
+		}
 	}
 
 	if scheduledSnapshot.Status.BaselineHTMLURL != "" {
@@ -118,16 +174,70 @@ func (r *ScheduledSnapshotReconciler) processSnapshot(ctx context.Context, sched
 			return xerrors.Errorf("failed to download baseline HTML: %w", err)
 		}
 
-		htmlDiff, htmlDiffAmount, err = r.generateHTMLDiff(baselineHTMLData, result.HTML, scheduledSnapshot.Spec.HTMLDiffFormat)
+		htmlDiffResult, err := r.generateHTMLDiff(baselineHTMLData, result.HTML, scheduledSnapshot.Spec.HTMLDiffFormat, r.htmlNormalizer(scheduledSnapshot.Spec.HTMLNormalization), r.htmlIgnore(scheduledSnapshot.Spec.HTMLIgnore), r.lineDiffOptions(&scheduledSnapshot.Spec, scheduledSnapshot.Status.BaselineHTMLURL, scheduledSnapshot.Spec.Target)...)
 		if err != nil {
 			return xerrors.Errorf("failed to generate HTML diff: %w", err)
 		}
+		htmlDiff, htmlDiffAmount, htmlDiffRendered = htmlDiffResult.Diff, htmlDiffResult.DiffAmount, htmlDiffResult.Rendered
+		if len(htmlDiffResult.Changes) > 0 {
+			htmlDiffChanges, err = json.MarshalIndent(htmlDiffResult.Changes, "", "  ")
+			if err != nil {
+				return xerrors.Errorf("failed to marshal HTML diff changes: %w", err)
+			}
+		}
 	}
 
-	var imageURL string
-	var htmlURL string
-	var diffURL string
-	var htmlDiffURL string
+	if scheduledSnapshot.Status.BaselineTextURL != "" && result.Text != nil {
+		baselineTextData, err := r.Storage.Get(ctx, scheduledSnapshot.Status.BaselineTextURL)
+		if err != nil {
+			return xerrors.Errorf("failed to download baseline text: %w", err)
+		}
+
+		textDiff, textDiffAmount, err = r.generateTextDiff(baselineTextData, result.Text, scheduledSnapshot.Spec.TextDiffFormat)
+		if err != nil {
+			return xerrors.Errorf("failed to generate text diff: %w", err)
+		}
+	}
+
+	if scheduledSnapshot.Status.BaselineStylesURL != "" && result.Styles != nil {
+		baselineStylesData, err := r.Storage.Get(ctx, scheduledSnapshot.Status.BaselineStylesURL)
+		if err != nil {
+			return xerrors.Errorf("failed to download baseline styles: %w", err)
+		}
+
+		styleDiff, styleDiffAmount, err = r.generateStyleDiff(baselineStylesData, result.Styles)
+		if err != nil {
+			return xerrors.Errorf("failed to generate style diff: %w", err)
+		}
+	}
+
+	if len(scheduledSnapshot.Status.BaselinePageURLs) > 0 && result.Pages != nil {
+		baselinePages := make([][]byte, len(scheduledSnapshot.Status.BaselinePageURLs))
+		for i, pageURL := range scheduledSnapshot.Status.BaselinePageURLs {
+			baselinePages[i], err = r.Storage.Get(ctx, pageURL)
+			if err != nil {
+				return xerrors.Errorf("failed to download baseline page %d: %w", i+1, err)
+			}
+		}
+
+		pageDiffImages, pageDiffs, err = r.generatePageDiffs(baselinePages, result.Pages, scheduledSnapshot.Spec.ScreenshotDiffFormat, result.ScreenshotFormat, r.diffOptions(&scheduledSnapshot.Spec, r.ignoreRegions(scheduledSnapshot.Spec.IgnoreRegions))...)
+		if err != nil {
+			return xerrors.Errorf("failed to generate page diffs: %w", err)
+		}
+	}
+
+	status := ssV1.ScheduledSnapshotStatus{
+		ScreenshotDiffAmount:      diffAmount,
+		ScreenshotInsertedBands:   insertedBands,
+		ScreenshotRemovedBands:    removedBands,
+		ScreenshotDimensionChange: dimensionChange,
+		HTMLDiffAmount:            htmlDiffAmount,
+		TextDiffAmount:            textDiffAmount,
+		StyleDiffAmount:           styleDiffAmount,
+		PageDiffs:                 pageDiffs,
+		ScreenshotComposites:      make([]ssV1.Composite, len(composites)),
+		TargetIgnoreRegions:       r.regions(result.IgnoreRegions),
+	}
 
 	{
 		eg, ctx := errgroup.WithContext(ctx)
@@ -140,15 +250,17 @@ func (r *ScheduledSnapshotReconciler) processSnapshot(ctx context.Context, sched
 
 		baseKey := fmt.Sprintf("ScheduledSnapshot/capture/%s/%s", urlHash, timestamp)
 
-		eg.Go(func() error {
-			imageKey := baseKey + ".jpeg"
-			path, err := r.Storage.Put(ctx, imageKey, result.Screenshot)
-			if err != nil {
-				return xerrors.Errorf("failed to upload screenshot: %w", err)
-			}
-			imageURL = path
-			return nil
-		})
+		if result.Screenshot != nil {
+			eg.Go(func() error {
+				imageKey := baseKey + "." + diffimage.Extension(result.ScreenshotFormat)
+				path, err := r.Storage.Put(ctx, imageKey, result.Screenshot)
+				if err != nil {
+					return xerrors.Errorf("failed to upload screenshot: %w", err)
+				}
+				status.TargetURL = path
+				return nil
+			})
+		}
 
 		eg.Go(func() error {
 			htmlKey := baseKey + ".html"
@@ -156,40 +268,182 @@ func (r *ScheduledSnapshotReconciler) processSnapshot(ctx context.Context, sched
 			if err != nil {
 				return xerrors.Errorf("failed to upload HTML: %w", err)
 			}
-			htmlURL = path
+			status.TargetHTMLURL = path
 			return nil
 		})
 
-		if diffImage != nil {
+		if result.Text != nil {
+			eg.Go(func() error {
+				textKey := baseKey + ".txt"
+				path, err := r.Storage.Put(ctx, textKey, result.Text)
+				if err != nil {
+					return xerrors.Errorf("failed to upload text: %w", err)
+				}
+				status.TargetTextURL = path
+				return nil
+			})
+		}
+
+		if result.Styles != nil {
+			eg.Go(func() error {
+				stylesKey := baseKey + "-styles.json"
+				path, err := r.Storage.Put(ctx, stylesKey, result.Styles)
+				if err != nil {
+					return xerrors.Errorf("failed to upload styles: %w", err)
+				}
+				status.TargetStylesURL = path
+				return nil
+			})
+		}
+
+		if result.PDF != nil {
 			eg.Go(func() error {
-				h := sha256.New()
-				h.Write([]byte(scheduledSnapshot.Status.BaselineURL + scheduledSnapshot.Spec.Target))
-				hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
+				pdfKey := baseKey + ".pdf"
+				path, err := r.Storage.Put(ctx, pdfKey, result.PDF)
+				if err != nil {
+					return xerrors.Errorf("failed to upload PDF: %w", err)
+				}
+				status.TargetPDFURL = path
+				return nil
+			})
+		}
+
+		if result.Pages != nil {
+			status.TargetPageURLs = make([]string, len(result.Pages))
+		}
+		for i, page := range result.Pages {
+			eg.Go(func() error {
+				pageKey := fmt.Sprintf("%s-page-%d.%s", baseKey, i+1, diffimage.Extension(result.ScreenshotFormat))
+				path, err := r.Storage.Put(ctx, pageKey, page)
+				if err != nil {
+					return xerrors.Errorf("failed to upload page %d: %w", i+1, err)
+				}
+				status.TargetPageURLs[i] = path
+				return nil
+			})
+		}
 
-				diffKey := fmt.Sprintf("Snapshot/diff/%s/%s.jpeg", hash, timestamp)
+		h = sha256.New()
+		h.Write([]byte(scheduledSnapshot.Status.BaselineURL + scheduledSnapshot.Spec.Target))
+		hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
+
+		if diffImage != nil {
+			eg.Go(func() error {
+				diffKey := fmt.Sprintf("Snapshot/diff/%s/%s.%s", hash, timestamp, diffimage.Extension(result.ScreenshotFormat))
 
 				url, err := r.Storage.Put(ctx, diffKey, diffImage)
 				if err != nil {
 					return xerrors.Errorf("failed to upload diff image: %w", err)
 				}
-				diffURL = url
+				status.ScreenshotDiffURL = url
 				return nil
 			})
 		}
 
-		if htmlDiff != nil {
+		if diffRegions != nil {
 			eg.Go(func() error {
-				h := sha256.New()
-				h.Write([]byte(scheduledSnapshot.Status.BaselineURL + scheduledSnapshot.Spec.Target))
-				hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
+				diffRegionsKey := fmt.Sprintf("Snapshot/diff/%s/%s-regions.json", hash, timestamp)
+
+				url, err := r.Storage.Put(ctx, diffRegionsKey, diffRegions)
+				if err != nil {
+					return xerrors.Errorf("failed to upload diff regions: %w", err)
+				}
+				status.ScreenshotDiffRegionsURL = url
+				return nil
+			})
+		}
 
-				htmlDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s.txt", hash, timestamp)
+		for i, composite := range composites {
+			status.ScreenshotComposites[i].Format = composite.format
+			eg.Go(func() error {
+				compositeKey := fmt.Sprintf("Snapshot/diff/%s/%s-%s.%s", hash, timestamp, composite.format, composite.extension)
+
+				url, err := r.Storage.Put(ctx, compositeKey, composite.data)
+				if err != nil {
+					return xerrors.Errorf("failed to upload %s composite: %w", composite.format, err)
+				}
+				status.ScreenshotComposites[i].URL = url
+				return nil
+			})
+		}
+
+		if htmlDiff != nil {
+			eg.Go(func() error {
+				htmlDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s.%s", hash, timestamp, difftext.Extension(scheduledSnapshot.Spec.HTMLDiffFormat))
 
 				url, err := r.Storage.Put(ctx, htmlDiffKey, htmlDiff)
 				if err != nil {
 					return xerrors.Errorf("failed to upload HTML diff: %w", err)
 				}
-				htmlDiffURL = url
+				status.HTMLDiffURL = url
+				return nil
+			})
+		}
+
+		if htmlDiffChanges != nil {
+			eg.Go(func() error {
+				htmlDiffChangesKey := fmt.Sprintf("Snapshot/diff/%s/%s-changes.json", hash, timestamp)
+
+				url, err := r.Storage.Put(ctx, htmlDiffChangesKey, htmlDiffChanges)
+				if err != nil {
+					return xerrors.Errorf("failed to upload HTML diff changes: %w", err)
+				}
+				status.HTMLDiffChangesURL = url
+				return nil
+			})
+		}
+
+		if htmlDiffRendered != nil {
+			eg.Go(func() error {
+				htmlDiffRenderedKey := fmt.Sprintf("Snapshot/diff/%s/%s.html", hash, timestamp)
+
+				url, err := r.Storage.Put(ctx, htmlDiffRenderedKey, htmlDiffRendered)
+				if err != nil {
+					return xerrors.Errorf("failed to upload rendered HTML diff: %w", err)
+				}
+				status.HTMLDiffRenderedURL = url
+				return nil
+			})
+		}
+
+		if textDiff != nil {
+			eg.Go(func() error {
+				textDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s-text.txt", hash, timestamp)
+
+				url, err := r.Storage.Put(ctx, textDiffKey, textDiff)
+				if err != nil {
+					return xerrors.Errorf("failed to upload text diff: %w", err)
+				}
+				status.TextDiffURL = url
+				return nil
+			})
+		}
+
+		if styleDiff != nil {
+			eg.Go(func() error {
+				styleDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s-styles.txt", hash, timestamp)
+
+				url, err := r.Storage.Put(ctx, styleDiffKey, styleDiff)
+				if err != nil {
+					return xerrors.Errorf("failed to upload style diff: %w", err)
+				}
+				status.StyleDiffURL = url
+				return nil
+			})
+		}
+
+		for i, pageDiffImage := range pageDiffImages {
+			if pageDiffImage == nil {
+				continue
+			}
+			eg.Go(func() error {
+				pageDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s-page-%d.%s", hash, timestamp, i+1, diffimage.Extension(result.ScreenshotFormat))
+
+				url, err := r.Storage.Put(ctx, pageDiffKey, pageDiffImage)
+				if err != nil {
+					return xerrors.Errorf("failed to upload diff image of page %d: %w", i+1, err)
+				}
+				status.PageDiffs[i].DiffURL = url
 				return nil
 			})
 		}
@@ -199,80 +453,384 @@ func (r *ScheduledSnapshotReconciler) processSnapshot(ctx context.Context, sched
 		}
 	}
 
-	if err := r.updateScheduledSnapshotStatus(ctx, scheduledSnapshot, imageURL, htmlURL, diffURL, diffAmount, htmlDiffURL, htmlDiffAmount); err != nil {
+	if err := r.updateScheduledSnapshotStatus(ctx, scheduledSnapshot, status); err != nil {
 		return err
 	}
-	r.Recorder.Eventf(scheduledSnapshot, coreV1.EventTypeNormal, "SnapshotCompleted", "Scheduled snapshot completed successfully: %q (screenshot difference: %.2f%%, HTML difference: %.2f%%)", scheduledSnapshot.Name, diffAmount*100, htmlDiffAmount*100)
+	r.Recorder.Eventf(scheduledSnapshot, coreV1.EventTypeNormal, "SnapshotCompleted", "Scheduled snapshot completed successfully: %q (screenshot difference: %.2f%%, HTML difference: %.2f%%, text difference: %.2f%%, style difference: %.2f%%)", scheduledSnapshot.Name, diffAmount*100, htmlDiffAmount*100, textDiffAmount*100, styleDiffAmount*100)
 
 	return nil
 }
 
-func (r *ScheduledSnapshotReconciler) generateDiff(baselineData []byte, targetData []byte, format string) ([]byte, float64, error) {
-	baselineImage, err := jpeg.Decode(bytes.NewReader(baselineData))
+// decodeScreenshots decodes the screenshots once for the diff and the composites.
+func (r *ScheduledSnapshotReconciler) decodeScreenshots(baselineData []byte, targetData []byte) (image.Image, image.Image, error) {
+	baselineImage, _, err := image.Decode(bytes.NewReader(baselineData))
 	if err != nil {
-		return nil, 0.0, xerrors.Errorf("failed to decode baseline image: %w", err)
+		return nil, nil, xerrors.Errorf("failed to decode baseline image: %w", err)
 	}
 
-	targetImage, err := jpeg.Decode(bytes.NewReader(targetData))
-	if err != nil {
-		return nil, 0.0, xerrors.Errorf("failed to decode target image: %w", err)
+	// Byte-identical screenshots share the decoded image, which the differs return as is without comparing pixels.
+	targetImage := baselineImage
+	if !bytes.Equal(baselineData, targetData) {
+		targetImage, _, err = image.Decode(bytes.NewReader(targetData))
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to decode target image: %w", err)
+		}
 	}
+	return baselineImage, targetImage, nil
+}
 
+func (r *ScheduledSnapshotReconciler) generateDiff(baselineImage image.Image, targetImage image.Image, format string, screenshotFormat string, opts ...diffimage.Option) ([]byte, *diffimage.DiffResult, error) {
 	var differ diffimage.Differ
 	switch format {
 	case "rectangle":
-		differ = diffimage.NewRectangleDiff()
+		differ = diffimage.NewRectangleDiff(opts...)
+	case "ssim":
+		differ = diffimage.NewSSIMDiff(opts...)
+	case "shift":
+		differ = diffimage.NewShiftDiff(0.1, opts...)
 	case "pixel":
-		differ = diffimage.NewPixelDiff(0.1)
+		differ = diffimage.NewPixelDiff(0.1, opts...)
 	default:
-		return nil, 0.0, xerrors.Errorf("unknown diff format: %s", format)
+		return nil, nil, xerrors.Errorf("unknown diff format: %s", format)
 	}
 
 	diffResult := differ.Calculate(baselineImage, targetImage)
 
-	var buffer bytes.Buffer
-	err = jpeg.Encode(&buffer, diffResult.Image, &jpeg.Options{Quality: 90})
+	diffImage, err := diffimage.Encode(diffResult.Image, screenshotFormat)
 	if err != nil {
-		return nil, 0.0, xerrors.Errorf("failed to encode diff image: %w", err)
+		return nil, nil, xerrors.Errorf("failed to encode diff image: %w", err)
 	}
 
-	return buffer.Bytes(), diffResult.DiffAmount, nil
+	return diffImage, diffResult, nil
 }
 
-func (r *ScheduledSnapshotReconciler) generateHTMLDiff(baselineHTML []byte, targetHTML []byte, format string) ([]byte, float64, error) {
+func (r *ScheduledSnapshotReconciler) generateComposites(baselineImage image.Image, targetImage image.Image, diffImage image.Image, formats []ssV1.CompositeFormat, screenshotFormat string) ([]renderedComposite, error) {
+	if len(formats) == 0 {
+		return nil, nil
+	}
+
+	composites := make([]renderedComposite, len(formats))
+	for i, format := range formats {
+		data, extension, err := diffimage.RenderComposite(string(format), baselineImage, targetImage, diffImage, screenshotFormat)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to render %s composite: %w", format, err)
+		}
+		composites[i] = renderedComposite{
+			format:    string(format),
+			extension: extension,
+			data:      data,
+		}
+	}
+	return composites, nil
+}
+
+func (r *ScheduledSnapshotReconciler) generateHTMLDiff(baselineHTML []byte, targetHTML []byte, format string, normalizer *difftext.Normalizer, ignore *difftext.Ignore, opts ...difftext.LineDiffOption) (*difftext.DiffResult, error) {
+	if normalizer != nil {
+		var err error
+		baselineHTML, err = normalizer.Normalize(baselineHTML)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to normalize baseline HTML: %w", err)
+		}
+		targetHTML, err = normalizer.Normalize(targetHTML)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to normalize target HTML: %w", err)
+		}
+	}
+
 	var differ difftext.Differ
 	switch format {
 	case "line":
-		differ = difftext.NewLineDiff()
+		differ = difftext.NewLineDiff(append(opts, difftext.WithIgnore(ignore))...)
+	case "dom":
+		differ = difftext.NewDOMDiff(difftext.WithDOMIgnore(ignore))
 	default:
-		return nil, 0.0, xerrors.Errorf("unknown HTML diff format: %s", format)
+		return nil, xerrors.Errorf("unknown HTML diff format: %s", format)
 	}
 
 	diffResult, err := differ.Calculate(baselineHTML, targetHTML)
 
 	if err != nil {
-		return nil, 0.0, xerrors.Errorf("failed to calculate HTML diff: %w", err)
+		return nil, xerrors.Errorf("failed to calculate HTML diff: %w", err)
+	}
+	return diffResult, nil
+}
+
+func (r *ScheduledSnapshotReconciler) htmlNormalizer(normalization *ssV1.HTMLNormalization) *difftext.Normalizer {
+	if normalization == nil {
+		return nil
+	}
+	replacements := make([]difftext.Replacement, len(normalization.Replacements))
+	for i, replacement := range normalization.Replacements {
+		replacements[i] = difftext.Replacement{Pattern: replacement.Pattern, Replacement: replacement.Replacement}
+	}
+	return &difftext.Normalizer{
+		PrettyPrint:        normalization.PrettyPrint,
+		SortAttributes:     normalization.SortAttributes,
+		CollapseWhitespace: normalization.CollapseWhitespace,
+		DropComments:       normalization.DropComments,
+		Replacements:       replacements,
+	}
+}
+
+// validateSpec reports the errors of the spec that retrying cannot fix, so that they surface once instead of failing
+// every reconcile.
+func (r *ScheduledSnapshotReconciler) validateSpec(spec *ssV1.ScheduledSnapshotSpec) error {
+	if normalizer := r.htmlNormalizer(spec.HTMLNormalization); normalizer != nil {
+		if err := normalizer.Validate(); err != nil {
+			return xerrors.Errorf("invalid htmlNormalization: %w", err)
+		}
+	}
+	if ignore := r.htmlIgnore(spec.HTMLIgnore); ignore != nil {
+		if err := ignore.Validate(); err != nil {
+			return xerrors.Errorf("invalid htmlIgnore: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *ScheduledSnapshotReconciler) htmlIgnore(ignore *ssV1.HTMLIgnore) *difftext.Ignore {
+	if ignore == nil {
+		return nil
+	}
+	return &difftext.Ignore{
+		Selectors:  ignore.Selectors,
+		Attributes: ignore.Attributes,
+		Lines:      ignore.Lines,
+	}
+}
+
+// lineDiffOptions names the baseline and the target in the headers of the unified diff.
+func (r *ScheduledSnapshotReconciler) lineDiffOptions(spec *ssV1.ScheduledSnapshotSpec, baselineLabel string, targetLabel string) []difftext.LineDiffOption {
+	opts := []difftext.LineDiffOption{difftext.WithLabels(baselineLabel, targetLabel)}
+	if spec.HTMLDiffContextLines != nil {
+		opts = append(opts, difftext.WithContextLines(*spec.HTMLDiffContextLines))
+	}
+	if spec.HTMLDiffHighlight != "" {
+		opts = append(opts, difftext.WithHighlight(spec.HTMLDiffHighlight))
+	}
+	return opts
+}
+
+func (r *ScheduledSnapshotReconciler) generateTextDiff(baselineText []byte, targetText []byte, format string) ([]byte, float64, error) {
+	var differ difftext.Differ
+	switch format {
+	case "word":
+		differ = difftext.NewWordDiff()
+	default:
+		return nil, 0.0, xerrors.Errorf("unknown text diff format: %s", format)
+	}
+
+	diffResult, err := differ.Calculate(baselineText, targetText)
+
+	if err != nil {
+		return nil, 0.0, xerrors.Errorf("failed to calculate text diff: %w", err)
 	}
 	return diffResult.Diff, diffResult.DiffAmount, nil
 }
 
-func (r *ScheduledSnapshotReconciler) updateScheduledSnapshotStatus(ctx context.Context, scheduledSnapshot *ssV1.ScheduledSnapshot, imageURL string, htmlURL string, diffURL string, diffAmount float64, htmlDiffURL string, htmlDiffAmount float64) error {
+func (r *ScheduledSnapshotReconciler) generateStyleDiff(baselineStyles []byte, targetStyles []byte) ([]byte, float64, error) {
+	diffResult, err := difftext.NewStyleDiff().Calculate(baselineStyles, targetStyles)
+	if err != nil {
+		return nil, 0.0, xerrors.Errorf("failed to calculate style diff: %w", err)
+	}
+	return diffResult.Diff, diffResult.DiffAmount, nil
+}
+
+func (r *ScheduledSnapshotReconciler) diffOptions(spec *ssV1.ScheduledSnapshotSpec, ignoreRegions []diffimage.Rectangle) []diffimage.Option {
+	opts := []diffimage.Option{
+		diffimage.WithAntiAliasingDetection(spec.DetectAntiAliasing),
+		diffimage.WithIgnoreRegions(ignoreRegions...),
+		diffimage.WithMemoryBudget(r.DiffMemoryBudget),
+		diffimage.WithSizeMismatch(spec.SizeMismatch, r.padColor(spec.PadColor)),
+		diffimage.WithPalette(r.palette(spec.ScreenshotDiffPalette)),
+	}
+	if spec.ScreenshotDiffStrokeWidth > 0 {
+		opts = append(opts, diffimage.WithStrokeWidth(spec.ScreenshotDiffStrokeWidth))
+	}
+	if spec.PerceptualHashDistance != nil {
+		opts = append(opts, diffimage.WithPerceptualHash(*spec.PerceptualHashDistance))
+	}
+	if spec.ColorDistance == "perceptual" {
+		opts = append(opts, diffimage.WithPerceptualColorDistance(r.colorTolerance(spec.ColorTolerance)))
+	}
+	return opts
+}
+
+// colorTolerance falls back to the default tolerance when the field was not defaulted. Zero is a valid tolerance.
+func (r *ScheduledSnapshotReconciler) colorTolerance(tolerance *float64) float64 {
+	if tolerance == nil {
+		return 0.1
+	}
+	return *tolerance
+}
+
+// palette falls back to the default palette for an unknown name.
+func (r *ScheduledSnapshotReconciler) palette(name string) diffimage.Palette {
+	palette, err := diffimage.PaletteByName(name)
+	if err != nil {
+		return diffimage.DefaultPalette()
+	}
+	return palette
+}
+
+// padColor returns nil for an empty or invalid color so that the differ falls back to white.
+func (r *ScheduledSnapshotReconciler) padColor(value string) color.Color {
+	c, err := diffimage.ParseHexColor(value)
+	if err != nil {
+		return nil
+	}
+	return c
+}
+
+func (r *ScheduledSnapshotReconciler) ignoreSelectors(ignoreRegions []ssV1.IgnoreRegion) []string {
+	var selectors []string
+	for _, ignoreRegion := range ignoreRegions {
+		if ignoreRegion.Selector != "" {
+			selectors = append(selectors, ignoreRegion.Selector)
+		}
+	}
+	return selectors
+}
+
+// ignoreRegions combines the rectangles of the spec with the bounding boxes resolved from its selectors on each capture.
+func (r *ScheduledSnapshotReconciler) ignoreRegions(ignoreRegions []ssV1.IgnoreRegion, boundingBoxes ...[]ssV1.Region) []diffimage.Rectangle {
+	var rectangles []diffimage.Rectangle
+	for _, ignoreRegion := range ignoreRegions {
+		if ignoreRegion.Selector == "" {
+			rectangles = append(rectangles, diffimage.Rectangle{X: ignoreRegion.X, Y: ignoreRegion.Y, Width: ignoreRegion.Width, Height: ignoreRegion.Height})
+		}
+	}
+	for _, regions := range boundingBoxes {
+		for _, region := range regions {
+			rectangles = append(rectangles, diffimage.Rectangle{X: region.X, Y: region.Y, Width: region.Width, Height: region.Height})
+		}
+	}
+	return rectangles
+}
+
+func (r *ScheduledSnapshotReconciler) regions(boundingBoxes []capture.BoundingBox) []ssV1.Region {
+	if len(boundingBoxes) == 0 {
+		return nil
+	}
+
+	regions := make([]ssV1.Region, len(boundingBoxes))
+	for i, boundingBox := range boundingBoxes {
+		regions[i] = ssV1.Region{X: boundingBox.X, Y: boundingBox.Y, Width: boundingBox.Width, Height: boundingBox.Height}
+	}
+	return regions
+}
+
+func (r *ScheduledSnapshotReconciler) dimensionChange(change *diffimage.DimensionChange) *ssV1.DimensionChange {
+	if change == nil {
+		return nil
+	}
+
+	return &ssV1.DimensionChange{
+		BaselineWidth:  change.BaselineWidth,
+		BaselineHeight: change.BaselineHeight,
+		TargetWidth:    change.TargetWidth,
+		TargetHeight:   change.TargetHeight,
+	}
+}
+
+func (r *ScheduledSnapshotReconciler) bands(bands []diffimage.Band) []ssV1.Band {
+	if len(bands) == 0 {
+		return nil
+	}
+
+	result := make([]ssV1.Band, len(bands))
+	for i, band := range bands {
+		result[i] = ssV1.Band{Y: band.Y, Height: band.Height}
+	}
+	return result
+}
+
+func (r *ScheduledSnapshotReconciler) capturer(captureMode string) capture.Capturer {
+	if captureMode == "http" {
+		return r.HTTPCapturer
+	}
+	return r.Capturer
+}
+
+// pdfOptions returns the capture options of the "pdf" capture mode, falling back to A4 paper.
+func (r *ScheduledSnapshotReconciler) pdfOptions(spec *ssV1.PDFSpec) *capture.PDFOptions {
+	pdfOptions := &capture.PDFOptions{
+		Format: "A4",
+	}
+	if spec != nil {
+		if spec.Format != "" {
+			pdfOptions.Format = spec.Format
+		}
+		pdfOptions.Landscape = spec.Landscape
+		pdfOptions.MarginTop = spec.Margin.Top
+		pdfOptions.MarginRight = spec.Margin.Right
+		pdfOptions.MarginBottom = spec.Margin.Bottom
+		pdfOptions.MarginLeft = spec.Margin.Left
+	}
+	return pdfOptions
+}
+
+func (r *ScheduledSnapshotReconciler) generatePageDiffs(baselinePages [][]byte, targetPages [][]byte, format string, screenshotFormat string, opts ...diffimage.Option) ([][]byte, []ssV1.PageDiff, error) {
+	pageCount := max(len(baselinePages), len(targetPages))
+	diffImages := make([][]byte, pageCount)
+	pageDiffs := make([]ssV1.PageDiff, pageCount)
+	for i := 0; i < pageCount; i++ {
+		pageDiffs[i].Page = i + 1
+		if i >= len(baselinePages) || i >= len(targetPages) {
+			pageDiffs[i].DiffAmount = 1.0
+			continue
+		}
+
+		baselineImage, targetImage, err := r.decodeScreenshots(baselinePages[i], targetPages[i])
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to decode page %d: %w", i+1, err)
+		}
+		diffImage, diffResult, err := r.generateDiff(baselineImage, targetImage, format, screenshotFormat, opts...)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to generate diff of page %d: %w", i+1, err)
+		}
+		diffImages[i] = diffImage
+		pageDiffs[i].DiffAmount = diffResult.DiffAmount
+	}
+	return diffImages, pageDiffs, nil
+}
+
+// updateScheduledSnapshotStatus rotates the previous target artifacts into the baseline and records the
+// artifacts of the current run given in status.
+func (r *ScheduledSnapshotReconciler) updateScheduledSnapshotStatus(ctx context.Context, scheduledSnapshot *ssV1.ScheduledSnapshot, status ssV1.ScheduledSnapshotStatus) error {
 	now := metaV1.Now()
 
+	status.BaselineURL = scheduledSnapshot.Status.BaselineURL
+	if scheduledSnapshot.Status.TargetURL != "" {
+		status.BaselineURL = scheduledSnapshot.Status.TargetURL
+	}
+	status.BaselineIgnoreRegions = scheduledSnapshot.Status.BaselineIgnoreRegions
 	if scheduledSnapshot.Status.TargetURL != "" {
-		scheduledSnapshot.Status.BaselineURL = scheduledSnapshot.Status.TargetURL
+		status.BaselineIgnoreRegions = scheduledSnapshot.Status.TargetIgnoreRegions
 	}
+	status.BaselineHTMLURL = scheduledSnapshot.Status.BaselineHTMLURL
 	if scheduledSnapshot.Status.TargetHTMLURL != "" {
-		scheduledSnapshot.Status.BaselineHTMLURL = scheduledSnapshot.Status.TargetHTMLURL
+		status.BaselineHTMLURL = scheduledSnapshot.Status.TargetHTMLURL
+	}
+	status.BaselineTextURL = scheduledSnapshot.Status.BaselineTextURL
+	if scheduledSnapshot.Status.TargetTextURL != "" {
+		status.BaselineTextURL = scheduledSnapshot.Status.TargetTextURL
+	}
+	status.BaselineStylesURL = scheduledSnapshot.Status.BaselineStylesURL
+	if scheduledSnapshot.Status.TargetStylesURL != "" {
+		status.BaselineStylesURL = scheduledSnapshot.Status.TargetStylesURL
 	}
+	status.BaselinePDFURL = scheduledSnapshot.Status.BaselinePDFURL
+	if scheduledSnapshot.Status.TargetPDFURL != "" {
+		status.BaselinePDFURL = scheduledSnapshot.Status.TargetPDFURL
+	}
+	status.BaselinePageURLs = scheduledSnapshot.Status.BaselinePageURLs
+	if len(scheduledSnapshot.Status.TargetPageURLs) > 0 {
+		status.BaselinePageURLs = scheduledSnapshot.Status.TargetPageURLs
+	}
+	status.LastSnapshotTime = &now
 
-	scheduledSnapshot.Status.TargetURL = imageURL
-	scheduledSnapshot.Status.TargetHTMLURL = htmlURL
-	scheduledSnapshot.Status.ScreenshotDiffURL = diffURL
-	scheduledSnapshot.Status.ScreenshotDiffAmount = diffAmount
-	scheduledSnapshot.Status.HTMLDiffURL = htmlDiffURL
-	scheduledSnapshot.Status.HTMLDiffAmount = htmlDiffAmount
-	scheduledSnapshot.Status.LastSnapshotTime = &now
+	scheduledSnapshot.Status = status
 
 	if err := r.Status().Update(ctx, scheduledSnapshot); err != nil {
 		return xerrors.Errorf("failed to update scheduled snapshot status: %w", err)
@@ -288,13 +846,124 @@ func (r *ScheduledSnapshotReconciler) createOrUpdateCronJob(ctx context.Context,
 		scheduledSnapshot.Spec.Target,
 		"--screenshot-diff-format", scheduledSnapshot.Spec.ScreenshotDiffFormat,
 		"--html-diff-format", scheduledSnapshot.Spec.HTMLDiffFormat,
+		"--text-diff-format", scheduledSnapshot.Spec.TextDiffFormat,
 		"--callback-url", fmt.Sprintf("http://%s/api/%s/%s/%s/%s/%s/artifacts", r.DistributedCallbackHost, scheduledSnapshot.Namespace, ssV1.GroupVersion.Group, ssV1.GroupVersion.Version, "scheduledsnapshot", scheduledSnapshot.Name),
 	}
 
+	if scheduledSnapshot.Spec.DetectAntiAliasing {
+		args = append(args, "--detect-anti-aliasing")
+	}
+
+	if selectors := r.ignoreSelectors(scheduledSnapshot.Spec.IgnoreRegions); len(selectors) > 0 {
+		value, err := json.Marshal(selectors)
+		if err != nil {
+			return xerrors.Errorf("failed to marshal ignore selectors: %w", err)
+		}
+		args = append(args, "--ignore-selectors", string(value))
+	}
+
+	if rectangles := r.ignoreRegions(scheduledSnapshot.Spec.IgnoreRegions); len(rectangles) > 0 {
+		values := make([]string, len(rectangles))
+		for i, rectangle := range rectangles {
+			values[i] = fmt.Sprintf("%d,%d,%d,%d", rectangle.X, rectangle.Y, rectangle.Width, rectangle.Height)
+		}
+		args = append(args, "--ignore-rectangles", strings.Join(values, ";"))
+	}
+
+	if scheduledSnapshot.Spec.ScreenshotFormat != "" {
+		args = append(args, "--screenshot-format", scheduledSnapshot.Spec.ScreenshotFormat)
+	}
+
+	if len(scheduledSnapshot.Spec.ScreenshotComposites) > 0 {
+		formats := make([]string, len(scheduledSnapshot.Spec.ScreenshotComposites))
+		for i, format := range scheduledSnapshot.Spec.ScreenshotComposites {
+			formats[i] = string(format)
+		}
+		args = append(args, "--screenshot-composites", strings.Join(formats, ","))
+	}
+
+	if normalizer := r.htmlNormalizer(scheduledSnapshot.Spec.HTMLNormalization); normalizer != nil {
+		value, err := json.Marshal(normalizer)
+		if err != nil {
+			return xerrors.Errorf("failed to marshal HTML normalization: %w", err)
+		}
+		args = append(args, "--html-normalization", string(value))
+	}
+
+	if ignore := r.htmlIgnore(scheduledSnapshot.Spec.HTMLIgnore); ignore != nil {
+		value, err := json.Marshal(ignore)
+		if err != nil {
+			return xerrors.Errorf("failed to marshal HTML ignore: %w", err)
+		}
+		args = append(args, "--html-ignore", string(value))
+	}
+
+	if scheduledSnapshot.Spec.HTMLDiffContextLines != nil {
+		args = append(args, "--html-diff-context-lines", strconv.Itoa(*scheduledSnapshot.Spec.HTMLDiffContextLines))
+	}
+
+	if scheduledSnapshot.Spec.HTMLDiffHighlight != "" {
+		args = append(args, "--html-diff-highlight", scheduledSnapshot.Spec.HTMLDiffHighlight)
+	}
+
+	if scheduledSnapshot.Spec.PerceptualHashDistance != nil {
+		args = append(args, "--perceptual-hash-distance", strconv.Itoa(*scheduledSnapshot.Spec.PerceptualHashDistance))
+	}
+
+	if scheduledSnapshot.Spec.ColorDistance != "" {
+		args = append(args, "--color-distance", scheduledSnapshot.Spec.ColorDistance)
+		args = append(args, "--color-tolerance", strconv.FormatFloat(r.colorTolerance(scheduledSnapshot.Spec.ColorTolerance), 'f', -1, 64))
+	}
+
+	if scheduledSnapshot.Spec.ScreenshotDiffPalette != "" {
+		args = append(args, "--screenshot-diff-palette", scheduledSnapshot.Spec.ScreenshotDiffPalette)
+	}
+
+	if scheduledSnapshot.Spec.ScreenshotDiffStrokeWidth > 0 {
+		args = append(args, "--screenshot-diff-stroke-width", strconv.Itoa(scheduledSnapshot.Spec.ScreenshotDiffStrokeWidth))
+	}
+
+	if scheduledSnapshot.Spec.SizeMismatch != "" {
+		args = append(args, "--size-mismatch", scheduledSnapshot.Spec.SizeMismatch)
+	}
+
+	if scheduledSnapshot.Spec.PadColor != "" {
+		args = append(args, "--pad-color", scheduledSnapshot.Spec.PadColor)
+	}
+
+	if r.DiffMemoryBudget > 0 {
+		args = append(args, "--diff-memory-budget", strconv.Itoa(r.DiffMemoryBudget))
+	}
+
 	if len(scheduledSnapshot.Spec.MaskSelectors) > 0 {
 		args = append(args, "--mask-selectors", strings.Join(scheduledSnapshot.Spec.MaskSelectors, ","))
 	}
 
+	if len(scheduledSnapshot.Spec.StyleSelectors) > 0 {
+		value, err := json.Marshal(scheduledSnapshot.Spec.StyleSelectors)
+		if err != nil {
+			return xerrors.Errorf("failed to marshal style selectors: %w", err)
+		}
+		args = append(args, "--style-selectors", string(value))
+	}
+
+	if len(scheduledSnapshot.Spec.StyleProperties) > 0 {
+		args = append(args, "--style-properties", strings.Join(scheduledSnapshot.Spec.StyleProperties, ","))
+	}
+
+	if scheduledSnapshot.Spec.CaptureMode != "" {
+		args = append(args, "--capture-mode", scheduledSnapshot.Spec.CaptureMode)
+	}
+
+	if scheduledSnapshot.Spec.CaptureMode == "pdf" {
+		pdfOptions := r.pdfOptions(scheduledSnapshot.Spec.PDF)
+		args = append(args,
+			"--pdf-format", pdfOptions.Format,
+			"--pdf-landscape="+strconv.FormatBool(pdfOptions.Landscape),
+			"--pdf-margin", strings.Join([]string{pdfOptions.MarginTop, pdfOptions.MarginRight, pdfOptions.MarginBottom, pdfOptions.MarginLeft}, ","),
+		)
+	}
+
 	for key, value := range scheduledSnapshot.Spec.Headers {
 		args = append(args, "-H", fmt.Sprintf("%s: %s", key, value))
 	}
@@ -4,14 +4,19 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
-	"image/jpeg"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
 	"os"
 	ssV1 "snapshot-controller/api/v1"
 	"snapshot-controller/internal/capture"
 	diffimage "snapshot-controller/internal/diff/image"
 	difftext "snapshot-controller/internal/diff/text"
 	"snapshot-controller/internal/storage"
+	"strconv"
 	"strings"
 	"time"
 
@@ -38,10 +43,16 @@ type SnapshotReconciler struct {
 	Recorder record.EventRecorder
 	Capturer capture.Capturer
 	Storage  storage.Storage
+	// HTTPCapturer captures snapshots in the "http" capture mode
+	HTTPCapturer capture.Capturer
 
 	Distributed             bool
 	DistributedCallbackHost string
 	DistributedWorkerImage  string
+
+	// DiffMemoryBudget bounds the bytes allocated for comparing screenshots, excluding the decoded screenshots
+	// themselves. Larger screenshots are compared in strips.
+	DiffMemoryBudget int
 }
 
 func (r *SnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -62,6 +73,11 @@ func (r *SnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, err
 	}
 
+	if err := r.validateSpec(&snapshot.Spec); err != nil {
+		r.Recorder.Eventf(snapshot, coreV1.EventTypeWarning, "InvalidSpec", "Snapshot %q is not processed: %v", snapshot.Name, err)
+		return ctrl.Result{}, nil
+	}
+
 	if r.Distributed {
 		if err := r.createJob(ctx, snapshot); err != nil {
 			return ctrl.Result{}, err
@@ -80,15 +96,22 @@ func (r *SnapshotReconciler) processSnapshot(ctx context.Context, snapshot *ssV1
 	var targetResult *capture.CaptureResult
 
 	captureOptions := capture.CaptureOptions{
-		MaskSelectors: snapshot.Spec.MaskSelectors,
-		Headers:       snapshot.Spec.Headers,
+		MaskSelectors:    snapshot.Spec.MaskSelectors,
+		Headers:          snapshot.Spec.Headers,
+		StyleSelectors:   snapshot.Spec.StyleSelectors,
+		StyleProperties:  snapshot.Spec.StyleProperties,
+		IgnoreSelectors:  r.ignoreSelectors(snapshot.Spec.IgnoreRegions),
+		ScreenshotFormat: snapshot.Spec.ScreenshotFormat,
+	}
+	if snapshot.Spec.CaptureMode == "pdf" {
+		captureOptions.PDF = r.pdfOptions(snapshot.Spec.PDF)
 	}
 
 	{
 		eg, ctx := errgroup.WithContext(ctx)
 
 		eg.Go(func() error {
-			result, err := r.Capturer.Capture(ctx, snapshot.Spec.Baseline, captureOptions)
+			result, err := r.capturer(snapshot.Spec.CaptureMode).Capture(ctx, snapshot.Spec.Baseline, captureOptions)
 			if err != nil {
 				return xerrors.Errorf("failed to capture baseline screenshot: %w", err)
 			}
@@ -97,7 +120,7 @@ func (r *SnapshotReconciler) processSnapshot(ctx context.Context, snapshot *ssV1
 		})
 
 		eg.Go(func() error {
-			result, err := r.Capturer.Capture(ctx, snapshot.Spec.Target, captureOptions)
+			result, err := r.capturer(snapshot.Spec.CaptureMode).Capture(ctx, snapshot.Spec.Target, captureOptions)
 			if err != nil {
 				return xerrors.Errorf("failed to capture target screenshot: %w", err)
 			}
@@ -110,95 +133,281 @@ func (r *SnapshotReconciler) processSnapshot(ctx context.Context, snapshot *ssV1
 		}
 	}
 
-	diffImage, diffAmount, err := r.generateDiff(baselineResult.Screenshot, targetResult.Screenshot, snapshot.Spec.ScreenshotDiffFormat)
-	if err != nil {
-		return xerrors.Errorf("failed to generate diff: %w", err)
+	var diffImage []byte
+	var diffAmount float64
+	var diffRegions []byte
+	var composites []renderedComposite
+	var insertedBands []ssV1.Band
+	var removedBands []ssV1.Band
+	var dimensionChange *ssV1.DimensionChange
+	var err error
+	if baselineResult.Screenshot != nil && targetResult.Screenshot != nil {
+		var baselineImage, targetImage image.Image
+		baselineImage, targetImage, err = r.decodeScreenshots(baselineResult.Screenshot, targetResult.Screenshot)
+		if err != nil {
+			return xerrors.Errorf("failed to decode screenshots: %w", err)
+		}
+		var diffResult *diffimage.DiffResult
+		diffImage, diffResult, err = r.generateDiff(baselineImage, targetImage, snapshot.Spec.ScreenshotDiffFormat, baselineResult.ScreenshotFormat, r.diffOptions(&snapshot.Spec, r.ignoreRegions(snapshot.Spec.IgnoreRegions, r.regions(baselineResult.IgnoreRegions), r.regions(targetResult.IgnoreRegions)))...)
+		if err != nil {
+			return xerrors.Errorf("failed to generate diff: %w", err)
+		}
+		diffAmount = diffResult.DiffAmount
+		insertedBands = r.bands(diffResult.InsertedBands)
+		removedBands = r.bands(diffResult.RemovedBands)
+		dimensionChange = r.dimensionChange(diffResult.DimensionChange)
+		if len(diffResult.Regions) > 0 {
+			diffRegions, err = json.MarshalIndent(diffResult.Regions, "", "  ")
+			if err != nil {
+				return xerrors.Errorf("failed to marshal diff regions: %w", err)
+			}
+		}
+		composites, err = r.generateComposites(baselineImage, targetImage, diffResult.Image, snapshot.Spec.ScreenshotComposites, baselineResult.ScreenshotFormat)
+		if err != nil {
+			return xerrors.Errorf("failed to generate composites: %w", err)
+		}
 	}
 
-	htmlDiff, htmlDiffAmount, err := r.generateHTMLDiff(baselineResult.HTML, targetResult.HTML, snapshot.Spec.HTMLDiffFormat)
+	htmlDiffResult, err := r.generateHTMLDiff(baselineResult.HTML, targetResult.HTML, snapshot.Spec.HTMLDiffFormat, r.htmlNormalizer(snapshot.Spec.HTMLNormalization), r.htmlIgnore(snapshot.Spec.HTMLIgnore), r.lineDiffOptions(&snapshot.Spec, snapshot.Spec.Baseline, snapshot.Spec.Target)...)
 	if err != nil {
 		return xerrors.Errorf("failed to generate HTML diff: %w", err)
 	}
-	var baselineURL string
-	var targetURL string
-	var baselineHTMLURL string
-	var targetHTMLURL string
-	var diffURL string
-	var htmlDiffURL string
+	htmlDiff, htmlDiffAmount, htmlDiffRendered := htmlDiffResult.Diff, htmlDiffResult.DiffAmount, htmlDiffResult.Rendered
+	var htmlDiffChanges []byte
+	if len(htmlDiffResult.Changes) > 0 {
+		htmlDiffChanges, err = json.MarshalIndent(htmlDiffResult.Changes, "", "  ")
+		if err != nil {
+			return xerrors.Errorf("failed to marshal HTML diff changes: %w", err)
+		}
+	}
+
+	var textDiff []byte
+	var textDiffAmount float64
+	if baselineResult.Text != nil && targetResult.Text != nil {
+		textDiff, textDiffAmount, err = r.generateTextDiff(baselineResult.Text, targetResult.Text, snapshot.Spec.TextDiffFormat)
+		if err != nil {
+			return xerrors.Errorf("failed to generate text diff: %w", err)
+		}
+	}
+
+	var styleDiff []byte
+	var styleDiffAmount float64
+	if baselineResult.Styles != nil && targetResult.Styles != nil {
+		styleDiff, styleDiffAmount, err = r.generateStyleDiff(baselineResult.Styles, targetResult.Styles)
+		if err != nil {
+			return xerrors.Errorf("failed to generate style diff: %w", err)
+		}
+	}
+
+	var pageDiffImages [][]byte
+	var pageDiffs []ssV1.PageDiff
+	if baselineResult.Pages != nil && targetResult.Pages != nil {
+		pageDiffImages, pageDiffs, err = r.generatePageDiffs(baselineResult.Pages, targetResult.Pages, snapshot.Spec.ScreenshotDiffFormat, baselineResult.ScreenshotFormat, r.diffOptions(&snapshot.Spec, r.ignoreRegions(snapshot.Spec.IgnoreRegions))...)
+		if err != nil {
+			return xerrors.Errorf("failed to generate page diffs: %w", err)
+		}
+	}
+
+	status := ssV1.SnapshotStatus{
+		ScreenshotDiffAmount:      diffAmount,
+		ScreenshotInsertedBands:   insertedBands,
+		ScreenshotRemovedBands:    removedBands,
+		ScreenshotDimensionChange: dimensionChange,
+		HTMLDiffAmount:            htmlDiffAmount,
+		TextDiffAmount:            textDiffAmount,
+		StyleDiffAmount:           styleDiffAmount,
+		PageDiffs:                 pageDiffs,
+		ScreenshotComposites:      make([]ssV1.Composite, len(composites)),
+	}
 
 	{
 		eg, ctx := errgroup.WithContext(ctx)
 
 		eg.Go(func() error {
-			imageURL, htmlURL, err := r.uploadCapture(ctx, snapshot.Spec.Baseline, baselineResult)
+			urls, err := r.uploadCapture(ctx, snapshot.Spec.Baseline, baselineResult)
 			if err != nil {
 				return err
 			}
-			baselineURL = imageURL
-			baselineHTMLURL = htmlURL
+			status.BaselineURL = urls.screenshotURL
+			status.BaselineHTMLURL = urls.htmlURL
+			status.BaselineTextURL = urls.textURL
+			status.BaselineStylesURL = urls.stylesURL
+			status.BaselinePDFURL = urls.pdfURL
+			status.BaselinePageURLs = urls.pageURLs
 			return nil
 		})
 
 		eg.Go(func() error {
-			imageURL, htmlURL, err := r.uploadCapture(ctx, snapshot.Spec.Target, targetResult)
+			urls, err := r.uploadCapture(ctx, snapshot.Spec.Target, targetResult)
 			if err != nil {
 				return err
 			}
-			targetURL = imageURL
-			targetHTMLURL = htmlURL
+			status.TargetURL = urls.screenshotURL
+			status.TargetHTMLURL = urls.htmlURL
+			status.TargetTextURL = urls.textURL
+			status.TargetStylesURL = urls.stylesURL
+			status.TargetPDFURL = urls.pdfURL
+			status.TargetPageURLs = urls.pageURLs
 			return nil
 		})
 
-		eg.Go(func() error {
-			timestamp := time.Now().Format("20060102150405")
+		timestamp := time.Now().Format("20060102150405")
 
-			h := sha256.New()
-			h.Write([]byte(snapshot.Spec.Baseline + snapshot.Spec.Target))
-			hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
+		h := sha256.New()
+		h.Write([]byte(snapshot.Spec.Baseline + snapshot.Spec.Target))
+		hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
 
-			diffKey := fmt.Sprintf("Snapshot/diff/%s/%s.jpeg", hash, timestamp)
+		if diffImage != nil {
+			eg.Go(func() error {
+				diffKey := fmt.Sprintf("Snapshot/diff/%s/%s.%s", hash, timestamp, diffimage.Extension(baselineResult.ScreenshotFormat))
 
-			url, err := r.Storage.Put(ctx, diffKey, diffImage)
-			if err != nil {
-				return xerrors.Errorf("failed to upload diff image: %w", err)
-			}
-			diffURL = url
-			return nil
-		})
+				url, err := r.Storage.Put(ctx, diffKey, diffImage)
+				if err != nil {
+					return xerrors.Errorf("failed to upload diff image: %w", err)
+				}
+				status.ScreenshotDiffURL = url
+				return nil
+			})
+		}
 
-		eg.Go(func() error {
-			timestamp := time.Now().Format("20060102150405")
+		if diffRegions != nil {
+			eg.Go(func() error {
+				diffRegionsKey := fmt.Sprintf("Snapshot/diff/%s/%s-regions.json", hash, timestamp)
+
+				url, err := r.Storage.Put(ctx, diffRegionsKey, diffRegions)
+				if err != nil {
+					return xerrors.Errorf("failed to upload diff regions: %w", err)
+				}
+				status.ScreenshotDiffRegionsURL = url
+				return nil
+			})
+		}
 
-			h := sha256.New()
-			h.Write([]byte(snapshot.Spec.Baseline + snapshot.Spec.Target))
-			hash := fmt.Sprintf("%x", h.Sum(nil))[:16]
+		for i, composite := range composites {
+			status.ScreenshotComposites[i].Format = composite.format
+			eg.Go(func() error {
+				compositeKey := fmt.Sprintf("Snapshot/diff/%s/%s-%s.%s", hash, timestamp, composite.format, composite.extension)
 
-			htmlDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s.txt", hash, timestamp)
+				url, err := r.Storage.Put(ctx, compositeKey, composite.data)
+				if err != nil {
+					return xerrors.Errorf("failed to upload %s composite: %w", composite.format, err)
+				}
+				status.ScreenshotComposites[i].URL = url
+				return nil
+			})
+		}
+
+		eg.Go(func() error {
+			htmlDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s.%s", hash, timestamp, difftext.Extension(snapshot.Spec.HTMLDiffFormat))
 
 			url, err := r.Storage.Put(ctx, htmlDiffKey, htmlDiff)
 			if err != nil {
 				return xerrors.Errorf("failed to upload HTML diff: %w", err)
 			}
-			htmlDiffURL = url
+			status.HTMLDiffURL = url
 			return nil
 		})
 
+		if htmlDiffChanges != nil {
+			eg.Go(func() error {
+				htmlDiffChangesKey := fmt.Sprintf("Snapshot/diff/%s/%s-changes.json", hash, timestamp)
+
+				url, err := r.Storage.Put(ctx, htmlDiffChangesKey, htmlDiffChanges)
+				if err != nil {
+					return xerrors.Errorf("failed to upload HTML diff changes: %w", err)
+				}
+				status.HTMLDiffChangesURL = url
+				return nil
+			})
+		}
+
+		if htmlDiffRendered != nil {
+			eg.Go(func() error {
+				htmlDiffRenderedKey := fmt.Sprintf("Snapshot/diff/%s/%s.html", hash, timestamp)
+
+				url, err := r.Storage.Put(ctx, htmlDiffRenderedKey, htmlDiffRendered)
+				if err != nil {
+					return xerrors.Errorf("failed to upload rendered HTML diff: %w", err)
+				}
+				status.HTMLDiffRenderedURL = url
+				return nil
+			})
+		}
+
+		if textDiff != nil {
+			eg.Go(func() error {
+				textDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s-text.txt", hash, timestamp)
+
+				url, err := r.Storage.Put(ctx, textDiffKey, textDiff)
+				if err != nil {
+					return xerrors.Errorf("failed to upload text diff: %w", err)
+				}
+				status.TextDiffURL = url
+				return nil
+			})
+		}
+
+		if styleDiff != nil {
+			eg.Go(func() error {
+				styleDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s-styles.txt", hash, timestamp)
+
+				url, err := r.Storage.Put(ctx, styleDiffKey, styleDiff)
+				if err != nil {
+					return xerrors.Errorf("failed to upload style diff: %w", err)
+				}
+				status.StyleDiffURL = url
+				return nil
+			})
+		}
+
+		for i, pageDiffImage := range pageDiffImages {
+			if pageDiffImage == nil {
+				continue
+			}
+			eg.Go(func() error {
+				pageDiffKey := fmt.Sprintf("Snapshot/diff/%s/%s-page-%d.%s", hash, timestamp, i+1, diffimage.Extension(baselineResult.ScreenshotFormat))
+
+				url, err := r.Storage.Put(ctx, pageDiffKey, pageDiffImage)
+				if err != nil {
+					return xerrors.Errorf("failed to upload diff image of page %d: %w", i+1, err)
+				}
+				status.PageDiffs[i].DiffURL = url
+				return nil
+			})
+		}
+
 		if err := eg.Wait(); err != nil {
 			return err
 		}
 	}
 
-	if err := r.updateSnapshotStatus(ctx, snapshot, baselineURL, targetURL, baselineHTMLURL, targetHTMLURL, diffURL, diffAmount, htmlDiffURL, htmlDiffAmount); err != nil {
+	if err := r.updateSnapshotStatus(ctx, snapshot, status); err != nil {
 		return err
 	}
-	r.Recorder.Eventf(snapshot, coreV1.EventTypeNormal, "SnapshotCompleted", "Snapshot completed successfully: %q (screenshot difference: %.2f%%, HTML difference: %.2f%%)", snapshot.Name, diffAmount*100, htmlDiffAmount*100)
+	r.Recorder.Eventf(snapshot, coreV1.EventTypeNormal, "SnapshotCompleted", "Snapshot completed successfully: %q (screenshot difference: %.2f%%, HTML difference: %.2f%%, text difference: %.2f%%, style difference: %.2f%%)", snapshot.Name, diffAmount*100, htmlDiffAmount*100, textDiffAmount*100, styleDiffAmount*100)
 
 	return nil
 }
 
-func (r *SnapshotReconciler) uploadCapture(ctx context.Context, url string, result *capture.CaptureResult) (string, string, error) {
-	var imageURL string
-	var htmlURL string
+// renderedComposite is an encoded composite of the screenshot diff.
+type renderedComposite struct {
+	format    string
+	extension string
+	data      []byte
+}
+
+// captureURLs holds the storage URLs of the artifacts of a single capture.
+type captureURLs struct {
+	screenshotURL string
+	htmlURL       string
+	textURL       string
+	stylesURL     string
+	pdfURL        string
+	pageURLs      []string
+}
+
+func (r *SnapshotReconciler) uploadCapture(ctx context.Context, url string, result *capture.CaptureResult) (*captureURLs, error) {
+	urls := &captureURLs{}
 	{
 		eg, ctx := errgroup.WithContext(ctx)
 
@@ -210,15 +419,17 @@ func (r *SnapshotReconciler) uploadCapture(ctx context.Context, url string, resu
 
 		baseKey := fmt.Sprintf("Snapshot/capture/%s/%s", urlHash, timestamp)
 
-		eg.Go(func() error {
-			imageKey := baseKey + ".jpeg"
-			path, err := r.Storage.Put(ctx, imageKey, result.Screenshot)
-			if err != nil {
-				return xerrors.Errorf("failed to upload screenshot: %w", err)
-			}
-			imageURL = path
-			return nil
-		})
+		if result.Screenshot != nil {
+			eg.Go(func() error {
+				imageKey := baseKey + "." + diffimage.Extension(result.ScreenshotFormat)
+				path, err := r.Storage.Put(ctx, imageKey, result.Screenshot)
+				if err != nil {
+					return xerrors.Errorf("failed to upload screenshot: %w", err)
+				}
+				urls.screenshotURL = path
+				return nil
+			})
+		}
 
 		eg.Go(func() error {
 			htmlKey := baseKey + ".html"
@@ -226,29 +437,74 @@ func (r *SnapshotReconciler) uploadCapture(ctx context.Context, url string, resu
 			if err != nil {
 				return xerrors.Errorf("failed to upload HTML: %w", err)
 			}
-			htmlURL = path
+			urls.htmlURL = path
 			return nil
 		})
 
+		if result.Text != nil {
+			eg.Go(func() error {
+				textKey := baseKey + ".txt"
+				path, err := r.Storage.Put(ctx, textKey, result.Text)
+				if err != nil {
+					return xerrors.Errorf("failed to upload text: %w", err)
+				}
+				urls.textURL = path
+				return nil
+			})
+		}
+
+		if result.Styles != nil {
+			eg.Go(func() error {
+				stylesKey := baseKey + "-styles.json"
+				path, err := r.Storage.Put(ctx, stylesKey, result.Styles)
+				if err != nil {
+					return xerrors.Errorf("failed to upload styles: %w", err)
+				}
+				urls.stylesURL = path
+				return nil
+			})
+		}
+
+		if result.PDF != nil {
+			eg.Go(func() error {
+				pdfKey := baseKey + ".pdf"
+				path, err := r.Storage.Put(ctx, pdfKey, result.PDF)
+				if err != nil {
+					return xerrors.Errorf("failed to upload PDF: %w", err)
+				}
+				urls.pdfURL = path
+				return nil
+			})
+		}
+
+		if result.Pages != nil {
+			urls.pageURLs = make([]string, len(result.Pages))
+		}
+		for i, page := range result.Pages {
+			eg.Go(func() error {
+				pageKey := fmt.Sprintf("%s-page-%d.%s", baseKey, i+1, diffimage.Extension(result.ScreenshotFormat))
+				path, err := r.Storage.Put(ctx, pageKey, page)
+				if err != nil {
+					return xerrors.Errorf("failed to upload page %d: %w", i+1, err)
+				}
+				urls.pageURLs[i] = path
+				return nil
+			})
+		}
+
 		if err := eg.Wait(); err != nil {
-			return "", "", err
+			return nil, err
 		}
 	}
 
-	return imageURL, htmlURL, nil
+	return urls, nil
 }
 
-func (r *SnapshotReconciler) updateSnapshotStatus(ctx context.Context, snapshot *ssV1.Snapshot, baselineURL string, targetURL string, baselineHTMLURL string, targetHTMLURL string, diffURL string, diffAmount float64, htmlDiffURL string, htmlDiffAmount float64) error {
+func (r *SnapshotReconciler) updateSnapshotStatus(ctx context.Context, snapshot *ssV1.Snapshot, status ssV1.SnapshotStatus) error {
 	now := metaV1.Now()
-	snapshot.Status.BaselineURL = baselineURL
-	snapshot.Status.TargetURL = targetURL
-	snapshot.Status.BaselineHTMLURL = baselineHTMLURL
-	snapshot.Status.TargetHTMLURL = targetHTMLURL
-	snapshot.Status.ScreenshotDiffURL = diffURL
-	snapshot.Status.ScreenshotDiffAmount = diffAmount
-	snapshot.Status.HTMLDiffURL = htmlDiffURL
-	snapshot.Status.HTMLDiffAmount = htmlDiffAmount
-	snapshot.Status.LastSnapshotTime = &now
+	status.LastSnapshotTime = &now
+	status.ObservedGeneration = snapshot.Status.ObservedGeneration
+	snapshot.Status = status
 
 	if err := r.Status().Update(ctx, snapshot); err != nil {
 		return xerrors.Errorf("failed to update snapshot status: %w", err)
@@ -256,55 +512,340 @@ func (r *SnapshotReconciler) updateSnapshotStatus(ctx context.Context, snapshot
 	return nil
 }
 
-func (r *SnapshotReconciler) generateDiff(baselineData []byte, targetData []byte, format string) ([]byte, float64, error) {
-	baselineImage, err := jpeg.Decode(bytes.NewReader(baselineData))
+// decodeScreenshots decodes the screenshots once for the diff and the composites.
+func (r *SnapshotReconciler) decodeScreenshots(baselineData []byte, targetData []byte) (image.Image, image.Image, error) {
+	baselineImage, _, err := image.Decode(bytes.NewReader(baselineData))
 	if err != nil {
-		return nil, 0.0, xerrors.Errorf("failed to decode baseline image: %w", err)
+		return nil, nil, xerrors.Errorf("failed to decode baseline image: %w", err)
 	}
 
-	targetImage, err := jpeg.Decode(bytes.NewReader(targetData))
-	if err != nil {
-		return nil, 0.0, xerrors.Errorf("failed to decode target image: %w", err)
+	// Byte-identical screenshots share the decoded image, which the differs return as is without comparing pixels.
+	targetImage := baselineImage
+	if !bytes.Equal(baselineData, targetData) {
+		targetImage, _, err = image.Decode(bytes.NewReader(targetData))
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to decode target image: %w", err)
+		}
 	}
+	return baselineImage, targetImage, nil
+}
 
+func (r *SnapshotReconciler) generateDiff(baselineImage image.Image, targetImage image.Image, format string, screenshotFormat string, opts ...diffimage.Option) ([]byte, *diffimage.DiffResult, error) {
 	var differ diffimage.Differ
 	switch format {
 	case "rectangle":
-		differ = diffimage.NewRectangleDiff()
+		differ = diffimage.NewRectangleDiff(opts...)
+	case "ssim":
+		differ = diffimage.NewSSIMDiff(opts...)
+	case "shift":
+		differ = diffimage.NewShiftDiff(0.1, opts...)
 	case "pixel":
-		differ = diffimage.NewPixelDiff(0.1)
+		differ = diffimage.NewPixelDiff(0.1, opts...)
 	default:
-		return nil, 0.0, xerrors.Errorf("unknown diff format: %s", format)
+		return nil, nil, xerrors.Errorf("unknown diff format: %s", format)
 	}
 
 	diffResult := differ.Calculate(baselineImage, targetImage)
 
-	var buffer bytes.Buffer
-	err = jpeg.Encode(&buffer, diffResult.Image, &jpeg.Options{Quality: 90})
+	diffImage, err := diffimage.Encode(diffResult.Image, screenshotFormat)
 	if err != nil {
-		return nil, 0.0, xerrors.Errorf("failed to encode diff image: %w", err)
+		return nil, nil, xerrors.Errorf("failed to encode diff image: %w", err)
+	}
+
+	return diffImage, diffResult, nil
+}
+
+func (r *SnapshotReconciler) generateComposites(baselineImage image.Image, targetImage image.Image, diffImage image.Image, formats []ssV1.CompositeFormat, screenshotFormat string) ([]renderedComposite, error) {
+	if len(formats) == 0 {
+		return nil, nil
 	}
 
-	return buffer.Bytes(), diffResult.DiffAmount, nil
+	composites := make([]renderedComposite, len(formats))
+	for i, format := range formats {
+		data, extension, err := diffimage.RenderComposite(string(format), baselineImage, targetImage, diffImage, screenshotFormat)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to render %s composite: %w", format, err)
+		}
+		composites[i] = renderedComposite{
+			format:    string(format),
+			extension: extension,
+			data:      data,
+		}
+	}
+	return composites, nil
 }
 
-func (r *SnapshotReconciler) generateHTMLDiff(baselineHTML []byte, targetHTML []byte, format string) ([]byte, float64, error) {
+func (r *SnapshotReconciler) generateHTMLDiff(baselineHTML []byte, targetHTML []byte, format string, normalizer *difftext.Normalizer, ignore *difftext.Ignore, opts ...difftext.LineDiffOption) (*difftext.DiffResult, error) {
+	if normalizer != nil {
+		var err error
+		baselineHTML, err = normalizer.Normalize(baselineHTML)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to normalize baseline HTML: %w", err)
+		}
+		targetHTML, err = normalizer.Normalize(targetHTML)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to normalize target HTML: %w", err)
+		}
+	}
+
 	var differ difftext.Differ
 	switch format {
 	case "line":
-		differ = difftext.NewLineDiff()
+		differ = difftext.NewLineDiff(append(opts, difftext.WithIgnore(ignore))...)
+	case "dom":
+		differ = difftext.NewDOMDiff(difftext.WithDOMIgnore(ignore))
 	default:
-		return nil, 0.0, xerrors.Errorf("unknown HTML diff format: %s", format)
+		return nil, xerrors.Errorf("unknown HTML diff format: %s", format)
 	}
 
 	diffResult, err := differ.Calculate(baselineHTML, targetHTML)
 
 	if err != nil {
-		return nil, 0.0, xerrors.Errorf("failed to calculate HTML diff: %w", err)
+		return nil, xerrors.Errorf("failed to calculate HTML diff: %w", err)
+	}
+	return diffResult, nil
+}
+
+func (r *SnapshotReconciler) htmlNormalizer(normalization *ssV1.HTMLNormalization) *difftext.Normalizer {
+	if normalization == nil {
+		return nil
+	}
+	replacements := make([]difftext.Replacement, len(normalization.Replacements))
+	for i, replacement := range normalization.Replacements {
+		replacements[i] = difftext.Replacement{Pattern: replacement.Pattern, Replacement: replacement.Replacement}
+	}
+	return &difftext.Normalizer{
+		PrettyPrint:        normalization.PrettyPrint,
+		SortAttributes:     normalization.SortAttributes,
+		CollapseWhitespace: normalization.CollapseWhitespace,
+		DropComments:       normalization.DropComments,
+		Replacements:       replacements,
+	}
+}
+
+// validateSpec reports the errors of the spec that retrying cannot fix, so that they surface once instead of failing
+// every reconcile.
+func (r *SnapshotReconciler) validateSpec(spec *ssV1.SnapshotSpec) error {
+	if normalizer := r.htmlNormalizer(spec.HTMLNormalization); normalizer != nil {
+		if err := normalizer.Validate(); err != nil {
+			return xerrors.Errorf("invalid htmlNormalization: %w", err)
+		}
+	}
+	if ignore := r.htmlIgnore(spec.HTMLIgnore); ignore != nil {
+		if err := ignore.Validate(); err != nil {
+			return xerrors.Errorf("invalid htmlIgnore: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *SnapshotReconciler) htmlIgnore(ignore *ssV1.HTMLIgnore) *difftext.Ignore {
+	if ignore == nil {
+		return nil
+	}
+	return &difftext.Ignore{
+		Selectors:  ignore.Selectors,
+		Attributes: ignore.Attributes,
+		Lines:      ignore.Lines,
+	}
+}
+
+// lineDiffOptions names the baseline and the target in the headers of the unified diff.
+func (r *SnapshotReconciler) lineDiffOptions(spec *ssV1.SnapshotSpec, baselineLabel string, targetLabel string) []difftext.LineDiffOption {
+	opts := []difftext.LineDiffOption{difftext.WithLabels(baselineLabel, targetLabel)}
+	if spec.HTMLDiffContextLines != nil {
+		opts = append(opts, difftext.WithContextLines(*spec.HTMLDiffContextLines))
+	}
+	if spec.HTMLDiffHighlight != "" {
+		opts = append(opts, difftext.WithHighlight(spec.HTMLDiffHighlight))
+	}
+	return opts
+}
+
+func (r *SnapshotReconciler) generateTextDiff(baselineText []byte, targetText []byte, format string) ([]byte, float64, error) {
+	var differ difftext.Differ
+	switch format {
+	case "word":
+		differ = difftext.NewWordDiff()
+	default:
+		return nil, 0.0, xerrors.Errorf("unknown text diff format: %s", format)
+	}
+
+	diffResult, err := differ.Calculate(baselineText, targetText)
+
+	if err != nil {
+		return nil, 0.0, xerrors.Errorf("failed to calculate text diff: %w", err)
 	}
 	return diffResult.Diff, diffResult.DiffAmount, nil
 }
 
+func (r *SnapshotReconciler) generateStyleDiff(baselineStyles []byte, targetStyles []byte) ([]byte, float64, error) {
+	diffResult, err := difftext.NewStyleDiff().Calculate(baselineStyles, targetStyles)
+	if err != nil {
+		return nil, 0.0, xerrors.Errorf("failed to calculate style diff: %w", err)
+	}
+	return diffResult.Diff, diffResult.DiffAmount, nil
+}
+
+func (r *SnapshotReconciler) diffOptions(spec *ssV1.SnapshotSpec, ignoreRegions []diffimage.Rectangle) []diffimage.Option {
+	opts := []diffimage.Option{
+		diffimage.WithAntiAliasingDetection(spec.DetectAntiAliasing),
+		diffimage.WithIgnoreRegions(ignoreRegions...),
+		diffimage.WithMemoryBudget(r.DiffMemoryBudget),
+		diffimage.WithSizeMismatch(spec.SizeMismatch, r.padColor(spec.PadColor)),
+		diffimage.WithPalette(r.palette(spec.ScreenshotDiffPalette)),
+	}
+	if spec.ScreenshotDiffStrokeWidth > 0 {
+		opts = append(opts, diffimage.WithStrokeWidth(spec.ScreenshotDiffStrokeWidth))
+	}
+	if spec.PerceptualHashDistance != nil {
+		opts = append(opts, diffimage.WithPerceptualHash(*spec.PerceptualHashDistance))
+	}
+	if spec.ColorDistance == "perceptual" {
+		opts = append(opts, diffimage.WithPerceptualColorDistance(r.colorTolerance(spec.ColorTolerance)))
+	}
+	return opts
+}
+
+// colorTolerance falls back to the default tolerance when the field was not defaulted. Zero is a valid tolerance.
+func (r *SnapshotReconciler) colorTolerance(tolerance *float64) float64 {
+	if tolerance == nil {
+		return 0.1
+	}
+	return *tolerance
+}
+
+// palette falls back to the default palette for an unknown name.
+func (r *SnapshotReconciler) palette(name string) diffimage.Palette {
+	palette, err := diffimage.PaletteByName(name)
+	if err != nil {
+		return diffimage.DefaultPalette()
+	}
+	return palette
+}
+
+// padColor returns nil for an empty or invalid color so that the differ falls back to white.
+func (r *SnapshotReconciler) padColor(value string) color.Color {
+	c, err := diffimage.ParseHexColor(value)
+	if err != nil {
+		return nil
+	}
+	return c
+}
+
+func (r *SnapshotReconciler) ignoreSelectors(ignoreRegions []ssV1.IgnoreRegion) []string {
+	var selectors []string
+	for _, ignoreRegion := range ignoreRegions {
+		if ignoreRegion.Selector != "" {
+			selectors = append(selectors, ignoreRegion.Selector)
+		}
+	}
+	return selectors
+}
+
+// ignoreRegions combines the rectangles of the spec with the bounding boxes resolved from its selectors on each capture.
+func (r *SnapshotReconciler) ignoreRegions(ignoreRegions []ssV1.IgnoreRegion, boundingBoxes ...[]ssV1.Region) []diffimage.Rectangle {
+	var rectangles []diffimage.Rectangle
+	for _, ignoreRegion := range ignoreRegions {
+		if ignoreRegion.Selector == "" {
+			rectangles = append(rectangles, diffimage.Rectangle{X: ignoreRegion.X, Y: ignoreRegion.Y, Width: ignoreRegion.Width, Height: ignoreRegion.Height})
+		}
+	}
+	for _, regions := range boundingBoxes {
+		for _, region := range regions {
+			rectangles = append(rectangles, diffimage.Rectangle{X: region.X, Y: region.Y, Width: region.Width, Height: region.Height})
+		}
+	}
+	return rectangles
+}
+
+func (r *SnapshotReconciler) regions(boundingBoxes []capture.BoundingBox) []ssV1.Region {
+	if len(boundingBoxes) == 0 {
+		return nil
+	}
+
+	regions := make([]ssV1.Region, len(boundingBoxes))
+	for i, boundingBox := range boundingBoxes {
+		regions[i] = ssV1.Region{X: boundingBox.X, Y: boundingBox.Y, Width: boundingBox.Width, Height: boundingBox.Height}
+	}
+	return regions
+}
+
+func (r *SnapshotReconciler) dimensionChange(change *diffimage.DimensionChange) *ssV1.DimensionChange {
+	if change == nil {
+		return nil
+	}
+
+	return &ssV1.DimensionChange{
+		BaselineWidth:  change.BaselineWidth,
+		BaselineHeight: change.BaselineHeight,
+		TargetWidth:    change.TargetWidth,
+		TargetHeight:   change.TargetHeight,
+	}
+}
+
+func (r *SnapshotReconciler) bands(bands []diffimage.Band) []ssV1.Band {
+	if len(bands) == 0 {
+		return nil
+	}
+
+	result := make([]ssV1.Band, len(bands))
+	for i, band := range bands {
+		result[i] = ssV1.Band{Y: band.Y, Height: band.Height}
+	}
+	return result
+}
+
+func (r *SnapshotReconciler) capturer(captureMode string) capture.Capturer {
+	if captureMode == "http" {
+		return r.HTTPCapturer
+	}
+	return r.Capturer
+}
+
+// pdfOptions returns the capture options of the "pdf" capture mode, falling back to A4 paper.
+func (r *SnapshotReconciler) pdfOptions(spec *ssV1.PDFSpec) *capture.PDFOptions {
+	pdfOptions := &capture.PDFOptions{
+		Format: "A4",
+	}
+	if spec != nil {
+		if spec.Format != "" {
+			pdfOptions.Format = spec.Format
+		}
+		pdfOptions.Landscape = spec.Landscape
+		pdfOptions.MarginTop = spec.Margin.Top
+		pdfOptions.MarginRight = spec.Margin.Right
+		pdfOptions.MarginBottom = spec.Margin.Bottom
+		pdfOptions.MarginLeft = spec.Margin.Left
+	}
+	return pdfOptions
+}
+
+func (r *SnapshotReconciler) generatePageDiffs(baselinePages [][]byte, targetPages [][]byte, format string, screenshotFormat string, opts ...diffimage.Option) ([][]byte, []ssV1.PageDiff, error) {
+	pageCount := max(len(baselinePages), len(targetPages))
+	diffImages := make([][]byte, pageCount)
+	pageDiffs := make([]ssV1.PageDiff, pageCount)
+	for i := 0; i < pageCount; i++ {
+		pageDiffs[i].Page = i + 1
+		if i >= len(baselinePages) || i >= len(targetPages) {
+			pageDiffs[i].DiffAmount = 1.0
+			continue
+		}
+
+		baselineImage, targetImage, err := r.decodeScreenshots(baselinePages[i], targetPages[i])
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to decode page %d: %w", i+1, err)
+		}
+		diffImage, diffResult, err := r.generateDiff(baselineImage, targetImage, format, screenshotFormat, opts...)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to generate diff of page %d: %w", i+1, err)
+		}
+		diffImages[i] = diffImage
+		pageDiffs[i].DiffAmount = diffResult.DiffAmount
+	}
+	return diffImages, pageDiffs, nil
+}
+
 func (r *SnapshotReconciler) createJob(ctx context.Context, snapshot *ssV1.Snapshot) error {
 	jobName := fmt.Sprintf("snapshot-%s-%d", snapshot.Name, time.Now().Unix())
 
@@ -313,13 +854,124 @@ func (r *SnapshotReconciler) createJob(ctx context.Context, snapshot *ssV1.Snaps
 		snapshot.Spec.Target,
 		"--screenshot-diff-format", snapshot.Spec.ScreenshotDiffFormat,
 		"--html-diff-format", snapshot.Spec.HTMLDiffFormat,
+		"--text-diff-format", snapshot.Spec.TextDiffFormat,
 		"--callback", fmt.Sprintf("http://%s/api/%s/%s/%s/%s/%s/artifacts", r.DistributedCallbackHost, snapshot.Namespace, ssV1.GroupVersion.Group, ssV1.GroupVersion.Version, "snapshot", snapshot.Name),
 	}
 
+	if snapshot.Spec.DetectAntiAliasing {
+		args = append(args, "--detect-anti-aliasing")
+	}
+
+	if selectors := r.ignoreSelectors(snapshot.Spec.IgnoreRegions); len(selectors) > 0 {
+		value, err := json.Marshal(selectors)
+		if err != nil {
+			return xerrors.Errorf("failed to marshal ignore selectors: %w", err)
+		}
+		args = append(args, "--ignore-selectors", string(value))
+	}
+
+	if rectangles := r.ignoreRegions(snapshot.Spec.IgnoreRegions); len(rectangles) > 0 {
+		values := make([]string, len(rectangles))
+		for i, rectangle := range rectangles {
+			values[i] = fmt.Sprintf("%d,%d,%d,%d", rectangle.X, rectangle.Y, rectangle.Width, rectangle.Height)
+		}
+		args = append(args, "--ignore-rectangles", strings.Join(values, ";"))
+	}
+
+	if snapshot.Spec.ScreenshotFormat != "" {
+		args = append(args, "--screenshot-format", snapshot.Spec.ScreenshotFormat)
+	}
+
+	if len(snapshot.Spec.ScreenshotComposites) > 0 {
+		formats := make([]string, len(snapshot.Spec.ScreenshotComposites))
+		for i, format := range snapshot.Spec.ScreenshotComposites {
+			formats[i] = string(format)
+		}
+		args = append(args, "--screenshot-composites", strings.Join(formats, ","))
+	}
+
+	if normalizer := r.htmlNormalizer(snapshot.Spec.HTMLNormalization); normalizer != nil {
+		value, err := json.Marshal(normalizer)
+		if err != nil {
+			return xerrors.Errorf("failed to marshal HTML normalization: %w", err)
+		}
+		args = append(args, "--html-normalization", string(value))
+	}
+
+	if ignore := r.htmlIgnore(snapshot.Spec.HTMLIgnore); ignore != nil {
+		value, err := json.Marshal(ignore)
+		if err != nil {
+			return xerrors.Errorf("failed to marshal HTML ignore: %w", err)
+		}
+		args = append(args, "--html-ignore", string(value))
+	}
+
+	if snapshot.Spec.HTMLDiffContextLines != nil {
+		args = append(args, "--html-diff-context-lines", strconv.Itoa(*snapshot.Spec.HTMLDiffContextLines))
+	}
+
+	if snapshot.Spec.HTMLDiffHighlight != "" {
+		args = append(args, "--html-diff-highlight", snapshot.Spec.HTMLDiffHighlight)
+	}
+
+	if snapshot.Spec.PerceptualHashDistance != nil {
+		args = append(args, "--perceptual-hash-distance", strconv.Itoa(*snapshot.Spec.PerceptualHashDistance))
+	}
+
+	if snapshot.Spec.ColorDistance != "" {
+		args = append(args, "--color-distance", snapshot.Spec.ColorDistance)
+		args = append(args, "--color-tolerance", strconv.FormatFloat(r.colorTolerance(snapshot.Spec.ColorTolerance), 'f', -1, 64))
+	}
+
+	if snapshot.Spec.ScreenshotDiffPalette != "" {
+		args = append(args, "--screenshot-diff-palette", snapshot.Spec.ScreenshotDiffPalette)
+	}
+
+	if snapshot.Spec.ScreenshotDiffStrokeWidth > 0 {
+		args = append(args, "--screenshot-diff-stroke-width", strconv.Itoa(snapshot.Spec.ScreenshotDiffStrokeWidth))
+	}
+
+	if snapshot.Spec.SizeMismatch != "" {
+		args = append(args, "--size-mismatch", snapshot.Spec.SizeMismatch)
+	}
+
+	if snapshot.Spec.PadColor != "" {
+		args = append(args, "--pad-color", snapshot.Spec.PadColor)
+	}
+
+	if r.DiffMemoryBudget > 0 {
+		args = append(args, "--diff-memory-budget", strconv.Itoa(r.DiffMemoryBudget))
+	}
+
 	if len(snapshot.Spec.MaskSelectors) > 0 {
 		args = append(args, "--mask-selectors", strings.Join(snapshot.Spec.MaskSelectors, ","))
 	}
 
+	if len(snapshot.Spec.StyleSelectors) > 0 {
+		value, err := json.Marshal(snapshot.Spec.StyleSelectors)
+		if err != nil {
+			return xerrors.Errorf("failed to marshal style selectors: %w", err)
+		}
+		args = append(args, "--style-selectors", string(value))
+	}
+
+	if len(snapshot.Spec.StyleProperties) > 0 {
+		args = append(args, "--style-properties", strings.Join(snapshot.Spec.StyleProperties, ","))
+	}
+
+	if snapshot.Spec.CaptureMode != "" {
+		args = append(args, "--capture-mode", snapshot.Spec.CaptureMode)
+	}
+
+	if snapshot.Spec.CaptureMode == "pdf" {
+		pdfOptions := r.pdfOptions(snapshot.Spec.PDF)
+		args = append(args,
+			"--pdf-format", pdfOptions.Format,
+			"--pdf-landscape="+strconv.FormatBool(pdfOptions.Landscape),
+			"--pdf-margin", strings.Join([]string{pdfOptions.MarginTop, pdfOptions.MarginRight, pdfOptions.MarginBottom, pdfOptions.MarginLeft}, ","),
+		)
+	}
+
 	for key, value := range snapshot.Spec.Headers {
 		args = append(args, "-H", fmt.Sprintf("%s: %s", key, value))
 	}
@@ -0,0 +1,119 @@
+package image
+
+import (
+	"image"
+	"image/color"
+)
+
+// isAntiAliased reports whether a pixel that differs between baseline and target is part of an anti-aliased edge in
+// either image, based on the brightness gradient of its neighbors as done by pixelmatch.
+// Reference: https://github.com/mapbox/pixelmatch
+// Reference: V. Vysniauskas, "Anti-aliased Pixel and Intensity Slope Detector", 2009
+func isAntiAliased(baseline image.Image, target image.Image, x int, y int) bool {
+	return antiAliased(baseline, target, x, y) || antiAliased(target, baseline, x, y)
+}
+
+// antiAliased checks whether the pixel lies on a gradient between a darker and a brighter neighbor, where at least one
+// of them belongs to a flat area in both images.
+func antiAliased(img image.Image, other image.Image, x1 int, y1 int) bool {
+	bounds := img.Bounds()
+	if !(image.Point{X: x1, Y: y1}).In(bounds) {
+		return false
+	}
+
+	x0, y0, x2, y2 := neighborhood(bounds, x1, y1)
+
+	zeroes := 0
+	if x1 == x0 || x1 == x2 || y1 == y0 || y1 == y2 {
+		zeroes = 1
+	}
+
+	center := brightness(pixelAt(img, x1, y1))
+
+	var minDelta, maxDelta float64
+	var minX, minY, maxX, maxY int
+	for y := y0; y <= y2; y++ {
+		for x := x0; x <= x2; x++ {
+			if x == x1 && y == y1 {
+				continue
+			}
+
+			delta := center - brightness(pixelAt(img, x, y))
+			if delta == 0 {
+				zeroes++
+				if zeroes > 2 {
+					return false
+				}
+			} else if delta < minDelta {
+				minDelta = delta
+				minX, minY = x, y
+			} else if delta > maxDelta {
+				maxDelta = delta
+				maxX, maxY = x, y
+			}
+		}
+	}
+
+	if minDelta == 0 || maxDelta == 0 {
+		return false
+	}
+
+	return (hasManySiblings(img, minX, minY) && hasManySiblings(other, minX, minY)) ||
+		(hasManySiblings(img, maxX, maxY) && hasManySiblings(other, maxX, maxY))
+}
+
+// hasManySiblings reports whether more than two neighbors have exactly the same color as the pixel.
+func hasManySiblings(img image.Image, x1 int, y1 int) bool {
+	bounds := img.Bounds()
+	if !(image.Point{X: x1, Y: y1}).In(bounds) {
+		return false
+	}
+
+	x0, y0, x2, y2 := neighborhood(bounds, x1, y1)
+
+	zeroes := 0
+	if x1 == x0 || x1 == x2 || y1 == y0 || y1 == y2 {
+		zeroes = 1
+	}
+
+	center := pixelAt(img, x1, y1)
+	for y := y0; y <= y2; y++ {
+		for x := x0; x <= x2; x++ {
+			if x == x1 && y == y1 {
+				continue
+			}
+
+			if pixelAt(img, x, y) == center {
+				zeroes++
+				if zeroes > 2 {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func neighborhood(bounds image.Rectangle, x int, y int) (int, int, int, int) {
+	return max(x-1, bounds.Min.X), max(y-1, bounds.Min.Y), min(x+1, bounds.Max.X-1), min(y+1, bounds.Max.Y-1)
+}
+
+// brightness returns the Y component of YIQ.
+func brightness(c color.RGBA) float64 {
+	return float64(c.R)*0.29889531 + float64(c.G)*0.58662247 + float64(c.B)*0.11448223
+}
+
+func pixelAt(img image.Image, x int, y int) color.RGBA {
+	switch i := img.(type) {
+	case *image.RGBA:
+		offset := i.PixOffset(x, y)
+		return color.RGBA{R: i.Pix[offset], G: i.Pix[offset+1], B: i.Pix[offset+2], A: i.Pix[offset+3]}
+	case *image.YCbCr:
+		c := i.YCbCrAt(x, y)
+		r, g, b := color.YCbCrToRGB(c.Y, c.Cb, c.Cr)
+		return color.RGBA{R: r, G: g, B: b, A: 255}
+	default:
+		return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+	}
+}
@@ -0,0 +1,158 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+)
+
+// Composite formats render the baseline, target and diff images together for review.
+const (
+	CompositeSideBySide = "side-by-side"
+	CompositeOverlay    = "overlay"
+	CompositeOnionSkin  = "onion-skin"
+	CompositeBlink      = "blink"
+)
+
+const (
+	// compositeGap is the width of the separator between the images of a side-by-side composite.
+	compositeGap = 8
+	// onionSkinSteps is the number of blending steps from the baseline to the target in an onion-skin composite.
+	onionSkinSteps = 5
+)
+
+// RenderComposite renders the composite of the format and returns it encoded along with its file extension. Static
+// composites are encoded in the image format as by Encode and animated ones as GIF.
+func RenderComposite(format string, baseline image.Image, target image.Image, diff image.Image, imageFormat string) ([]byte, string, error) {
+	var buffer bytes.Buffer
+	switch format {
+	case CompositeSideBySide:
+		data, err := Encode(SideBySide(baseline, diff, target), imageFormat)
+		if err != nil {
+			return nil, "", err
+		}
+		return data, Extension(imageFormat), nil
+	case CompositeOverlay:
+		data, err := Encode(Overlay(baseline, target, 0.5), imageFormat)
+		if err != nil {
+			return nil, "", err
+		}
+		return data, Extension(imageFormat), nil
+	case CompositeOnionSkin:
+		if err := gif.EncodeAll(&buffer, OnionSkin(baseline, target)); err != nil {
+			return nil, "", err
+		}
+		return buffer.Bytes(), "gif", nil
+	case CompositeBlink:
+		if err := gif.EncodeAll(&buffer, Blink(baseline, target)); err != nil {
+			return nil, "", err
+		}
+		return buffer.Bytes(), "gif", nil
+	default:
+		return nil, "", fmt.Errorf("unknown composite format: %s", format)
+	}
+}
+
+// SideBySide lays out the baseline, diff and target images from left to right, separated by gray gaps.
+func SideBySide(baseline image.Image, diff image.Image, target image.Image) *image.RGBA {
+	images := []image.Image{baseline, diff, target}
+
+	width := compositeGap * (len(images) - 1)
+	height := 0
+	for _, img := range images {
+		width += img.Bounds().Dx()
+		height = max(height, img.Bounds().Dy())
+	}
+
+	result := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(result, result.Bounds(), &image.Uniform{C: color.Gray{Y: 128}}, image.Point{}, draw.Src)
+	x := 0
+	for _, img := range images {
+		bounds := img.Bounds()
+		draw.Draw(result, image.Rect(x, 0, x+bounds.Dx(), bounds.Dy()), img, bounds.Min, draw.Src)
+		x += bounds.Dx() + compositeGap
+	}
+	return result
+}
+
+// Overlay blends the target over the baseline with the opacity, so that moved or changed content shows up as ghosting.
+func Overlay(baseline image.Image, target image.Image, opacity float64) *image.RGBA {
+	bounds := compositeBounds(baseline, target)
+	return blend(toCanvas(baseline, bounds), toCanvas(target, bounds), opacity)
+}
+
+// OnionSkin renders an animation that fades from the baseline to the target and back.
+func OnionSkin(baseline image.Image, target image.Image) *gif.GIF {
+	bounds := compositeBounds(baseline, target)
+	baselineCanvas := toCanvas(baseline, bounds)
+	targetCanvas := toCanvas(target, bounds)
+
+	animation := &gif.GIF{}
+	for step := 0; step < 2*(onionSkinSteps-1); step++ {
+		i := step
+		if i >= onionSkinSteps {
+			i = 2*(onionSkinSteps-1) - step
+		}
+
+		delay := 15
+		if i == 0 || i == onionSkinSteps-1 {
+			delay = 100
+		}
+		opacity := float64(i) / float64(onionSkinSteps-1)
+		animation.Image = append(animation.Image, toPaletted(blend(baselineCanvas, targetCanvas, opacity)))
+		animation.Delay = append(animation.Delay, delay)
+	}
+	return animation
+}
+
+// Blink renders an animation that flips between the baseline and the target.
+func Blink(baseline image.Image, target image.Image) *gif.GIF {
+	bounds := compositeBounds(baseline, target)
+	return &gif.GIF{
+		Image: []*image.Paletted{toPaletted(toCanvas(baseline, bounds)), toPaletted(toCanvas(target, bounds))},
+		Delay: []int{50, 50},
+	}
+}
+
+// compositeBounds returns bounds at the origin large enough for both images.
+func compositeBounds(baseline image.Image, target image.Image) image.Rectangle {
+	return image.Rect(0, 0, max(baseline.Bounds().Dx(), target.Bounds().Dx()), max(baseline.Bounds().Dy(), target.Bounds().Dy()))
+}
+
+// toCanvas renders the image at the origin of a white canvas of the bounds.
+func toCanvas(img image.Image, bounds image.Rectangle) *image.RGBA {
+	canvas := image.NewRGBA(bounds)
+	draw.Draw(canvas, bounds, &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(canvas, img.Bounds().Sub(img.Bounds().Min), img, img.Bounds().Min, draw.Over)
+	return canvas
+}
+
+func blend(baseline *image.RGBA, target *image.RGBA, opacity float64) *image.RGBA {
+	result := image.NewRGBA(baseline.Bounds())
+	for i := range result.Pix {
+		result.Pix[i] = uint8(float64(baseline.Pix[i])*(1.0-opacity) + float64(target.Pix[i])*opacity + 0.5)
+	}
+	return result
+}
+
+// toPaletted quantizes the image to the web-safe palette by rounding each channel to the nearest multiple of 0x33,
+// which avoids the nearest color search of draw.Draw on large screenshots.
+func toPaletted(img *image.RGBA) *image.Paletted {
+	bounds := img.Bounds()
+	paletted := image.NewPaletted(bounds, palette.WebSafe)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		src := img.Pix[img.PixOffset(bounds.Min.X, y):]
+		dst := paletted.Pix[paletted.PixOffset(bounds.Min.X, y):]
+		for x := 0; x < bounds.Dx(); x++ {
+			r := (int(src[x*4]) + 25) / 51
+			g := (int(src[x*4+1]) + 25) / 51
+			b := (int(src[x*4+2]) + 25) / 51
+			dst[x] = uint8(r*36 + g*6 + b)
+		}
+	}
+	return paletted
+}
@@ -0,0 +1,88 @@
+package image
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+)
+
+func TestSideBySide(t *testing.T) {
+	baseline := createRectTestImage(100, 50, color.White)
+	diff := createRectTestImage(100, 80, color.RGBA{R: 255, A: 255})
+	target := createRectTestImage(120, 60, color.Black)
+
+	result := SideBySide(baseline, diff, target)
+
+	if expected := image.Rect(0, 0, 100+compositeGap+100+compositeGap+120, 80); result.Bounds() != expected {
+		t.Fatalf("Expected bounds %v, got %v", expected, result.Bounds())
+	}
+	if c := result.RGBAAt(100+compositeGap+50, 40); c != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("Expected the diff in the middle, got %v", c)
+	}
+	if c := result.RGBAAt(100+compositeGap+100+compositeGap+10, 10); c != (color.RGBA{A: 255}) {
+		t.Errorf("Expected the target on the right, got %v", c)
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	baseline := createRectTestImage(10, 10, color.White)
+	target := createRectTestImage(10, 10, color.Black)
+
+	result := Overlay(baseline, target, 0.5)
+
+	if c := result.RGBAAt(5, 5); c.R != 128 || c.G != 128 || c.B != 128 {
+		t.Errorf("Expected gray, got %v", c)
+	}
+}
+
+func TestRenderComposite(t *testing.T) {
+	baseline := createRectTestImage(10, 10, color.White)
+	target := createRectTestImage(10, 20, color.Black)
+
+	t.Run("Blink", func(t *testing.T) {
+		data, extension, err := RenderComposite(CompositeBlink, baseline, target, target, "png")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if extension != "gif" {
+			t.Errorf("Expected gif, got %s", extension)
+		}
+
+		animation, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Failed to decode GIF: %v", err)
+		}
+		if len(animation.Image) != 2 {
+			t.Fatalf("Expected 2 frames, got %d", len(animation.Image))
+		}
+		if r, _, _, _ := animation.Image[0].At(5, 15).RGBA(); r>>8 != 255 {
+			t.Errorf("Expected the baseline frame to be padded with white, got %d", r>>8)
+		}
+		if r, _, _, _ := animation.Image[1].At(5, 15).RGBA(); r>>8 != 0 {
+			t.Errorf("Expected the target frame to be black, got %d", r>>8)
+		}
+	})
+
+	t.Run("OnionSkin", func(t *testing.T) {
+		data, _, err := RenderComposite(CompositeOnionSkin, baseline, target, target, "png")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		animation, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Failed to decode GIF: %v", err)
+		}
+		if len(animation.Image) != 2*(onionSkinSteps-1) {
+			t.Errorf("Expected %d frames, got %d", 2*(onionSkinSteps-1), len(animation.Image))
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		if _, _, err := RenderComposite("unknown", baseline, target, target, "png"); err == nil {
+			t.Error("Expected an error for an unknown format")
+		}
+	})
+}
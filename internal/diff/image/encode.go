@@ -0,0 +1,33 @@
+package image
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+)
+
+// Encode encodes the image as PNG when the format is "png" and as JPEG otherwise. PNG keeps the diff lossless so that
+// the rendered colors are not disturbed by compression noise.
+func Encode(img image.Image, format string) ([]byte, error) {
+	var buffer bytes.Buffer
+	switch format {
+	case "png":
+		if err := png.Encode(&buffer, img); err != nil {
+			return nil, err
+		}
+	default:
+		if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: 90}); err != nil {
+			return nil, err
+		}
+	}
+	return buffer.Bytes(), nil
+}
+
+// Extension returns the file extension of the images encoded by Encode in the format.
+func Extension(format string) string {
+	if format == "png" {
+		return "png"
+	}
+	return "jpeg"
+}
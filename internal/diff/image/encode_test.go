@@ -0,0 +1,42 @@
+package image
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	img := createRectTestImage(10, 10, color.White)
+	img.Set(5, 5, color.RGBA{R: 1, G: 2, B: 3, A: 255})
+
+	t.Run("PNG", func(t *testing.T) {
+		data, err := Encode(img, "png")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		decoded, format, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Failed to decode: %v", err)
+		}
+		if format != "png" {
+			t.Errorf("Expected png, got %s", format)
+		}
+		if r, g, b, _ := decoded.At(5, 5).RGBA(); r>>8 != 1 || g>>8 != 2 || b>>8 != 3 {
+			t.Errorf("Expected the pixel to be kept losslessly, got (%d, %d, %d)", r>>8, g>>8, b>>8)
+		}
+	})
+
+	t.Run("JPEG", func(t *testing.T) {
+		data, err := Encode(img, "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if _, format, err := image.Decode(bytes.NewReader(data)); err != nil || format != "jpeg" {
+			t.Errorf("Expected jpeg, got %s (%v)", format, err)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+package image
+
+import (
+	"hash"
+	"hash/fnv"
+	"image"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// WithPerceptualHash treats images of the same size as unchanged when both their difference hashes (dHash) and their
+// perceptual hashes (pHash) are within maxDistance bits of each other, and skips comparing their pixels. The hashes
+// are computed from a few thousand sampled pixels, so differences smaller than a sampled cell may go unnoticed; use it
+// when most comparisons are expected to find no change. All differs support it.
+// Reference: N. Krawetz, "Kind of Like That", 2013
+func WithPerceptualHash(maxDistance int) Option {
+	return func(o *options) {
+		o.perceptualHash = true
+		o.maxHashDistance = maxDistance
+	}
+}
+
+// unchanged reports whether comparing the pixels of the images can be skipped because they are identical, or similar
+// enough by their perceptual hashes.
+func (o *options) unchanged(baseline image.Image, target image.Image) bool {
+	if identical(baseline, target) {
+		return true
+	}
+	if !o.perceptualHash || baseline.Bounds().Size() != target.Bounds().Size() {
+		return false
+	}
+	return HammingDistance(DHash(baseline), DHash(target)) <= o.maxHashDistance &&
+		HammingDistance(PHash(baseline), PHash(target)) <= o.maxHashDistance
+}
+
+// identical reports whether the images have the same bounds and pixels. Images of the same type are compared by the
+// hashes of their rows, which stops at the first changed row without converting any pixel into a color.Color.
+func identical(baseline image.Image, target image.Image) bool {
+	if baseline == target {
+		return true
+	}
+	bounds := baseline.Bounds()
+	if bounds != target.Bounds() {
+		return false
+	}
+	if !comparableRows(baseline, target) {
+		return identicalColors(baseline, target)
+	}
+
+	h := fnv.New64a()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		if rowHash(h, baseline, y) != rowHash(h, target, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// comparableRows reports whether the rows of the images are hashed by rowHash with the same layout of bytes.
+func comparableRows(baseline image.Image, target image.Image) bool {
+	switch baseline := baseline.(type) {
+	case *image.RGBA:
+		_, ok := target.(*image.RGBA)
+		return ok
+	case *image.NRGBA:
+		_, ok := target.(*image.NRGBA)
+		return ok
+	case *image.RGBA64:
+		_, ok := target.(*image.RGBA64)
+		return ok
+	case *image.NRGBA64:
+		_, ok := target.(*image.NRGBA64)
+		return ok
+	case *image.Gray:
+		_, ok := target.(*image.Gray)
+		return ok
+	case *image.YCbCr:
+		t, ok := target.(*image.YCbCr)
+		return ok && t.SubsampleRatio == baseline.SubsampleRatio
+	default:
+		return false
+	}
+}
+
+// rowHash hashes the bytes of the row y of an image accepted by comparableRows.
+func rowHash(h hash.Hash64, img image.Image, y int) uint64 {
+	bounds := img.Bounds()
+	h.Reset()
+	switch img := img.(type) {
+	case *image.RGBA:
+		h.Write(img.Pix[img.PixOffset(bounds.Min.X, y):img.PixOffset(bounds.Max.X, y)])
+	case *image.NRGBA:
+		h.Write(img.Pix[img.PixOffset(bounds.Min.X, y):img.PixOffset(bounds.Max.X, y)])
+	case *image.RGBA64:
+		h.Write(img.Pix[img.PixOffset(bounds.Min.X, y):img.PixOffset(bounds.Max.X, y)])
+	case *image.NRGBA64:
+		h.Write(img.Pix[img.PixOffset(bounds.Min.X, y):img.PixOffset(bounds.Max.X, y)])
+	case *image.Gray:
+		h.Write(img.Pix[img.PixOffset(bounds.Min.X, y):img.PixOffset(bounds.Max.X, y)])
+	case *image.YCbCr:
+		h.Write(img.Y[img.YOffset(bounds.Min.X, y) : img.YOffset(bounds.Max.X-1, y)+1])
+		h.Write(img.Cb[img.COffset(bounds.Min.X, y) : img.COffset(bounds.Max.X-1, y)+1])
+		h.Write(img.Cr[img.COffset(bounds.Min.X, y) : img.COffset(bounds.Max.X-1, y)+1])
+	}
+	return h.Sum64()
+}
+
+// identicalColors compares the colors of the images pixel by pixel, for images whose rows are not comparable by hash.
+func identicalColors(baseline image.Image, target image.Image) bool {
+	bounds := baseline.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			br, bg, bb, ba := baseline.At(x, y).RGBA()
+			tr, tg, tb, ta := target.At(x, y).RGBA()
+			if br != tr || bg != tg || bb != tb || ba != ta {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// hashSamples is the number of pixels sampled along each axis of a cell of the hashes.
+const hashSamples = 4
+
+// DHash returns the 64-bit difference hash of the image: the image is reduced to 9x8 cells of gray and each bit tells
+// whether a cell is brighter than its right neighbor.
+func DHash(img image.Image) uint64 {
+	cells := grayCells(img, 9, 8)
+	var h uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			h <<= 1
+			if cells[y*9+x] > cells[y*9+x+1] {
+				h |= 1
+			}
+		}
+	}
+	return h
+}
+
+// PHash returns the 64-bit perceptual hash of the image: the image is reduced to 32x32 cells of gray and each bit tells
+// whether one of the 8x8 lowest frequencies of its discrete cosine transform is above their median, excluding the
+// constant term.
+func PHash(img image.Image) uint64 {
+	const size = 32
+	cells := grayCells(img, size, size)
+
+	cosines := make([]float64, 8*size)
+	for u := 0; u < 8; u++ {
+		for x := 0; x < size; x++ {
+			cosines[u*size+x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * size))
+		}
+	}
+	coefficients := make([]float64, 64)
+	for v := 0; v < 8; v++ {
+		for u := 0; u < 8; u++ {
+			sum := 0.0
+			for y := 0; y < size; y++ {
+				for x := 0; x < size; x++ {
+					sum += cells[y*size+x] * cosines[u*size+x] * cosines[v*size+y]
+				}
+			}
+			coefficients[v*8+u] = sum
+		}
+	}
+
+	sorted := slices.Clone(coefficients[1:])
+	slices.Sort(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var h uint64
+	for _, c := range coefficients {
+		h <<= 1
+		if c > median {
+			h |= 1
+		}
+	}
+	return h
+}
+
+// HammingDistance returns the number of bits in which the hashes differ.
+func HammingDistance(a uint64, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// grayCells divides the image into width x height cells and returns the mean luma of each cell, estimated from
+// hashSamples x hashSamples pixels so that the cost does not grow with the size of the image.
+func grayCells(img image.Image, width int, height int) []float64 {
+	bounds := img.Bounds()
+	cells := make([]float64, width*height)
+	if bounds.Empty() {
+		return cells
+	}
+	for cy := 0; cy < height; cy++ {
+		for cx := 0; cx < width; cx++ {
+			sum := 0.0
+			for sy := 0; sy < hashSamples; sy++ {
+				y := bounds.Min.Y + ((cy*hashSamples+sy)*2+1)*bounds.Dy()/(2*height*hashSamples)
+				for sx := 0; sx < hashSamples; sx++ {
+					x := bounds.Min.X + ((cx*hashSamples+sx)*2+1)*bounds.Dx()/(2*width*hashSamples)
+					r, g, b, _ := img.At(x, y).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+				}
+			}
+			cells[cy*width+cx] = sum / (hashSamples * hashSamples * 257)
+		}
+	}
+	return cells
+}
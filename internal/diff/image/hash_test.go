@@ -0,0 +1,96 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestIdentical(t *testing.T) {
+	baseline := createRectTestImage(50, 50, color.White)
+	baseline.Set(10, 10, color.Black)
+
+	t.Run("SameContent", func(t *testing.T) {
+		target := createRectTestImage(50, 50, color.White)
+		target.Set(10, 10, color.Black)
+
+		if !identical(baseline, target) {
+			t.Error("Expected images with the same pixels to be identical")
+		}
+	})
+
+	t.Run("ChangedPixel", func(t *testing.T) {
+		target := createRectTestImage(50, 50, color.White)
+		target.Set(10, 10, color.Black)
+		target.Set(49, 49, color.Black)
+
+		if identical(baseline, target) {
+			t.Error("Expected images with a changed pixel not to be identical")
+		}
+	})
+
+	t.Run("DifferentTypes", func(t *testing.T) {
+		target := image.NewNRGBA(baseline.Bounds())
+		draw.Draw(target, target.Bounds(), baseline, image.Point{}, draw.Src)
+
+		if !identical(baseline, target) {
+			t.Error("Expected images of different types with the same colors to be identical")
+		}
+	})
+
+	t.Run("SkipsComparison", func(t *testing.T) {
+		target := createRectTestImage(50, 50, color.White)
+		target.Set(10, 10, color.Black)
+
+		result := NewPixelDiff(0.1).Calculate(baseline, target)
+
+		if result.Image != image.Image(baseline) || result.DiffAmount != 0 {
+			t.Errorf("Expected the baseline to be returned as is, got DiffAmount %f", result.DiffAmount)
+		}
+	})
+}
+
+func TestPerceptualHash(t *testing.T) {
+	baseline := image.NewRGBA(image.Rect(0, 0, 200, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 200; x++ {
+			baseline.Set(x, y, color.Gray{Y: uint8((x*x + y*3) % 256)})
+		}
+	}
+
+	t.Run("SimilarImages", func(t *testing.T) {
+		target := image.NewRGBA(baseline.Bounds())
+		draw.Draw(target, target.Bounds(), baseline, image.Point{}, draw.Src)
+		target.Set(151, 151, color.White)
+
+		if d := HammingDistance(DHash(baseline), DHash(target)); d != 0 {
+			t.Errorf("Expected the same difference hash, got distance %d", d)
+		}
+		if d := HammingDistance(PHash(baseline), PHash(target)); d != 0 {
+			t.Errorf("Expected the same perceptual hash, got distance %d", d)
+		}
+		if result := NewPixelDiff(0.1, WithPerceptualHash(0)).Calculate(baseline, target); result.DiffAmount != 0 {
+			t.Errorf("Expected the comparison to be skipped, got DiffAmount %f", result.DiffAmount)
+		}
+		if result := NewPixelDiff(0.1).Calculate(baseline, target); result.DiffAmount == 0 {
+			t.Error("Expected the change to be found without the perceptual hash")
+		}
+	})
+
+	t.Run("DifferentImages", func(t *testing.T) {
+		target := image.NewRGBA(baseline.Bounds())
+		for y := 0; y < 200; y++ {
+			for x := 0; x < 200; x++ {
+				target.Set(x, y, color.Gray{Y: uint8((y*y + x*3) % 256)})
+			}
+		}
+
+		if d := HammingDistance(PHash(baseline), PHash(target)); d <= 4 {
+			t.Errorf("Expected different perceptual hashes, got distance %d", d)
+		}
+		if result := NewPixelDiff(0.1, WithPerceptualHash(4)).Calculate(baseline, target); result.DiffAmount == 0 {
+			t.Error("Expected the comparison not to be skipped")
+		}
+	})
+}
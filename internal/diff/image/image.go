@@ -1,12 +1,135 @@
 package image
 
-import "image"
+import (
+	"image"
+	"image/color"
+)
 
 type DiffResult struct {
 	Image      image.Image
 	DiffAmount float64
+	// InsertedBands are the rows of the target that do not exist in the baseline. Only ShiftDiff reports them.
+	InsertedBands []Band
+	// RemovedBands are the rows of the baseline that do not exist in the target. Only ShiftDiff reports them.
+	RemovedBands []Band
+	// Regions are the merged areas of changed pixels. Only RectangleDiff reports them.
+	Regions []ChangedRegion
+	// DimensionChange is the size difference between the baseline and the target, nil when they have the same size.
+	DimensionChange *DimensionChange
+}
+
+// Band is a horizontal range of rows [Y, Y+Height).
+type Band struct {
+	Y      int `json:"y"`
+	Height int `json:"height"`
+}
+
+// ChangedRegion is a rectangle enclosing changed pixels. Area is the number of pixels of the rectangle and PixelRatio is
+// the ratio of changed pixels to Area.
+type ChangedRegion struct {
+	Rectangle
+	Area       int     `json:"area"`
+	PixelRatio float64 `json:"pixelRatio"`
 }
 
 type Differ interface {
 	Calculate(baseline image.Image, target image.Image) *DiffResult
 }
+
+// options are the settings shared by the differs. A differ ignores the settings it does not support.
+type options struct {
+	detectAntiAliasing bool
+	ignoreRegions      []Rectangle
+	memoryBudget       int
+	sizeMismatch       string
+	padColor           color.Color
+	palette            Palette
+	strokeWidth        int
+
+	perceptualColorDistance bool
+	colorTolerance          float64
+
+	perceptualHash  bool
+	maxHashDistance int
+}
+
+type Option func(*options)
+
+func newOptions(opts []Option) options {
+	o := options{
+		palette:     DefaultPalette(),
+		strokeWidth: 3,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithAntiAliasingDetection excludes pixels changed by anti-aliasing from the diff amount and renders them in yellow.
+// Only PixelDiff supports it.
+func WithAntiAliasingDetection(enabled bool) Option {
+	return func(o *options) {
+		o.detectAntiAliasing = enabled
+	}
+}
+
+// WithIgnoreRegions excludes the regions from both the diff amount and the rendered differences.
+// PixelDiff, RectangleDiff and ShiftDiff support it.
+func WithIgnoreRegions(regions ...Rectangle) Option {
+	return func(o *options) {
+		o.ignoreRegions = regions
+	}
+}
+
+// WithMemoryBudget bounds the bytes allocated for comparing the images. When the full-size buffers would exceed the
+// budget, the images are compared in horizontal strips and the diff image is rendered strip by strip while it is read,
+// with the same diff amount and rectangles, and images of different sizes are padded or scaled on demand instead of
+// being copied. The decoded images themselves are not counted: they are held in full by the caller. Zero means no
+// budget. PixelDiff and RectangleDiff support it.
+func WithMemoryBudget(bytes int) Option {
+	return func(o *options) {
+		o.memoryBudget = bytes
+	}
+}
+
+func (o *options) isIgnored(x int, y int) bool {
+	for _, region := range o.ignoreRegions {
+		if x >= region.X && x < region.X+region.Width && y >= region.Y && y < region.Y+region.Height {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredArea returns the number of pixels within bounds covered by at least one ignored region.
+func (o *options) ignoredArea(bounds image.Rectangle) int {
+	var regions []image.Rectangle
+	for _, region := range o.ignoreRegions {
+		r := image.Rect(region.X, region.Y, region.X+region.Width, region.Y+region.Height).Intersect(bounds)
+		if !r.Empty() {
+			regions = append(regions, r)
+		}
+	}
+	if len(regions) == 0 {
+		return 0
+	}
+
+	area := 0
+	covered := make([]bool, bounds.Dx())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		clear(covered)
+		for _, r := range regions {
+			if y < r.Min.Y || y >= r.Max.Y {
+				continue
+			}
+			for x := r.Min.X; x < r.Max.X; x++ {
+				if !covered[x-bounds.Min.X] {
+					covered[x-bounds.Min.X] = true
+					area++
+				}
+			}
+		}
+	}
+	return area
+}
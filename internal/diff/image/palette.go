@@ -0,0 +1,96 @@
+package image
+
+import (
+	"fmt"
+	"image/color"
+)
+
+// Palette names select the built-in palettes.
+const (
+	PaletteNameDefault    = "default"
+	PaletteNameColorBlind = "color-blind"
+)
+
+// Palette is the set of colors the differs render differences with.
+type Palette struct {
+	// Added is the color of pixels that got brighter.
+	Added color.RGBA
+	// Removed is the color of pixels that got darker.
+	Removed color.RGBA
+	// Hue is the color of pixels whose color changed without getting brighter or darker.
+	Hue color.RGBA
+	// Changed is the color of the rectangles around changed regions.
+	Changed color.RGBA
+	// AntiAliased is the color of pixels changed by anti-aliasing.
+	AntiAliased color.RGBA
+	// Unchanged is the color unchanged pixels are blended toward by Dimming.
+	Unchanged color.RGBA
+	// Dimming is the ratio from 0 to 1 by which unchanged pixels are blended toward Unchanged, so that differences
+	// stand out. Only PixelDiff dims unchanged pixels.
+	Dimming float64
+}
+
+// DefaultPalette renders brighter pixels in red, darker pixels in blue, pixels whose hue changed in magenta and
+// anti-aliased pixels in yellow.
+func DefaultPalette() Palette {
+	return Palette{
+		Added:       color.RGBA{R: 255, A: 255},
+		Removed:     color.RGBA{B: 255, A: 255},
+		Hue:         color.RGBA{R: 255, B: 255, A: 255},
+		Changed:     color.RGBA{R: 255, A: 255},
+		AntiAliased: color.RGBA{R: 255, G: 255, A: 255},
+		Unchanged:   color.RGBA{R: 255, G: 255, B: 255, A: 255},
+	}
+}
+
+// ColorBlindPalette uses colors of the Okabe-Ito palette, which stay distinguishable with the common forms of color
+// vision deficiency, and dims unchanged pixels so that differences do not rely on hue alone.
+// Reference: M. Okabe and K. Ito, "Color Universal Design (CUD)", 2008
+func ColorBlindPalette() Palette {
+	return Palette{
+		Added:       color.RGBA{R: 0xe6, G: 0x9f, B: 0x00, A: 255}, // orange
+		Removed:     color.RGBA{R: 0x00, G: 0x72, B: 0xb2, A: 255}, // blue
+		Hue:         color.RGBA{R: 0x00, G: 0x9e, B: 0x73, A: 255}, // bluish green
+		Changed:     color.RGBA{R: 0xcc, G: 0x79, B: 0xa7, A: 255}, // reddish purple
+		AntiAliased: color.RGBA{R: 0xf0, G: 0xe4, B: 0x42, A: 255}, // yellow
+		Unchanged:   color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		Dimming:     0.6,
+	}
+}
+
+// PaletteByName returns the built-in palette of the name. An empty name selects the default palette.
+func PaletteByName(name string) (Palette, error) {
+	switch name {
+	case "", PaletteNameDefault:
+		return DefaultPalette(), nil
+	case PaletteNameColorBlind:
+		return ColorBlindPalette(), nil
+	default:
+		return Palette{}, fmt.Errorf("unknown palette: %s", name)
+	}
+}
+
+// WithPalette sets the colors differences are rendered with. PixelDiff and RectangleDiff support it.
+func WithPalette(palette Palette) Option {
+	return func(o *options) {
+		o.palette = palette
+	}
+}
+
+// WithStrokeWidth sets the width in pixels of the rectangles around changed regions. Only RectangleDiff supports it.
+func WithStrokeWidth(width int) Option {
+	return func(o *options) {
+		o.strokeWidth = width
+	}
+}
+
+// dim blends the unchanged color toward Unchanged by Dimming.
+func (p *Palette) dim(r uint8, g uint8, b uint8, a uint8) (uint8, uint8, uint8, uint8) {
+	if p.Dimming <= 0 {
+		return r, g, b, a
+	}
+	return uint8(float64(r)*(1-p.Dimming) + float64(p.Unchanged.R)*p.Dimming + 0.5),
+		uint8(float64(g)*(1-p.Dimming) + float64(p.Unchanged.G)*p.Dimming + 0.5),
+		uint8(float64(b)*(1-p.Dimming) + float64(p.Unchanged.B)*p.Dimming + 0.5),
+		a
+}
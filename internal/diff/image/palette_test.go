@@ -0,0 +1,92 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPixelDiff_Palette(t *testing.T) {
+	baseline := createRectTestImage(10, 10, color.Black)
+	target := createRectTestImage(10, 10, color.Black)
+	target.Set(5, 5, color.White)
+
+	palette := ColorBlindPalette()
+	result := NewPixelDiff(0.1, WithPalette(palette)).Calculate(baseline, target)
+
+	if c := color.RGBAModel.Convert(result.Image.At(5, 5)); c != palette.Added {
+		t.Errorf("Expected the added color %v, got %v", palette.Added, c)
+	}
+	if c := color.RGBAModel.Convert(result.Image.At(0, 0)).(color.RGBA); c.R != 153 || c.G != 153 || c.B != 153 {
+		t.Errorf("Expected unchanged pixels to be dimmed toward white, got %v", c)
+	}
+}
+
+func TestPixelDiff_PaletteYCbCr(t *testing.T) {
+	newYCbCr := func() *image.YCbCr {
+		img := image.NewYCbCr(image.Rect(0, 0, 10, 10), image.YCbCrSubsampleRatio444)
+		for i := range img.Y {
+			img.Y[i] = 40
+			img.Cb[i] = 128
+			img.Cr[i] = 128
+		}
+		return img
+	}
+	baseline := newYCbCr()
+	target := newYCbCr()
+	target.Y[target.YOffset(5, 5)] = 235
+
+	toRGBA := func(img *image.YCbCr) *image.RGBA {
+		rgba := image.NewRGBA(img.Bounds())
+		for y := 0; y < 10; y++ {
+			for x := 0; x < 10; x++ {
+				rgba.Set(x, y, img.At(x, y))
+			}
+		}
+		return rgba
+	}
+
+	differ := NewPixelDiff(0.1, WithPalette(ColorBlindPalette()))
+	result := differ.Calculate(baseline, target)
+	expected := differ.Calculate(toRGBA(baseline), toRGBA(target)).Image.At(0, 0)
+
+	if c := result.Image.At(0, 0); c != expected {
+		t.Errorf("Expected unchanged YCbCr pixels to be dimmed like RGBA ones to %v, got %v", expected, c)
+	}
+	if c := color.RGBAModel.Convert(result.Image.At(0, 0)).(color.RGBA); c.R == 40 {
+		t.Errorf("Expected unchanged pixels to be dimmed, got %v", c)
+	}
+}
+
+func TestRectangleDiff_Palette(t *testing.T) {
+	baseline := createRectTestImage(100, 100, color.White)
+	target := createRectTestImage(100, 100, color.White)
+	for y := 40; y < 60; y++ {
+		for x := 40; x < 60; x++ {
+			target.Set(x, y, color.Black)
+		}
+	}
+
+	palette := DefaultPalette()
+	palette.Changed = color.RGBA{G: 255, A: 255}
+	result := NewRectangleDiff(WithPalette(palette), WithStrokeWidth(1)).Calculate(baseline, target)
+
+	if c := color.RGBAModel.Convert(result.Image.At(40, 50)); c != palette.Changed {
+		t.Errorf("Expected the stroke in %v, got %v", palette.Changed, c)
+	}
+	if c := color.RGBAModel.Convert(result.Image.At(39, 50)); c != (color.RGBA{R: 255, G: 255, B: 255, A: 255}) {
+		t.Errorf("Expected the stroke to be 1px wide, got %v", c)
+	}
+}
+
+func TestPaletteByName(t *testing.T) {
+	if palette, err := PaletteByName(PaletteNameColorBlind); err != nil || palette != ColorBlindPalette() {
+		t.Errorf("Expected the color-blind palette, got %v, %v", palette, err)
+	}
+	if palette, err := PaletteByName(""); err != nil || palette != DefaultPalette() {
+		t.Errorf("Expected the default palette, got %v, %v", palette, err)
+	}
+	if _, err := PaletteByName("unknown"); err == nil {
+		t.Error("Expected an error for an unknown palette")
+	}
+}
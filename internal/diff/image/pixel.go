@@ -10,30 +10,67 @@ import (
 )
 
 type PixelDiff struct {
+	options
 	threshold float64
 }
 
-func NewPixelDiff(threshold float64) *PixelDiff {
+func NewPixelDiff(threshold float64, opts ...Option) *PixelDiff {
 	return &PixelDiff{
-		threshold,
+		options:   newOptions(opts),
+		threshold: threshold,
 	}
 }
 
 func (p *PixelDiff) Calculate(baseline image.Image, target image.Image) *DiffResult {
-	if baseline == target {
+	if p.unchanged(baseline, target) {
 		return &DiffResult{
 			Image:      baseline,
 			DiffAmount: 0.0,
 		}
 	}
 
+	change := dimensionChange(baseline, target)
+	baseline, target = p.fitSizes(baseline, target)
+
 	bounds := p.calculateUnionBounds(baseline, target)
-	diff := image.NewRGBA(bounds)
+	totalPixelCount := int64((bounds.Max.Y-bounds.Min.Y)*(bounds.Max.X-bounds.Min.X) - p.ignoredArea(bounds))
+
+	var diffImage image.Image
+	var differentPixelCount int64
+	if stripHeight := p.stripHeight(bounds.Dx()*4, bounds.Dy()); stripHeight > 0 {
+		strips := newStripImage(bounds, stripHeight, func(strip *image.RGBA) {
+			p.render(baseline, target, strip)
+		})
+		for y := bounds.Min.Y; y < bounds.Max.Y; y += stripHeight {
+			differentPixelCount += p.render(baseline, target, strips.strip(y))
+		}
+		diffImage = strips
+	} else {
+		diff := image.NewRGBA(bounds)
+		differentPixelCount = p.render(baseline, target, diff)
+		diffImage = diff
+	}
+
+	diffAmount := 0.0
+	if totalPixelCount > 0 {
+		diffAmount = float64(differentPixelCount) / float64(totalPixelCount)
+	}
+
+	return &DiffResult{
+		Image:           diffImage,
+		DiffAmount:      diffAmount,
+		DimensionChange: change,
+	}
+}
+
+// render renders the differences within the bounds of diff and returns the number of pixels counted as differences.
+func (p *PixelDiff) render(baseline image.Image, target image.Image, diff *image.RGBA) int64 {
+	bounds := diff.Bounds()
 	draw.Draw(diff, bounds, &image.Uniform{C: color.White}, image.Point{}, draw.Src)
 
 	var addedPixelCount int64
 	var removedPixelCount int64
-	totalPixelCount := int64((bounds.Max.Y - bounds.Min.Y) * (bounds.Max.X - bounds.Min.X))
+	var changedPixelCount int64
 
 	baselineRGBA, baselineIsRGBA := baseline.(*image.RGBA)
 	targetRGBA, targetIsRGBA := target.(*image.RGBA)
@@ -56,7 +93,7 @@ func (p *PixelDiff) Calculate(baseline image.Image, target image.Image) *DiffRes
 	var wg sync.WaitGroup
 	wg.Add(numWorkers)
 
-	if baselineIsRGBA && targetIsRGBA && diff.Bounds().Eq(bounds) {
+	if baselineIsRGBA && targetIsRGBA {
 		for i := 0; i < numWorkers; i++ {
 			startY := bounds.Min.Y + i*rowsPerWorker
 			endY := startY + rowsPerWorker
@@ -66,10 +103,10 @@ func (p *PixelDiff) Calculate(baseline image.Image, target image.Image) *DiffRes
 
 			go func(startY int, endY int) {
 				defer wg.Done()
-				p.processRGBA(baselineRGBA, targetRGBA, diff, bounds.Min.X, bounds.Max.X, startY, endY, &addedPixelCount, &removedPixelCount)
+				p.processRGBA(baselineRGBA, targetRGBA, diff, bounds.Min.X, bounds.Max.X, startY, endY, &addedPixelCount, &removedPixelCount, &changedPixelCount)
 			}(startY, endY)
 		}
-	} else if baselineIsNRGBA && targetIsNRGBA && diff.Bounds().Eq(bounds) {
+	} else if baselineIsNRGBA && targetIsNRGBA {
 		for i := 0; i < numWorkers; i++ {
 			startY := bounds.Min.Y + i*rowsPerWorker
 			endY := startY + rowsPerWorker
@@ -79,10 +116,10 @@ func (p *PixelDiff) Calculate(baseline image.Image, target image.Image) *DiffRes
 
 			go func(startY int, endY int) {
 				defer wg.Done()
-				p.processNRGBA(baselineNRGBA, targetNRGBA, diff, bounds.Min.X, bounds.Max.X, startY, endY, &addedPixelCount, &removedPixelCount)
+				p.processNRGBA(baselineNRGBA, targetNRGBA, diff, bounds.Min.X, bounds.Max.X, startY, endY, &addedPixelCount, &removedPixelCount, &changedPixelCount)
 			}(startY, endY)
 		}
-	} else if baselineIsRGBA64 && targetIsRGBA64 && diff.Bounds().Eq(bounds) {
+	} else if baselineIsRGBA64 && targetIsRGBA64 {
 		for i := 0; i < numWorkers; i++ {
 			startY := bounds.Min.Y + i*rowsPerWorker
 			endY := startY + rowsPerWorker
@@ -92,10 +129,10 @@ func (p *PixelDiff) Calculate(baseline image.Image, target image.Image) *DiffRes
 
 			go func(startY int, endY int) {
 				defer wg.Done()
-				p.processRGBA64(baselineRGBA64, targetRGBA64, diff, bounds.Min.X, bounds.Max.X, startY, endY, &addedPixelCount, &removedPixelCount)
+				p.processRGBA64(baselineRGBA64, targetRGBA64, diff, bounds.Min.X, bounds.Max.X, startY, endY, &addedPixelCount, &removedPixelCount, &changedPixelCount)
 			}(startY, endY)
 		}
-	} else if baselineIsNRGBA64 && targetIsNRGBA64 && diff.Bounds().Eq(bounds) {
+	} else if baselineIsNRGBA64 && targetIsNRGBA64 {
 		for i := 0; i < numWorkers; i++ {
 			startY := bounds.Min.Y + i*rowsPerWorker
 			endY := startY + rowsPerWorker
@@ -105,10 +142,10 @@ func (p *PixelDiff) Calculate(baseline image.Image, target image.Image) *DiffRes
 
 			go func(startY int, endY int) {
 				defer wg.Done()
-				p.processNRGBA64(baselineNRGBA64, targetNRGBA64, diff, bounds.Min.X, bounds.Max.X, startY, endY, &addedPixelCount, &removedPixelCount)
+				p.processNRGBA64(baselineNRGBA64, targetNRGBA64, diff, bounds.Min.X, bounds.Max.X, startY, endY, &addedPixelCount, &removedPixelCount, &changedPixelCount)
 			}(startY, endY)
 		}
-	} else if baselineIsYCbCr && targetIsYCbCr && diff.Bounds().Eq(bounds) {
+	} else if baselineIsYCbCr && targetIsYCbCr {
 		for i := 0; i < numWorkers; i++ {
 			startY := bounds.Min.Y + i*rowsPerWorker
 			endY := startY + rowsPerWorker
@@ -118,7 +155,7 @@ func (p *PixelDiff) Calculate(baseline image.Image, target image.Image) *DiffRes
 
 			go func(startY int, endY int) {
 				defer wg.Done()
-				p.processYCbCr(baselineYCbCr, targetYCbCr, diff, bounds.Min.X, bounds.Max.X, startY, endY, &addedPixelCount, &removedPixelCount)
+				p.processYCbCr(baselineYCbCr, targetYCbCr, diff, bounds.Min.X, bounds.Max.X, startY, endY, &addedPixelCount, &removedPixelCount, &changedPixelCount)
 			}(startY, endY)
 		}
 	} else {
@@ -131,27 +168,20 @@ func (p *PixelDiff) Calculate(baseline image.Image, target image.Image) *DiffRes
 
 			go func(startY int, endY int) {
 				defer wg.Done()
-				p.processGeneric(baseline, target, diff, bounds.Min.X, bounds.Max.X, startY, endY, &addedPixelCount, &removedPixelCount)
+				p.processGeneric(baseline, target, diff, bounds.Min.X, bounds.Max.X, startY, endY, &addedPixelCount, &removedPixelCount, &changedPixelCount)
 			}(startY, endY)
 		}
 	}
 
 	wg.Wait()
 
-	diffAmount := 0.0
-	if totalPixelCount > 0 {
-		diffAmount = float64(addedPixelCount+removedPixelCount) / float64(totalPixelCount)
-	}
-
-	return &DiffResult{
-		Image:      diff,
-		DiffAmount: diffAmount,
-	}
+	return addedPixelCount + removedPixelCount + changedPixelCount
 }
 
-func (p *PixelDiff) processRGBA(baseline *image.RGBA, target *image.RGBA, diff *image.RGBA, minX int, maxX int, startY int, endY int, addedCount *int64, removedCount *int64) {
+func (p *PixelDiff) processRGBA(baseline *image.RGBA, target *image.RGBA, diff *image.RGBA, minX int, maxX int, startY int, endY int, addedCount *int64, removedCount *int64, changedCount *int64) {
 	var localAdded int64
 	var localRemoved int64
+	var localChanged int64
 
 	for y := startY; y < endY; y++ {
 		baselineRowStart := baseline.PixOffset(minX, y)
@@ -176,22 +206,27 @@ func (p *PixelDiff) processRGBA(baseline *image.RGBA, target *image.RGBA, diff *
 				ta := target.Pix[targetOffset+3]
 
 				if br == tr && bg == tg && bb == tb && ba == ta {
-					diff.Pix[diffOffset] = br
-					diff.Pix[diffOffset+1] = bg
-					diff.Pix[diffOffset+2] = bb
-					diff.Pix[diffOffset+3] = ba
+					dr, dg, db, da := p.palette.dim(br, bg, bb, ba)
+					diff.Pix[diffOffset] = dr
+					diff.Pix[diffOffset+1] = dg
+					diff.Pix[diffOffset+2] = db
+					diff.Pix[diffOffset+3] = da
 				} else {
-					dr, dg, db, da := p.getDiffColor(br, bg, bb, ba, tr, tg, tb, ta)
+					kind := p.classifyPixel(baseline, target, minX+x, y, br, bg, bb, tr, tg, tb)
+					dr, dg, db, da := p.getDiffColor(kind, br, bg, bb, ba)
 
 					diff.Pix[diffOffset] = dr
 					diff.Pix[diffOffset+1] = dg
 					diff.Pix[diffOffset+2] = db
 					diff.Pix[diffOffset+3] = da
 
-					if dr == 255 && dg == 0 && db == 0 {
+					switch kind {
+					case pixelAdded:
 						localAdded++
-					} else if dr == 0 && dg == 0 && db == 255 {
+					case pixelRemoved:
 						localRemoved++
+					case pixelHueChanged:
+						localChanged++
 					}
 				}
 			} else {
@@ -205,11 +240,13 @@ func (p *PixelDiff) processRGBA(baseline *image.RGBA, target *image.RGBA, diff *
 
 	atomic.AddInt64(addedCount, localAdded)
 	atomic.AddInt64(removedCount, localRemoved)
+	atomic.AddInt64(changedCount, localChanged)
 }
 
-func (p *PixelDiff) processNRGBA(baseline *image.NRGBA, target *image.NRGBA, diff *image.RGBA, minX int, maxX int, startY int, endY int, addedCount *int64, removedCount *int64) {
+func (p *PixelDiff) processNRGBA(baseline *image.NRGBA, target *image.NRGBA, diff *image.RGBA, minX int, maxX int, startY int, endY int, addedCount *int64, removedCount *int64, changedCount *int64) {
 	var localAdded int64
 	var localRemoved int64
+	var localChanged int64
 
 	for y := startY; y < endY; y++ {
 		baselineRowStart := baseline.PixOffset(minX, y)
@@ -234,22 +271,27 @@ func (p *PixelDiff) processNRGBA(baseline *image.NRGBA, target *image.NRGBA, dif
 				ta := target.Pix[targetOffset+3]
 
 				if br == tr && bg == tg && bb == tb && ba == ta {
-					diff.Pix[diffOffset] = br
-					diff.Pix[diffOffset+1] = bg
-					diff.Pix[diffOffset+2] = bb
-					diff.Pix[diffOffset+3] = ba
+					dr, dg, db, da := p.palette.dim(br, bg, bb, ba)
+					diff.Pix[diffOffset] = dr
+					diff.Pix[diffOffset+1] = dg
+					diff.Pix[diffOffset+2] = db
+					diff.Pix[diffOffset+3] = da
 				} else {
-					dr, dg, db, da := p.getDiffColor(br, bg, bb, ba, tr, tg, tb, ta)
+					kind := p.classifyPixel(baseline, target, minX+x, y, br, bg, bb, tr, tg, tb)
+					dr, dg, db, da := p.getDiffColor(kind, br, bg, bb, ba)
 
 					diff.Pix[diffOffset] = dr
 					diff.Pix[diffOffset+1] = dg
 					diff.Pix[diffOffset+2] = db
 					diff.Pix[diffOffset+3] = da
 
-					if dr == 255 && dg == 0 && db == 0 {
+					switch kind {
+					case pixelAdded:
 						localAdded++
-					} else if dr == 0 && dg == 0 && db == 255 {
+					case pixelRemoved:
 						localRemoved++
+					case pixelHueChanged:
+						localChanged++
 					}
 				}
 			} else {
@@ -263,11 +305,13 @@ func (p *PixelDiff) processNRGBA(baseline *image.NRGBA, target *image.NRGBA, dif
 
 	atomic.AddInt64(addedCount, localAdded)
 	atomic.AddInt64(removedCount, localRemoved)
+	atomic.AddInt64(changedCount, localChanged)
 }
 
-func (p *PixelDiff) processRGBA64(baseline *image.RGBA64, target *image.RGBA64, diff *image.RGBA, minX int, maxX int, startY int, endY int, addedCount *int64, removedCount *int64) {
+func (p *PixelDiff) processRGBA64(baseline *image.RGBA64, target *image.RGBA64, diff *image.RGBA, minX int, maxX int, startY int, endY int, addedCount *int64, removedCount *int64, changedCount *int64) {
 	var localAdded int64
 	var localRemoved int64
+	var localChanged int64
 
 	for y := startY; y < endY; y++ {
 		baselineRowStart := baseline.PixOffset(minX, y)
@@ -292,22 +336,27 @@ func (p *PixelDiff) processRGBA64(baseline *image.RGBA64, target *image.RGBA64,
 				ta := target.Pix[targetOffset+7]
 
 				if br == tr && bg == tg && bb == tb && ba == ta {
-					diff.Pix[diffOffset] = br
-					diff.Pix[diffOffset+1] = bg
-					diff.Pix[diffOffset+2] = bb
-					diff.Pix[diffOffset+3] = ba
+					dr, dg, db, da := p.palette.dim(br, bg, bb, ba)
+					diff.Pix[diffOffset] = dr
+					diff.Pix[diffOffset+1] = dg
+					diff.Pix[diffOffset+2] = db
+					diff.Pix[diffOffset+3] = da
 				} else {
-					dr, dg, db, da := p.getDiffColor(br, bg, bb, ba, tr, tg, tb, ta)
+					kind := p.classifyPixel(baseline, target, minX+x, y, br, bg, bb, tr, tg, tb)
+					dr, dg, db, da := p.getDiffColor(kind, br, bg, bb, ba)
 
 					diff.Pix[diffOffset] = dr
 					diff.Pix[diffOffset+1] = dg
 					diff.Pix[diffOffset+2] = db
 					diff.Pix[diffOffset+3] = da
 
-					if dr == 255 && dg == 0 && db == 0 {
+					switch kind {
+					case pixelAdded:
 						localAdded++
-					} else if dr == 0 && dg == 0 && db == 255 {
+					case pixelRemoved:
 						localRemoved++
+					case pixelHueChanged:
+						localChanged++
 					}
 				}
 			} else {
@@ -321,11 +370,13 @@ func (p *PixelDiff) processRGBA64(baseline *image.RGBA64, target *image.RGBA64,
 
 	atomic.AddInt64(addedCount, localAdded)
 	atomic.AddInt64(removedCount, localRemoved)
+	atomic.AddInt64(changedCount, localChanged)
 }
 
-func (p *PixelDiff) processNRGBA64(baseline *image.NRGBA64, target *image.NRGBA64, diff *image.RGBA, minX int, maxX int, startY int, endY int, addedCount *int64, removedCount *int64) {
+func (p *PixelDiff) processNRGBA64(baseline *image.NRGBA64, target *image.NRGBA64, diff *image.RGBA, minX int, maxX int, startY int, endY int, addedCount *int64, removedCount *int64, changedCount *int64) {
 	var localAdded int64
 	var localRemoved int64
+	var localChanged int64
 
 	for y := startY; y < endY; y++ {
 		baselineRowStart := baseline.PixOffset(minX, y)
@@ -350,22 +401,27 @@ func (p *PixelDiff) processNRGBA64(baseline *image.NRGBA64, target *image.NRGBA6
 				ta := target.Pix[targetOffset+7]
 
 				if br == tr && bg == tg && bb == tb && ba == ta {
-					diff.Pix[diffOffset] = br
-					diff.Pix[diffOffset+1] = bg
-					diff.Pix[diffOffset+2] = bb
-					diff.Pix[diffOffset+3] = ba
+					dr, dg, db, da := p.palette.dim(br, bg, bb, ba)
+					diff.Pix[diffOffset] = dr
+					diff.Pix[diffOffset+1] = dg
+					diff.Pix[diffOffset+2] = db
+					diff.Pix[diffOffset+3] = da
 				} else {
-					dr, dg, db, da := p.getDiffColor(br, bg, bb, ba, tr, tg, tb, ta)
+					kind := p.classifyPixel(baseline, target, minX+x, y, br, bg, bb, tr, tg, tb)
+					dr, dg, db, da := p.getDiffColor(kind, br, bg, bb, ba)
 
 					diff.Pix[diffOffset] = dr
 					diff.Pix[diffOffset+1] = dg
 					diff.Pix[diffOffset+2] = db
 					diff.Pix[diffOffset+3] = da
 
-					if dr == 255 && dg == 0 && db == 0 {
+					switch kind {
+					case pixelAdded:
 						localAdded++
-					} else if dr == 0 && dg == 0 && db == 255 {
+					case pixelRemoved:
 						localRemoved++
+					case pixelHueChanged:
+						localChanged++
 					}
 				}
 			} else {
@@ -379,11 +435,13 @@ func (p *PixelDiff) processNRGBA64(baseline *image.NRGBA64, target *image.NRGBA6
 
 	atomic.AddInt64(addedCount, localAdded)
 	atomic.AddInt64(removedCount, localRemoved)
+	atomic.AddInt64(changedCount, localChanged)
 }
 
-func (p *PixelDiff) processGeneric(baseline image.Image, target image.Image, diff *image.RGBA, minX int, maxX int, startY int, endY int, addedCount *int64, removedCount *int64) {
+func (p *PixelDiff) processGeneric(baseline image.Image, target image.Image, diff *image.RGBA, minX int, maxX int, startY int, endY int, addedCount *int64, removedCount *int64, changedCount *int64) {
 	var localAdded int64
 	var localRemoved int64
+	var localChanged int64
 
 	for y := startY; y < endY; y++ {
 		for x := minX; x < maxX; x++ {
@@ -391,16 +449,21 @@ func (p *PixelDiff) processGeneric(baseline image.Image, target image.Image, dif
 			targetColor := p.getColorAt(target, x, y)
 
 			if colorsEqual(baselineColor, targetColor) {
-				diff.Set(x, y, baselineColor)
+				dr, dg, db, da := p.palette.dim(baselineColor.R, baselineColor.G, baselineColor.B, baselineColor.A)
+				diff.SetRGBA(x, y, color.RGBA{R: dr, G: dg, B: db, A: da})
 			} else {
-				dr, dg, db, da := p.getDiffColor(baselineColor.R, baselineColor.G, baselineColor.B, baselineColor.A, targetColor.R, targetColor.G, targetColor.B, targetColor.A)
+				kind := p.classifyPixel(baseline, target, x, y, baselineColor.R, baselineColor.G, baselineColor.B, targetColor.R, targetColor.G, targetColor.B)
+				dr, dg, db, da := p.getDiffColor(kind, baselineColor.R, baselineColor.G, baselineColor.B, baselineColor.A)
 				diffColor := color.RGBA{R: dr, G: dg, B: db, A: da}
 				diff.Set(x, y, diffColor)
 
-				if dr == 255 && dg == 0 && db == 0 {
+				switch kind {
+				case pixelAdded:
 					localAdded++
-				} else if dr == 0 && dg == 0 && db == 255 {
+				case pixelRemoved:
 					localRemoved++
+				case pixelHueChanged:
+					localChanged++
 				}
 			}
 		}
@@ -408,6 +471,7 @@ func (p *PixelDiff) processGeneric(baseline image.Image, target image.Image, dif
 
 	atomic.AddInt64(addedCount, localAdded)
 	atomic.AddInt64(removedCount, localRemoved)
+	atomic.AddInt64(changedCount, localChanged)
 }
 
 func (p *PixelDiff) calculateUnionBounds(baseline image.Image, target image.Image) image.Rectangle {
@@ -442,12 +506,26 @@ func (p *PixelDiff) getColorAt(img image.Image, x int, y int) color.RGBA {
 	if x < bounds.Min.X || x >= bounds.Max.X || y < bounds.Min.Y || y >= bounds.Max.Y {
 		return color.RGBA{R: 255, G: 255, B: 255, A: 255}
 	}
+	return rgbaAt(img, x, y)
+}
+
+// rgbaImage is implemented by *image.RGBA and the images computed on demand, whose RGBAAt avoids the allocation of
+// boxing every color that At returns.
+type rgbaImage interface {
+	RGBAAt(x int, y int) color.RGBA
+}
+
+func rgbaAt(img image.Image, x int, y int) color.RGBA {
+	if i, ok := img.(rgbaImage); ok {
+		return i.RGBAAt(x, y)
+	}
 	return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
 }
 
-func (p *PixelDiff) processYCbCr(baseline *image.YCbCr, target *image.YCbCr, diff *image.RGBA, minX int, maxX int, startY int, endY int, addedCount *int64, removedCount *int64) {
+func (p *PixelDiff) processYCbCr(baseline *image.YCbCr, target *image.YCbCr, diff *image.RGBA, minX int, maxX int, startY int, endY int, addedCount *int64, removedCount *int64, changedCount *int64) {
 	var localAdded int64
 	var localRemoved int64
+	var localChanged int64
 
 	for y := startY; y < endY; y++ {
 		for x := minX; x < maxX; x++ {
@@ -471,31 +549,36 @@ func (p *PixelDiff) processYCbCr(baseline *image.YCbCr, target *image.YCbCr, dif
 				diffOffset := diff.PixOffset(x, y)
 
 				if by == ty && bcb == tcb && bcr == tcr {
-					r, g, b, a := p.ycbcrToRGBA(by, bcb, bcr)
-					diff.Pix[diffOffset] = r
-					diff.Pix[diffOffset+1] = g
-					diff.Pix[diffOffset+2] = b
-					diff.Pix[diffOffset+3] = a
+					dr, dg, db, da := p.palette.dim(p.ycbcrToRGBA(by, bcb, bcr))
+					diff.Pix[diffOffset] = dr
+					diff.Pix[diffOffset+1] = dg
+					diff.Pix[diffOffset+2] = db
+					diff.Pix[diffOffset+3] = da
 				} else {
 					br, bg, bb, ba := p.ycbcrToRGBA(by, bcb, bcr)
 					tr, tg, tb, ta := p.ycbcrToRGBA(ty, tcb, tcr)
 
 					if br == tr && bg == tg && bb == tb && ba == ta {
-						diff.Pix[diffOffset] = br
-						diff.Pix[diffOffset+1] = bg
-						diff.Pix[diffOffset+2] = bb
-						diff.Pix[diffOffset+3] = ba
+						dr, dg, db, da := p.palette.dim(br, bg, bb, ba)
+						diff.Pix[diffOffset] = dr
+						diff.Pix[diffOffset+1] = dg
+						diff.Pix[diffOffset+2] = db
+						diff.Pix[diffOffset+3] = da
 					} else {
-						dr, dg, db, da := p.getDiffColor(br, bg, bb, ba, tr, tg, tb, ta)
+						kind := p.classifyPixel(baseline, target, x, y, br, bg, bb, tr, tg, tb)
+						dr, dg, db, da := p.getDiffColor(kind, br, bg, bb, ba)
 						diff.Pix[diffOffset] = dr
 						diff.Pix[diffOffset+1] = dg
 						diff.Pix[diffOffset+2] = db
 						diff.Pix[diffOffset+3] = da
 
-						if dr == 255 && dg == 0 && db == 0 {
+						switch kind {
+						case pixelAdded:
 							localAdded++
-						} else if dr == 0 && dg == 0 && db == 255 {
+						case pixelRemoved:
 							localRemoved++
+						case pixelHueChanged:
+							localChanged++
 						}
 					}
 				}
@@ -511,6 +594,7 @@ func (p *PixelDiff) processYCbCr(baseline *image.YCbCr, target *image.YCbCr, dif
 
 	atomic.AddInt64(addedCount, localAdded)
 	atomic.AddInt64(removedCount, localRemoved)
+	atomic.AddInt64(changedCount, localChanged)
 }
 
 func (p *PixelDiff) ycbcrToRGBA(y uint8, cb uint8, cr uint8) (uint8, uint8, uint8, uint8) {
@@ -576,21 +660,57 @@ func colorsEqual(c1 color.RGBA, c2 color.RGBA) bool {
 	return c1.R == c2.R && c1.G == c2.G && c1.B == c2.B && c1.A == c2.A
 }
 
-func (p *PixelDiff) getDiffColor(br uint8, bg uint8, bb uint8, ba uint8, tr uint8, tg uint8, tb uint8, ta uint8) (uint8, uint8, uint8, uint8) {
-	const (
-		redColor  = 255
-		blueColor = 255
-	)
+// pixelKind is the classification of a pixel whose color differs between baseline and target.
+type pixelKind int
+
+const (
+	pixelUnchanged pixelKind = iota
+	// pixelAdded is a pixel that got brighter.
+	pixelAdded
+	// pixelRemoved is a pixel that got darker.
+	pixelRemoved
+	// pixelHueChanged is a pixel whose color changed perceptibly without getting brighter or darker.
+	pixelHueChanged
+	// pixelAntiAliased is a changed pixel caused by anti-aliasing, which is not counted as a difference.
+	pixelAntiAliased
+	// pixelIgnored is a changed pixel in an ignored region, which is neither counted nor rendered as a difference.
+	pixelIgnored
+)
 
+func (p *PixelDiff) classifyPixel(baseline image.Image, target image.Image, x int, y int, br uint8, bg uint8, bb uint8, tr uint8, tg uint8, tb uint8) pixelKind {
 	baselineBrightness := int(br) + int(bg) + int(bb)
 	targetBrightness := int(tr) + int(tg) + int(tb)
 	normalizedDiff := float64(targetBrightness-baselineBrightness) / (255.0 * 3.0)
 
+	kind := pixelUnchanged
 	if normalizedDiff > p.threshold {
-		return redColor, 0, 0, 255
+		kind = pixelAdded
 	} else if normalizedDiff < -p.threshold {
-		return 0, 0, blueColor, 255
-	} else {
-		return br, bg, bb, ba
+		kind = pixelRemoved
+	} else if p.perceptualColorDistance && yiqDelta(br, bg, bb, tr, tg, tb) > maxYIQDelta*p.colorTolerance*p.colorTolerance {
+		kind = pixelHueChanged
+	}
+
+	if kind != pixelUnchanged && p.isIgnored(x, y) {
+		return pixelIgnored
+	}
+	if kind != pixelUnchanged && p.detectAntiAliasing && isAntiAliased(baseline, target, x, y) {
+		return pixelAntiAliased
+	}
+	return kind
+}
+
+func (p *PixelDiff) getDiffColor(kind pixelKind, br uint8, bg uint8, bb uint8, ba uint8) (uint8, uint8, uint8, uint8) {
+	switch kind {
+	case pixelAdded:
+		return p.palette.Added.R, p.palette.Added.G, p.palette.Added.B, p.palette.Added.A
+	case pixelRemoved:
+		return p.palette.Removed.R, p.palette.Removed.G, p.palette.Removed.B, p.palette.Removed.A
+	case pixelHueChanged:
+		return p.palette.Hue.R, p.palette.Hue.G, p.palette.Hue.B, p.palette.Hue.A
+	case pixelAntiAliased:
+		return p.palette.AntiAliased.R, p.palette.AntiAliased.G, p.palette.AntiAliased.B, p.palette.AntiAliased.A
+	default:
+		return p.palette.dim(br, bg, bb, ba)
 	}
 }
@@ -66,6 +66,72 @@ func TestPixelDiff_Calculate(t *testing.T) {
 	})
 }
 
+func TestPixelDiff_AntiAliasing(t *testing.T) {
+	createEdgeImages := func() (*image.RGBA, *image.RGBA) {
+		baseline := createTestImage(100, 100, color.White)
+		target := createTestImage(100, 100, color.White)
+		for y := 0; y < 100; y++ {
+			for x := 50; x < 53; x++ {
+				baseline.Set(x, y, color.Black)
+				target.Set(x, y, color.Black)
+			}
+			target.Set(49, y, color.Gray{Y: 128})
+		}
+		return baseline, target
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		baseline, target := createEdgeImages()
+
+		result := NewPixelDiff(0.1, WithAntiAliasingDetection(true)).Calculate(baseline, target)
+
+		if result.DiffAmount != 0.0 {
+			t.Errorf("Expected DiffAmount to be 0.0, got %f", result.DiffAmount)
+		}
+
+		r, g, b, _ := result.Image.At(49, 10).RGBA()
+		if r>>8 != 255 || g>>8 != 255 || b>>8 != 0 {
+			t.Errorf("Expected anti-aliased pixel to be yellow, got (%d, %d, %d)", r>>8, g>>8, b>>8)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		baseline, target := createEdgeImages()
+
+		result := NewPixelDiff(0.1).Calculate(baseline, target)
+
+		if result.DiffAmount != 0.01 {
+			t.Errorf("Expected DiffAmount to be 0.01, got %f", result.DiffAmount)
+		}
+	})
+}
+
+func TestPixelDiff_IgnoreRegions(t *testing.T) {
+	img1 := createTestImage(100, 100, color.White)
+	img2 := createTestImage(100, 100, color.White)
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 100; x++ {
+			img2.Set(x, y, color.Black)
+		}
+	}
+	for y := 50; y < 60; y++ {
+		for x := 0; x < 50; x++ {
+			img2.Set(x, y, color.Black)
+		}
+	}
+
+	result := NewPixelDiff(0.1, WithIgnoreRegions(Rectangle{X: 0, Y: 0, Width: 100, Height: 20})).Calculate(img1, img2)
+
+	if expected := 500.0 / 8000.0; result.DiffAmount != expected {
+		t.Errorf("Expected DiffAmount to be %f, got %f", expected, result.DiffAmount)
+	}
+
+	r, g, b, _ := result.Image.At(10, 10).RGBA()
+	if r>>8 != 255 || g>>8 != 255 || b>>8 != 255 {
+		t.Errorf("Expected ignored pixel to keep the baseline color, got (%d, %d, %d)", r>>8, g>>8, b>>8)
+	}
+}
+
 func BenchmarkPixelDiff_Calculate_Small(b *testing.B) {
 	pd := NewPixelDiff(0.1)
 	img1 := createTestImage(1920, 1080, color.White)
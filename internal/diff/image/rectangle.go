@@ -9,36 +9,74 @@ import (
 )
 
 type Rectangle struct {
-	X      int
-	Y      int
-	Width  int
-	Height int
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
 }
 
-type RectangleDiff struct{}
+type RectangleDiff struct {
+	options
+}
 
-func NewRectangleDiff() *RectangleDiff {
-	return &RectangleDiff{}
+func NewRectangleDiff(opts ...Option) *RectangleDiff {
+	return &RectangleDiff{
+		options: newOptions(opts),
+	}
 }
 
 func (r *RectangleDiff) Calculate(baseline image.Image, target image.Image) *DiffResult {
-	if baseline == target {
+	if r.unchanged(baseline, target) {
 		return &DiffResult{
 			Image:      baseline,
 			DiffAmount: 0.0,
 		}
 	}
 
-	rectangles := r.findRectangles(baseline, target)
+	change := dimensionChange(baseline, target)
+	baseline, target = r.fitSizes(baseline, target)
+
+	// The full-size path holds two boolean maps of the union bounds and an RGBA copy of the target.
+	union := r.unionBounds(baseline, target)
+	stripHeight := r.stripHeight(union.Dx()*6, union.Dy())
+
+	regions := r.findRectangles(baseline, target, stripHeight)
+	rectangles := make([]Rectangle, len(regions))
+	for i, region := range regions {
+		rectangles[i] = region.Rectangle
+	}
 
 	bounds := target.Bounds()
-	result := image.NewRGBA(bounds)
-	draw.Draw(result, bounds, target, bounds.Min, draw.Src)
+	var result image.Image
+	if stripHeight > 0 {
+		result = newStripImage(bounds, stripHeight, func(strip *image.RGBA) {
+			draw.Draw(strip, strip.Bounds(), target, strip.Bounds().Min, draw.Src)
+			r.drawRectangles(strip, rectangles, bounds)
+		})
+	} else {
+		full := image.NewRGBA(bounds)
+		draw.Draw(full, bounds, target, bounds.Min, draw.Src)
+		r.drawRectangles(full, rectangles, bounds)
+		result = full
+	}
+
+	diffAmount := r.calculateDiffAmount(baseline, target, rectangles)
+
+	return &DiffResult{
+		Image:           result,
+		DiffAmount:      diffAmount,
+		Regions:         regions,
+		DimensionChange: change,
+	}
+}
 
-	rectColor := color.RGBA{R: 255, A: 255} // Red color for rectangles
+// drawRectangles draws the borders of the rectangles within the bounds onto result, which may cover only a part of the
+// bounds.
+func (r *RectangleDiff) drawRectangles(result *image.RGBA, rectangles []Rectangle, bounds image.Rectangle) {
+	rectColor := r.palette.Changed
 
 	for _, rect := range rectangles {
-		for thickness := 0; thickness < 3; thickness++ {
+		for thickness := 0; thickness < r.strokeWidth; thickness++ {
 			for x := rect.X - thickness; x < rect.X+rect.Width+thickness; x++ {
 				if x >= 0 && x < bounds.Max.X {
 					if rect.Y-thickness >= 0 {
@@ -62,42 +100,112 @@ func (r *RectangleDiff) Calculate(baseline image.Image, target image.Image) *Dif
 			}
 		}
 	}
+}
 
-	diffAmount := r.calculateDiffAmount(baseline, target, rectangles)
+func (r *RectangleDiff) findRectangles(baseline image.Image, target image.Image, stripHeight int) []ChangedRegion {
+	union := r.unionBounds(baseline, target)
+	if stripHeight > 0 {
+		return r.findRectanglesInStrips(baseline, target, union, stripHeight)
+	}
 
-	return &DiffResult{
-		Image:      result,
-		DiffAmount: diffAmount,
+	minX := union.Min.X
+	minY := union.Min.Y
+	height := union.Dy()
+	width := union.Dx()
+	diffMap := make([][]bool, height)
+	for i := range diffMap {
+		diffMap[i] = make([]bool, width)
 	}
-}
+	r.compareRows(baseline, target, diffMap, union, 0, height)
 
-func (r *RectangleDiff) findRectangles(baseline image.Image, target image.Image) []Rectangle {
-	bounds := baseline.Bounds()
-	targetBounds := target.Bounds()
+	visited := make([][]bool, height)
+	for i := range visited {
+		visited[i] = make([]bool, width)
+	}
 
-	minX := bounds.Min.X
-	if targetBounds.Min.X < minX {
-		minX = targetBounds.Min.X
+	var rectangles []Rectangle
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if diffMap[y][x] && !visited[y][x] {
+				rect := r.findBoundingBox(diffMap, visited, x, y, width, height, minX, minY)
+				if rect.Width > 2 && rect.Height > 2 {
+					rectangles = append(rectangles, rect)
+				}
+			}
+		}
 	}
-	minY := bounds.Min.Y
-	if targetBounds.Min.Y < minY {
-		minY = targetBounds.Min.Y
+
+	rectangles = r.mergeRectangles(rectangles)
+
+	regions := make([]ChangedRegion, len(rectangles))
+	for i, rect := range rectangles {
+		regions[i] = r.changedRegion(rect, r.countChanged(diffMap, rect, minX, minY))
 	}
-	maxX := bounds.Max.X
-	if targetBounds.Max.X > maxX {
-		maxX = targetBounds.Max.X
+	return regions
+}
+
+// findRectanglesInStrips finds the same rectangles as findRectangles while holding the diff map of stripHeight rows at
+// a time. The components are labeled in a first pass and the changed pixels of the merged rectangles are counted in a
+// second pass, because the rectangles are only known once every row has been labeled.
+func (r *RectangleDiff) findRectanglesInStrips(baseline image.Image, target image.Image, union image.Rectangle, stripHeight int) []ChangedRegion {
+	height := union.Dy()
+	width := union.Dx()
+	buffer := make([]bool, width*stripHeight)
+
+	// Rows outside of the current strip are nil, so that the diff map keeps the indices of the full-size one.
+	diffMap := make([][]bool, height)
+	forEachStrip := func(f func(startY int, endY int)) {
+		for startY := 0; startY < height; startY += stripHeight {
+			endY := min(startY+stripHeight, height)
+			clear(buffer)
+			for y := startY; y < endY; y++ {
+				diffMap[y] = buffer[(y-startY)*width : (y-startY+1)*width]
+			}
+			r.compareRows(baseline, target, diffMap, union, startY, endY)
+			f(startY, endY)
+			for y := startY; y < endY; y++ {
+				diffMap[y] = nil
+			}
+		}
 	}
-	maxY := bounds.Max.Y
-	if targetBounds.Max.Y > maxY {
-		maxY = targetBounds.Max.Y
+
+	labeler := newComponentLabeler(width)
+	forEachStrip(func(startY int, endY int) {
+		for y := startY; y < endY; y++ {
+			labeler.addRow(diffMap[y], y)
+		}
+	})
+
+	var rectangles []Rectangle
+	for _, rect := range labeler.rectangles(union.Min.X, union.Min.Y) {
+		if rect.Width > 2 && rect.Height > 2 {
+			rectangles = append(rectangles, rect)
+		}
 	}
+	rectangles = r.mergeRectangles(rectangles)
 
-	height := maxY - minY
-	width := maxX - minX
-	diffMap := make([][]bool, height)
-	for i := range diffMap {
-		diffMap[i] = make([]bool, width)
+	changedCounts := make([]int, len(rectangles))
+	forEachStrip(func(startY int, endY int) {
+		for i, rect := range rectangles {
+			changedCounts[i] += r.countChanged(diffMap, rect, union.Min.X, union.Min.Y)
+		}
+	})
+
+	regions := make([]ChangedRegion, len(rectangles))
+	for i, rect := range rectangles {
+		regions[i] = r.changedRegion(rect, changedCounts[i])
 	}
+	return regions
+}
+
+// compareRows marks the changed pixels of the rows [startRow, endRow) of the diff map, which is indexed from the top of the
+// union bounds, and clears the ignored regions within them.
+func (r *RectangleDiff) compareRows(baseline image.Image, target image.Image, diffMap [][]bool, union image.Rectangle, startRow int, endRow int) {
+	bounds := baseline.Bounds()
+	targetBounds := target.Bounds()
+	minX := union.Min.X
+	minY := union.Min.Y
+	maxX := union.Max.X
 
 	baselineRGBA, baselineIsRGBA := baseline.(*image.RGBA)
 	targetRGBA, targetIsRGBA := target.(*image.RGBA)
@@ -108,16 +216,16 @@ func (r *RectangleDiff) findRectangles(baseline image.Image, target image.Image)
 	// https://tip.golang.org/doc/go1.25#container-aware-gomaxprocs
 	numWorkers := runtime.GOMAXPROCS(0)
 
-	rowsPerWorker := height / numWorkers
+	rowsPerWorker := (endRow - startRow) / numWorkers
 	var wg sync.WaitGroup
 	wg.Add(numWorkers)
 
 	if baselineIsRGBA && targetIsRGBA {
 		for i := 0; i < numWorkers; i++ {
-			startY := i * rowsPerWorker
+			startY := startRow + i*rowsPerWorker
 			endY := startY + rowsPerWorker
 			if i == numWorkers-1 {
-				endY = height
+				endY = endRow
 			}
 
 			go func(startY, endY int) {
@@ -127,10 +235,10 @@ func (r *RectangleDiff) findRectangles(baseline image.Image, target image.Image)
 		}
 	} else if baselineIsYCbCr && targetIsYCbCr {
 		for i := 0; i < numWorkers; i++ {
-			startY := i * rowsPerWorker
+			startY := startRow + i*rowsPerWorker
 			endY := startY + rowsPerWorker
 			if i == numWorkers-1 {
-				endY = height
+				endY = endRow
 			}
 
 			go func(startY, endY int) {
@@ -140,10 +248,10 @@ func (r *RectangleDiff) findRectangles(baseline image.Image, target image.Image)
 		}
 	} else {
 		for i := 0; i < numWorkers; i++ {
-			startY := i * rowsPerWorker
+			startY := startRow + i*rowsPerWorker
 			endY := startY + rowsPerWorker
 			if i == numWorkers-1 {
-				endY = height
+				endY = endRow
 			}
 
 			go func(startY, endY int) {
@@ -155,24 +263,42 @@ func (r *RectangleDiff) findRectangles(baseline image.Image, target image.Image)
 
 	wg.Wait()
 
-	visited := make([][]bool, height)
-	for i := range visited {
-		visited[i] = make([]bool, width)
+	for _, region := range r.ignoreRegions {
+		for y := max(region.Y, minY+startRow); y < min(region.Y+region.Height, minY+endRow); y++ {
+			for x := max(region.X, minX); x < min(region.X+region.Width, maxX); x++ {
+				diffMap[y-minY][x-minX] = false
+			}
+		}
 	}
+}
 
-	var rectangles []Rectangle
-	for y := 0; y < height; y++ {
-		for x := 0; x < width; x++ {
-			if diffMap[y][x] && !visited[y][x] {
-				rect := r.findBoundingBox(diffMap, visited, x, y, width, height, minX, minY)
-				if rect.Width > 2 && rect.Height > 2 {
-					rectangles = append(rectangles, rect)
-				}
+// countChanged counts the changed pixels within the rectangle, including the small clusters that were dropped as noise.
+// Nil rows of the diff map are skipped.
+func (r *RectangleDiff) countChanged(diffMap [][]bool, rect Rectangle, offsetX int, offsetY int) int {
+	changedCount := 0
+	for y := max(rect.Y-offsetY, 0); y < min(rect.Y+rect.Height-offsetY, len(diffMap)); y++ {
+		row := diffMap[y]
+		for x := max(rect.X-offsetX, 0); x < min(rect.X+rect.Width-offsetX, len(row)); x++ {
+			if row[x] {
+				changedCount++
 			}
 		}
 	}
+	return changedCount
+}
+
+func (r *RectangleDiff) changedRegion(rect Rectangle, changedCount int) ChangedRegion {
+	area := rect.Width * rect.Height
+	pixelRatio := 0.0
+	if area > 0 {
+		pixelRatio = float64(changedCount) / float64(area)
+	}
 
-	return r.mergeRectangles(rectangles)
+	return ChangedRegion{
+		Rectangle:  rect,
+		Area:       area,
+		PixelRatio: pixelRatio,
+	}
 }
 
 func (r *RectangleDiff) findBoundingBox(diffMap [][]bool, visited [][]bool, startX int, startY int, width int, height int, offsetX int, offsetY int) Rectangle {
@@ -322,12 +448,7 @@ func (r *RectangleDiff) combineRectangles(r1 Rectangle, r2 Rectangle) Rectangle
 	}
 }
 
-func (r *RectangleDiff) calculateDiffAmount(baseline image.Image, target image.Image, rectangles []Rectangle) float64 {
-	totalDiffArea := 0
-	for _, rect := range rectangles {
-		totalDiffArea += rect.Width * rect.Height
-	}
-
+func (r *RectangleDiff) unionBounds(baseline image.Image, target image.Image) image.Rectangle {
 	bounds := baseline.Bounds()
 	targetBounds := target.Bounds()
 
@@ -348,13 +469,23 @@ func (r *RectangleDiff) calculateDiffAmount(baseline image.Image, target image.I
 		maxY = targetBounds.Max.Y
 	}
 
-	totalArea := (maxX - minX) * (maxY - minY)
+	return image.Rect(minX, minY, maxX, maxY)
+}
+
+func (r *RectangleDiff) calculateDiffAmount(baseline image.Image, target image.Image, rectangles []Rectangle) float64 {
+	totalDiffArea := 0
+	for _, rect := range rectangles {
+		totalDiffArea += rect.Width * rect.Height
+	}
+
+	union := r.unionBounds(baseline, target)
+	totalArea := union.Dx()*union.Dy() - r.ignoredArea(union)
 
-	if totalArea == 0 {
+	if totalArea <= 0 {
 		return 0.0
 	}
 
-	return float64(totalDiffArea) / float64(totalArea)
+	return min(float64(totalDiffArea)/float64(totalArea), 1.0)
 }
 
 func (r *RectangleDiff) processRGBA(baseline *image.RGBA, target *image.RGBA, diffMap [][]bool, bounds image.Rectangle, targetBounds image.Rectangle, minX int, maxX int, minY int, startY int, endY int) {
@@ -462,11 +593,11 @@ func (r *RectangleDiff) processGeneric(baseline image.Image, target image.Image,
 			targetColor := color.RGBA{R: 255, G: 255, B: 255, A: 255}
 
 			if x < bounds.Max.X && y < bounds.Max.Y {
-				baselineColor = color.RGBAModel.Convert(baseline.At(x, y)).(color.RGBA)
+				baselineColor = rgbaAt(baseline, x, y)
 			}
 
 			if x < targetBounds.Max.X && y < targetBounds.Max.Y {
-				targetColor = color.RGBAModel.Convert(target.At(x, y)).(color.RGBA)
+				targetColor = rgbaAt(target, x, y)
 			}
 
 			if !colorsEqual(baselineColor, targetColor) {
@@ -66,6 +66,70 @@ func TestRectangleDiff_Calculate(t *testing.T) {
 	})
 }
 
+func TestRectangleDiff_IgnoreRegions(t *testing.T) {
+	img1 := createRectTestImage(100, 100, color.White)
+	img2 := createRectTestImage(100, 100, color.White)
+	for y := 10; y < 30; y++ {
+		for x := 10; x < 30; x++ {
+			img2.Set(x, y, color.Black)
+		}
+	}
+
+	t.Run("Ignored", func(t *testing.T) {
+		result := NewRectangleDiff(WithIgnoreRegions(Rectangle{X: 0, Y: 0, Width: 50, Height: 50})).Calculate(img1, img2)
+
+		if result.DiffAmount != 0.0 {
+			t.Errorf("Expected DiffAmount to be 0.0, got %f", result.DiffAmount)
+		}
+
+		r, g, b, _ := result.Image.At(10, 9).RGBA()
+		if r>>8 != 255 || g>>8 != 255 || b>>8 != 255 {
+			t.Errorf("Expected no rectangle around the ignored region, got (%d, %d, %d)", r>>8, g>>8, b>>8)
+		}
+	})
+
+	t.Run("NotIgnored", func(t *testing.T) {
+		result := NewRectangleDiff(WithIgnoreRegions(Rectangle{X: 50, Y: 50, Width: 50, Height: 50})).Calculate(img1, img2)
+
+		if expected := 400.0 / 7500.0; result.DiffAmount != expected {
+			t.Errorf("Expected DiffAmount to be %f, got %f", expected, result.DiffAmount)
+		}
+	})
+}
+
+func TestRectangleDiff_Regions(t *testing.T) {
+	img1 := createRectTestImage(100, 100, color.White)
+	img2 := createRectTestImage(100, 100, color.White)
+	for y := 10; y < 30; y++ {
+		for x := 10; x < 30; x++ {
+			if x == y {
+				continue
+			}
+			img2.Set(x, y, color.Black)
+		}
+	}
+	for y := 70; y < 80; y++ {
+		for x := 60; x < 90; x++ {
+			img2.Set(x, y, color.Black)
+		}
+	}
+
+	result := NewRectangleDiff().Calculate(img1, img2)
+
+	expected := []ChangedRegion{
+		{Rectangle: Rectangle{X: 10, Y: 10, Width: 20, Height: 20}, Area: 400, PixelRatio: 380.0 / 400.0},
+		{Rectangle: Rectangle{X: 60, Y: 70, Width: 30, Height: 10}, Area: 300, PixelRatio: 1.0},
+	}
+	if len(result.Regions) != len(expected) {
+		t.Fatalf("Expected %d regions, got %v", len(expected), result.Regions)
+	}
+	for i := range expected {
+		if result.Regions[i] != expected[i] {
+			t.Errorf("Expected region %d to be %+v, got %+v", i, expected[i], result.Regions[i])
+		}
+	}
+}
+
 func BenchmarkRectangleDiff_Calculate_Small(b *testing.B) {
 	rd := NewRectangleDiff()
 	img1 := createRectTestImage(1920, 1080, color.White)
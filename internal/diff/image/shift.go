@@ -0,0 +1,299 @@
+package image
+
+import (
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// ShiftDiff tolerates layout shifts by aligning the rows of baseline and target before comparing them, so that a
+// band inserted at the top of the page is reported as an insertion instead of shifting every pixel below it.
+// Aligned rows are compared by PixelDiff and the diff image stacks them together with the removed and inserted bands
+// in page order.
+type ShiftDiff struct {
+	options
+	pixelDiff *PixelDiff
+	// maxEdits bounds the alignment cost. Images that differ in more rows are only aligned by their common prefix and
+	// suffix.
+	maxEdits int
+}
+
+func NewShiftDiff(threshold float64, opts ...Option) *ShiftDiff {
+	return &ShiftDiff{
+		options: newOptions(opts),
+		// Ignored regions are painted out before the alignment because the aligned rows no longer share the
+		// coordinates of the regions.
+		pixelDiff: NewPixelDiff(threshold, append(append([]Option{}, opts...), WithIgnoreRegions())...),
+		maxEdits:  2000,
+	}
+}
+
+// rowPair is an aligned pair of baseline and target rows. A row index of -1 means the row only exists in the other
+// image.
+type rowPair struct {
+	baseline int
+	target   int
+}
+
+func (s *ShiftDiff) Calculate(baseline image.Image, target image.Image) *DiffResult {
+	if s.unchanged(baseline, target) {
+		return &DiffResult{
+			Image:      baseline,
+			DiffAmount: 0.0,
+		}
+	}
+
+	change := dimensionChange(baseline, target)
+	baseline, target = s.fitSizes(baseline, target)
+
+	minX := min(baseline.Bounds().Min.X, target.Bounds().Min.X)
+	maxX := max(baseline.Bounds().Max.X, target.Bounds().Max.X)
+	width := maxX - minX
+
+	baselineRows := s.toRows(baseline, minX, maxX)
+	targetRows := s.toRows(target, minX, maxX)
+
+	pairs := s.alignRows(s.hashRows(baselineRows), s.hashRows(targetRows))
+
+	var matchedCount int
+	for _, pair := range pairs {
+		if pair.baseline >= 0 && pair.target >= 0 {
+			matchedCount++
+		}
+	}
+
+	// Compare the aligned rows as two images of the same height so that PixelDiff applies its threshold and options.
+	matchedBaseline := image.NewRGBA(image.Rect(0, 0, width, matchedCount))
+	matchedTarget := image.NewRGBA(image.Rect(0, 0, width, matchedCount))
+	i := 0
+	for _, pair := range pairs {
+		if pair.baseline >= 0 && pair.target >= 0 {
+			copy(matchedBaseline.Pix[i*matchedBaseline.Stride:], s.row(baselineRows, pair.baseline))
+			copy(matchedTarget.Pix[i*matchedTarget.Stride:], s.row(targetRows, pair.target))
+			i++
+		}
+	}
+	matchedDiff := s.pixelDiff.Calculate(matchedBaseline, matchedTarget)
+	matchedDiffRGBA := image.NewRGBA(matchedDiff.Image.Bounds())
+	draw.Draw(matchedDiffRGBA, matchedDiffRGBA.Bounds(), matchedDiff.Image, matchedDiff.Image.Bounds().Min, draw.Src)
+
+	result := image.NewRGBA(image.Rect(0, 0, width, len(pairs)))
+	var insertedBands []Band
+	var removedBands []Band
+	i = 0
+	for y, pair := range pairs {
+		dst := result.Pix[y*result.Stride : y*result.Stride+width*4]
+		switch {
+		case pair.baseline >= 0 && pair.target >= 0:
+			copy(dst, matchedDiffRGBA.Pix[i*matchedDiffRGBA.Stride:])
+			i++
+		case pair.target >= 0:
+			s.tint(dst, s.row(targetRows, pair.target), color.RGBA{G: 255, A: 255})
+			insertedBands = s.appendBand(insertedBands, pair.target)
+		default:
+			s.tint(dst, s.row(baselineRows, pair.baseline), color.RGBA{R: 255, B: 255, A: 255})
+			removedBands = s.appendBand(removedBands, pair.baseline)
+		}
+	}
+
+	diffAmount := 0.0
+	if len(pairs) > 0 && width > 0 {
+		changedRowCount := float64(len(pairs) - matchedCount)
+		diffAmount = (matchedDiff.DiffAmount*float64(matchedCount) + changedRowCount) / float64(len(pairs))
+	}
+
+	return &DiffResult{
+		Image:           result,
+		DiffAmount:      diffAmount,
+		InsertedBands:   insertedBands,
+		RemovedBands:    removedBands,
+		DimensionChange: change,
+	}
+}
+
+// toRows renders the image onto a white canvas spanning [minX, maxX) so that rows of both images have the same width.
+// Ignored regions are filled with gray on both images so that they neither break the alignment nor count as changes.
+func (s *ShiftDiff) toRows(img image.Image, minX int, maxX int) *image.RGBA {
+	bounds := img.Bounds()
+	offset := image.Point{X: minX, Y: bounds.Min.Y}
+	rows := image.NewRGBA(image.Rect(0, 0, maxX-minX, bounds.Dy()))
+	draw.Draw(rows, rows.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(rows, bounds.Sub(offset), img, bounds.Min, draw.Src)
+	for _, region := range s.ignoreRegions {
+		r := image.Rect(region.X, region.Y, region.X+region.Width, region.Y+region.Height).Sub(offset)
+		draw.Draw(rows, r, &image.Uniform{C: color.Gray{Y: 192}}, image.Point{}, draw.Src)
+	}
+	return rows
+}
+
+func (s *ShiftDiff) row(rows *image.RGBA, y int) []byte {
+	return rows.Pix[y*rows.Stride : y*rows.Stride+rows.Bounds().Dx()*4]
+}
+
+// hashRows hashes each row with the low bits of every channel dropped, which keeps rows with compression noise equal.
+func (s *ShiftDiff) hashRows(rows *image.RGBA) []uint64 {
+	height := rows.Bounds().Dy()
+	hashes := make([]uint64, height)
+	buffer := make([]byte, rows.Bounds().Dx()*4)
+	for y := 0; y < height; y++ {
+		for i, v := range s.row(rows, y) {
+			buffer[i] = v >> 4
+		}
+		h := fnv.New64a()
+		_, _ = h.Write(buffer)
+		hashes[y] = h.Sum64()
+	}
+	return hashes
+}
+
+// alignRows aligns the row hashes with the Myers O(ND) difference algorithm. Rows that are not matched in the same gap
+// between matches are paired by position, leaving only the surplus rows as inserted or removed, so that a row whose
+// content changed in place is still compared pixel by pixel.
+// Reference: E. W. Myers, "An O(ND) Difference Algorithm and Its Variations", 1986
+func (s *ShiftDiff) alignRows(a []uint64, b []uint64) []rowPair {
+	matches := s.matchRows(a, b)
+
+	var pairs []rowPair
+	x, y := 0, 0
+	for _, match := range append(matches, rowPair{baseline: len(a), target: len(b)}) {
+		removedCount := match.baseline - x
+		insertedCount := match.target - y
+		for i := 0; i < min(removedCount, insertedCount); i++ {
+			pairs = append(pairs, rowPair{baseline: x, target: y})
+			x++
+			y++
+		}
+		for ; x < match.baseline; x++ {
+			pairs = append(pairs, rowPair{baseline: x, target: -1})
+		}
+		for ; y < match.target; y++ {
+			pairs = append(pairs, rowPair{baseline: -1, target: y})
+		}
+		if match.baseline < len(a) {
+			pairs = append(pairs, match)
+			x++
+			y++
+		}
+	}
+	return pairs
+}
+
+// matchRows returns the matched rows of the shortest edit script, or only the common prefix and suffix when it needs
+// more than maxEdits edits. Matching the common prefix and suffix first also keeps uniform rows, such as blank
+// background, from being aligned across a change in place.
+func (s *ShiftDiff) matchRows(a []uint64, b []uint64) []rowPair {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var matches []rowPair
+	for i := 0; i < prefix; i++ {
+		matches = append(matches, rowPair{baseline: i, target: i})
+	}
+	for _, match := range s.myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		matches = append(matches, rowPair{baseline: prefix + match.baseline, target: prefix + match.target})
+	}
+	for i := suffix; i > 0; i-- {
+		matches = append(matches, rowPair{baseline: len(a) - i, target: len(b) - i})
+	}
+	return matches
+}
+
+// myers returns the matched rows of the shortest edit script, or nil when it needs more than maxEdits edits.
+func (s *ShiftDiff) myers(a []uint64, b []uint64) []rowPair {
+	n, m := len(a), len(b)
+	limit := min(n+m, s.maxEdits)
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+
+	// trace keeps the furthest reaching x of each diagonal k in [-d, d] after every step d for the backtracking.
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return s.backtrack(trace, n, m, d)
+			}
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+	}
+
+	return nil
+}
+
+func (s *ShiftDiff) backtrack(trace [][]int, n int, m int, d int) []rowPair {
+	var matches []rowPair
+	x, y := n, m
+	for ; d > 0; d-- {
+		previous := trace[d-1]
+		at := func(k int) int {
+			return previous[k+d-1]
+		}
+
+		k := x - y
+		var previousK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			previousK = k + 1
+		} else {
+			previousK = k - 1
+		}
+		previousX := at(previousK)
+		previousY := previousX - previousK
+
+		for x > previousX && y > previousY {
+			x--
+			y--
+			matches = append(matches, rowPair{baseline: x, target: y})
+		}
+		x, y = previousX, previousY
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		matches = append(matches, rowPair{baseline: x, target: y})
+	}
+
+	for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+		matches[i], matches[j] = matches[j], matches[i]
+	}
+	return matches
+}
+
+// tint blends the row halfway toward the color.
+func (s *ShiftDiff) tint(dst []byte, src []byte, c color.RGBA) {
+	for i := 0; i+3 < len(src); i += 4 {
+		dst[i] = uint8((uint16(src[i]) + uint16(c.R)) / 2)
+		dst[i+1] = uint8((uint16(src[i+1]) + uint16(c.G)) / 2)
+		dst[i+2] = uint8((uint16(src[i+2]) + uint16(c.B)) / 2)
+		dst[i+3] = 255
+	}
+}
+
+func (s *ShiftDiff) appendBand(bands []Band, y int) []Band {
+	if len(bands) > 0 {
+		last := &bands[len(bands)-1]
+		if last.Y+last.Height == y {
+			last.Height++
+			return bands
+		}
+	}
+	return append(bands, Band{Y: y, Height: 1})
+}
@@ -0,0 +1,115 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func createStripedTestImage(width, height int, offset int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8((y + offset) * 16), G: uint8((y + offset) / 16 * 16), B: uint8(x), A: 255})
+		}
+	}
+	return img
+}
+
+func TestShiftDiff_Calculate(t *testing.T) {
+	sd := NewShiftDiff(0.1)
+
+	t.Run("NoDifference", func(t *testing.T) {
+		img1 := createStripedTestImage(100, 200, 0)
+		img2 := createStripedTestImage(100, 200, 0)
+
+		result := sd.Calculate(img1, img2)
+
+		if result.DiffAmount != 0.0 {
+			t.Errorf("Expected DiffAmount to be 0.0, got %f", result.DiffAmount)
+		}
+		if len(result.InsertedBands) != 0 || len(result.RemovedBands) != 0 {
+			t.Errorf("Expected no bands, got inserted %v and removed %v", result.InsertedBands, result.RemovedBands)
+		}
+	})
+
+	t.Run("InsertedBand", func(t *testing.T) {
+		baseline := createStripedTestImage(100, 200, 0)
+		target := createStripedTestImage(100, 240, -40)
+		for y := 0; y < 40; y++ {
+			for x := 0; x < 100; x++ {
+				target.Set(x, y, color.RGBA{G: 255, A: 255})
+			}
+		}
+
+		result := sd.Calculate(baseline, target)
+
+		if len(result.InsertedBands) != 1 || result.InsertedBands[0] != (Band{Y: 0, Height: 40}) {
+			t.Errorf("Expected inserted band {0 40}, got %v", result.InsertedBands)
+		}
+		if len(result.RemovedBands) != 0 {
+			t.Errorf("Expected no removed bands, got %v", result.RemovedBands)
+		}
+		if expected := 40.0 / 240.0; result.DiffAmount != expected {
+			t.Errorf("Expected DiffAmount to be %f, got %f", expected, result.DiffAmount)
+		}
+		if pixelResult := NewPixelDiff(0.1).Calculate(baseline, target); pixelResult.DiffAmount <= result.DiffAmount {
+			t.Errorf("Expected pixel DiffAmount %f to exceed shift DiffAmount %f", pixelResult.DiffAmount, result.DiffAmount)
+		}
+	})
+
+	t.Run("RemovedBand", func(t *testing.T) {
+		baseline := createStripedTestImage(100, 200, 0)
+		target := image.NewRGBA(image.Rect(0, 0, 100, 180))
+		for y := 0; y < 180; y++ {
+			sourceY := y
+			if y >= 100 {
+				sourceY = y + 20
+			}
+			for x := 0; x < 100; x++ {
+				target.Set(x, y, baseline.At(x, sourceY))
+			}
+		}
+
+		result := sd.Calculate(baseline, target)
+
+		if len(result.RemovedBands) != 1 || result.RemovedBands[0] != (Band{Y: 100, Height: 20}) {
+			t.Errorf("Expected removed band {100 20}, got %v", result.RemovedBands)
+		}
+		if len(result.InsertedBands) != 0 {
+			t.Errorf("Expected no inserted bands, got %v", result.InsertedBands)
+		}
+		if result.Image.Bounds().Dy() != 200 {
+			t.Errorf("Expected diff image to stack 200 rows, got %d", result.Image.Bounds().Dy())
+		}
+	})
+
+	t.Run("ChangedInPlace", func(t *testing.T) {
+		baseline := createTestImage(100, 100, color.White)
+		target := createTestImage(100, 100, color.White)
+		for y := 0; y < 50; y++ {
+			for x := 0; x < 100; x++ {
+				target.Set(x, y, color.Black)
+			}
+		}
+
+		result := sd.Calculate(baseline, target)
+
+		if result.DiffAmount != 0.5 {
+			t.Errorf("Expected DiffAmount to be 0.5, got %f", result.DiffAmount)
+		}
+		if len(result.InsertedBands) != 0 || len(result.RemovedBands) != 0 {
+			t.Errorf("Expected no bands, got inserted %v and removed %v", result.InsertedBands, result.RemovedBands)
+		}
+	})
+
+	t.Run("SameImageInstance", func(t *testing.T) {
+		img := createTestImage(100, 100, color.White)
+
+		result := sd.Calculate(img, img)
+
+		if result.DiffAmount != 0.0 {
+			t.Errorf("Expected DiffAmount to be 0.0 for same image instance, got %f", result.DiffAmount)
+		}
+	})
+}
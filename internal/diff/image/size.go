@@ -0,0 +1,257 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+	"strings"
+)
+
+// Size mismatch policies decide how images of different sizes are compared.
+const (
+	// SizeMismatchUnion compares the images over the union of their bounds, where the area outside of an image is
+	// white.
+	SizeMismatchUnion = "union"
+	// SizeMismatchCropToCommon compares only the area both images cover.
+	SizeMismatchCropToCommon = "crop-to-common"
+	// SizeMismatchPadWithColor pads both images to the union of their bounds with the pad color.
+	SizeMismatchPadWithColor = "pad-with-color"
+	// SizeMismatchScaleToBaseline scales the target to the size of the baseline.
+	SizeMismatchScaleToBaseline = "scale-to-baseline"
+)
+
+// DimensionChange is the size difference between the baseline and the target.
+type DimensionChange struct {
+	BaselineWidth  int `json:"baselineWidth"`
+	BaselineHeight int `json:"baselineHeight"`
+	TargetWidth    int `json:"targetWidth"`
+	TargetHeight   int `json:"targetHeight"`
+}
+
+// WithSizeMismatch sets the policy for images of different sizes. The pad color is only used by
+// SizeMismatchPadWithColor and defaults to white. PixelDiff, RectangleDiff, SSIMDiff and ShiftDiff support it.
+func WithSizeMismatch(policy string, padColor color.Color) Option {
+	return func(o *options) {
+		o.sizeMismatch = policy
+		o.padColor = padColor
+	}
+}
+
+// ParseHexColor parses a color in the #rrggbb form.
+func ParseHexColor(value string) (color.RGBA, error) {
+	hex, ok := strings.CutPrefix(value, "#")
+	if !ok || len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid color: %s", value)
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color: %s", value)
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 255}, nil
+}
+
+// dimensionChange returns the size difference of the images, or nil when they have the same size.
+func dimensionChange(baseline image.Image, target image.Image) *DimensionChange {
+	baselineBounds := baseline.Bounds()
+	targetBounds := target.Bounds()
+	if baselineBounds.Dx() == targetBounds.Dx() && baselineBounds.Dy() == targetBounds.Dy() {
+		return nil
+	}
+	return &DimensionChange{
+		BaselineWidth:  baselineBounds.Dx(),
+		BaselineHeight: baselineBounds.Dy(),
+		TargetWidth:    targetBounds.Dx(),
+		TargetHeight:   targetBounds.Dy(),
+	}
+}
+
+// fitSizes applies the size mismatch policy and returns the images to compare. Images of the same bounds are returned
+// as they are. Within a memory budget, padded and scaled images are computed on demand instead of being copied.
+func (o *options) fitSizes(baseline image.Image, target image.Image) (image.Image, image.Image) {
+	if baseline.Bounds() == target.Bounds() {
+		return baseline, target
+	}
+
+	switch o.sizeMismatch {
+	case SizeMismatchCropToCommon:
+		common := baseline.Bounds().Intersect(target.Bounds())
+		return crop(baseline, common), crop(target, common)
+	case SizeMismatchPadWithColor:
+		padColor := o.padColor
+		if padColor == nil {
+			padColor = color.White
+		}
+		union := baseline.Bounds().Union(target.Bounds())
+		if o.memoryBudget > 0 {
+			return newPaddedImage(baseline, union, padColor), newPaddedImage(target, union, padColor)
+		}
+		return pad(baseline, union, padColor), pad(target, union, padColor)
+	case SizeMismatchScaleToBaseline:
+		if o.memoryBudget > 0 {
+			return baseline, newScaledImage(target, baseline.Bounds())
+		}
+		return baseline, scale(target, baseline.Bounds())
+	default:
+		return baseline, target
+	}
+}
+
+func crop(img image.Image, bounds image.Rectangle) image.Image {
+	if img.Bounds() == bounds {
+		return img
+	}
+	if subImager, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return subImager.SubImage(bounds)
+	}
+	cropped := image.NewRGBA(bounds)
+	draw.Draw(cropped, bounds, img, bounds.Min, draw.Src)
+	return cropped
+}
+
+func pad(img image.Image, bounds image.Rectangle, c color.Color) image.Image {
+	if img.Bounds() == bounds {
+		return img
+	}
+	padded := image.NewRGBA(bounds)
+	draw.Draw(padded, bounds, &image.Uniform{C: c}, image.Point{}, draw.Src)
+	draw.Draw(padded, img.Bounds(), img, img.Bounds().Min, draw.Src)
+	return padded
+}
+
+// scale resizes the image to the bounds with bilinear interpolation.
+func scale(img image.Image, bounds image.Rectangle) *image.RGBA {
+	src := image.NewRGBA(img.Bounds().Sub(img.Bounds().Min))
+	draw.Draw(src, src.Bounds(), img, img.Bounds().Min, draw.Src)
+	dst := image.NewRGBA(bounds)
+
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	if srcWidth == 0 || srcHeight == 0 {
+		return dst
+	}
+	scaleX := float64(srcWidth) / float64(bounds.Dx())
+	scaleY := float64(srcHeight) / float64(bounds.Dy())
+
+	for y := 0; y < bounds.Dy(); y++ {
+		sy := max((float64(y)+0.5)*scaleY-0.5, 0)
+		y0 := min(int(sy), srcHeight-1)
+		y1 := min(y0+1, srcHeight-1)
+		fy := sy - float64(y0)
+
+		for x := 0; x < bounds.Dx(); x++ {
+			sx := max((float64(x)+0.5)*scaleX-0.5, 0)
+			x0 := min(int(sx), srcWidth-1)
+			x1 := min(x0+1, srcWidth-1)
+			fx := sx - float64(x0)
+
+			offset := dst.PixOffset(bounds.Min.X+x, bounds.Min.Y+y)
+			for c := 0; c < 4; c++ {
+				top := float64(src.Pix[src.PixOffset(x0, y0)+c])*(1-fx) + float64(src.Pix[src.PixOffset(x1, y0)+c])*fx
+				bottom := float64(src.Pix[src.PixOffset(x0, y1)+c])*(1-fx) + float64(src.Pix[src.PixOffset(x1, y1)+c])*fx
+				dst.Pix[offset+c] = uint8(top*(1-fy) + bottom*fy + 0.5)
+			}
+		}
+	}
+	return dst
+}
+
+// paddedImage is an image extended to larger bounds with a color without copying it.
+type paddedImage struct {
+	image.Image
+	bounds image.Rectangle
+	color  color.RGBA
+}
+
+func newPaddedImage(img image.Image, bounds image.Rectangle, c color.Color) image.Image {
+	if img.Bounds() == bounds {
+		return img
+	}
+	return &paddedImage{Image: img, bounds: bounds, color: color.RGBAModel.Convert(c).(color.RGBA)}
+}
+
+func (p *paddedImage) ColorModel() color.Model {
+	return color.RGBAModel
+}
+
+func (p *paddedImage) Bounds() image.Rectangle {
+	return p.bounds
+}
+
+func (p *paddedImage) At(x int, y int) color.Color {
+	return p.RGBAAt(x, y)
+}
+
+func (p *paddedImage) RGBAAt(x int, y int) color.RGBA {
+	point := image.Point{X: x, Y: y}
+	switch {
+	case point.In(p.Image.Bounds()):
+		return rgbaAt(p.Image, x, y)
+	case point.In(p.bounds):
+		return p.color
+	default:
+		return color.RGBA{}
+	}
+}
+
+// scaledImage is an image resized with the bilinear interpolation of scale, computed pixel by pixel on demand.
+type scaledImage struct {
+	src    image.Image
+	bounds image.Rectangle
+	scaleX float64
+	scaleY float64
+}
+
+func newScaledImage(img image.Image, bounds image.Rectangle) *scaledImage {
+	return &scaledImage{
+		src:    img,
+		bounds: bounds,
+		scaleX: float64(img.Bounds().Dx()) / float64(bounds.Dx()),
+		scaleY: float64(img.Bounds().Dy()) / float64(bounds.Dy()),
+	}
+}
+
+func (s *scaledImage) ColorModel() color.Model {
+	return color.RGBAModel
+}
+
+func (s *scaledImage) Bounds() image.Rectangle {
+	return s.bounds
+}
+
+func (s *scaledImage) At(x int, y int) color.Color {
+	return s.RGBAAt(x, y)
+}
+
+func (s *scaledImage) RGBAAt(x int, y int) color.RGBA {
+	srcBounds := s.src.Bounds()
+	srcWidth, srcHeight := srcBounds.Dx(), srcBounds.Dy()
+	if !(image.Point{X: x, Y: y}.In(s.bounds)) || srcWidth == 0 || srcHeight == 0 {
+		return color.RGBA{}
+	}
+
+	sy := max((float64(y-s.bounds.Min.Y)+0.5)*s.scaleY-0.5, 0)
+	y0 := min(int(sy), srcHeight-1)
+	y1 := min(y0+1, srcHeight-1)
+	fy := sy - float64(y0)
+	sx := max((float64(x-s.bounds.Min.X)+0.5)*s.scaleX-0.5, 0)
+	x0 := min(int(sx), srcWidth-1)
+	x1 := min(x0+1, srcWidth-1)
+	fx := sx - float64(x0)
+
+	at := func(x int, y int) [4]float64 {
+		c := rgbaAt(s.src, srcBounds.Min.X+x, srcBounds.Min.Y+y)
+		return [4]float64{float64(c.R), float64(c.G), float64(c.B), float64(c.A)}
+	}
+	topLeft, topRight, bottomLeft, bottomRight := at(x0, y0), at(x1, y0), at(x0, y1), at(x1, y1)
+
+	var channels [4]uint8
+	for c := 0; c < 4; c++ {
+		top := topLeft[c]*(1-fx) + topRight[c]*fx
+		bottom := bottomLeft[c]*(1-fx) + bottomRight[c]*fx
+		channels[c] = uint8(top*(1-fy) + bottom*fy + 0.5)
+	}
+	return color.RGBA{R: channels[0], G: channels[1], B: channels[2], A: channels[3]}
+}
@@ -0,0 +1,98 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestSizeMismatch(t *testing.T) {
+	baseline := createRectTestImage(100, 100, color.White)
+	target := createRectTestImage(100, 130, color.White)
+	for y := 100; y < 130; y++ {
+		for x := 0; x < 100; x++ {
+			target.Set(x, y, color.Black)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		policy         string
+		padColor       color.Color
+		expectedBounds image.Rectangle
+		expectChange   bool
+	}{
+		{name: "CropToCommon", policy: SizeMismatchCropToCommon, expectedBounds: image.Rect(0, 0, 100, 100)},
+		{name: "PadWithColor", policy: SizeMismatchPadWithColor, expectedBounds: image.Rect(0, 0, 100, 130), expectChange: true},
+		{name: "PadWithMatchingColor", policy: SizeMismatchPadWithColor, padColor: color.Black, expectedBounds: image.Rect(0, 0, 100, 130)},
+		{name: "ScaleToBaseline", policy: SizeMismatchScaleToBaseline, expectedBounds: image.Rect(0, 0, 100, 100), expectChange: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewPixelDiff(0.1, WithSizeMismatch(tt.policy, tt.padColor)).Calculate(baseline, target)
+
+			if result.Image.Bounds() != tt.expectedBounds {
+				t.Errorf("Expected bounds %v, got %v", tt.expectedBounds, result.Image.Bounds())
+			}
+			if changed := result.DiffAmount > 0; changed != tt.expectChange {
+				t.Errorf("Expected change %v, got DiffAmount %f", tt.expectChange, result.DiffAmount)
+			}
+
+			expected := DimensionChange{BaselineWidth: 100, BaselineHeight: 100, TargetWidth: 100, TargetHeight: 130}
+			if result.DimensionChange == nil || *result.DimensionChange != expected {
+				t.Errorf("Expected dimension change %v, got %v", expected, result.DimensionChange)
+			}
+		})
+	}
+
+	t.Run("MemoryBudget", func(t *testing.T) {
+		_, noisyTarget := createNoisyTestImages(100, 130)
+		for _, policy := range []string{SizeMismatchPadWithColor, SizeMismatchScaleToBaseline} {
+			expected := NewPixelDiff(0.1, WithSizeMismatch(policy, nil)).Calculate(baseline, noisyTarget)
+			actual := NewPixelDiff(0.1, WithSizeMismatch(policy, nil), WithMemoryBudget(100*4*7)).Calculate(baseline, noisyTarget)
+
+			if actual.DiffAmount != expected.DiffAmount {
+				t.Errorf("Expected DiffAmount %f with %s, got %f", expected.DiffAmount, policy, actual.DiffAmount)
+			}
+			assertSameImage(t, expected.Image, actual.Image)
+		}
+	})
+
+	t.Run("SameSize", func(t *testing.T) {
+		result := NewRectangleDiff(WithSizeMismatch(SizeMismatchCropToCommon, nil)).Calculate(baseline, createRectTestImage(100, 100, color.Black))
+
+		if result.DimensionChange != nil {
+			t.Errorf("Expected no dimension change, got %v", result.DimensionChange)
+		}
+	})
+}
+
+func TestScale(t *testing.T) {
+	img := createRectTestImage(10, 20, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+
+	scaled := scale(img, image.Rect(0, 0, 5, 5))
+
+	if scaled.Bounds() != image.Rect(0, 0, 5, 5) {
+		t.Fatalf("Expected 5x5, got %v", scaled.Bounds())
+	}
+	if c := scaled.RGBAAt(2, 2); c != (color.RGBA{R: 200, G: 100, B: 50, A: 255}) {
+		t.Errorf("Expected the color to be kept, got %v", c)
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	c, err := ParseHexColor("#ff8000")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c != (color.RGBA{R: 255, G: 128, A: 255}) {
+		t.Errorf("Expected orange, got %v", c)
+	}
+
+	for _, value := range []string{"ff8000", "#ff80", "#gg8000"} {
+		if _, err := ParseHexColor(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
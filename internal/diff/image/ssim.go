@@ -0,0 +1,247 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"runtime"
+	"sync"
+)
+
+const (
+	// ssimC1 and ssimC2 stabilize the division for flat windows, computed as (0.01*255)^2 and (0.03*255)^2.
+	ssimC1 = 6.5025
+	ssimC2 = 58.5225
+)
+
+// SSIMDiff compares images by the windowed structural similarity of their luminance, which follows human perception
+// more closely than per-pixel comparison.
+// Reference: Z. Wang et al., "Image Quality Assessment: From Error Visibility to Structural Similarity", 2004
+type SSIMDiff struct {
+	options
+	windowRadius int
+}
+
+func NewSSIMDiff(opts ...Option) *SSIMDiff {
+	return &SSIMDiff{
+		options:      newOptions(opts),
+		windowRadius: 3,
+	}
+}
+
+// Calculate renders a heatmap where dissimilar areas are red over a faded target and reports 1 - meanSSIM as the diff
+// amount.
+func (s *SSIMDiff) Calculate(baseline image.Image, target image.Image) *DiffResult {
+	if s.unchanged(baseline, target) {
+		return &DiffResult{
+			Image:      baseline,
+			DiffAmount: 0.0,
+		}
+	}
+
+	change := dimensionChange(baseline, target)
+	baseline, target = s.fitSizes(baseline, target)
+
+	bounds := baseline.Bounds().Union(target.Bounds())
+	baselineGray := s.toGray(baseline, bounds)
+	targetGray := s.toGray(target, bounds)
+	heatmap := image.NewRGBA(bounds)
+
+	totalPixelCount := bounds.Dx() * bounds.Dy()
+	if totalPixelCount == 0 {
+		return &DiffResult{
+			Image:           heatmap,
+			DiffAmount:      0.0,
+			DimensionChange: change,
+		}
+	}
+
+	// Use GOMAXPROCS instead of runtime.NumCPU() to consider cgroup.
+	// https://tip.golang.org/doc/go1.25#container-aware-gomaxprocs
+	numWorkers := runtime.GOMAXPROCS(0)
+
+	height := bounds.Max.Y - bounds.Min.Y
+	rowsPerWorker := height / numWorkers
+
+	ssimSums := make([]float64, numWorkers)
+
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		startY := bounds.Min.Y + i*rowsPerWorker
+		endY := startY + rowsPerWorker
+		if i == numWorkers-1 {
+			endY = bounds.Max.Y
+		}
+
+		go func(i int, startY int, endY int) {
+			defer wg.Done()
+			ssimSums[i] = s.process(baselineGray, targetGray, heatmap, startY, endY)
+		}(i, startY, endY)
+	}
+	wg.Wait()
+
+	var ssimSum float64
+	for _, sum := range ssimSums {
+		ssimSum += sum
+	}
+
+	diffAmount := 1.0 - ssimSum/float64(totalPixelCount)
+	if diffAmount < 0.0 {
+		diffAmount = 0.0
+	} else if diffAmount > 1.0 {
+		diffAmount = 1.0
+	}
+
+	return &DiffResult{
+		Image:           heatmap,
+		DiffAmount:      diffAmount,
+		DimensionChange: change,
+	}
+}
+
+// toGray converts the image to luminance over bounds. Areas outside the image are black so that size differences
+// count as dissimilar.
+func (s *SSIMDiff) toGray(img image.Image, bounds image.Rectangle) *image.Gray {
+	gray := image.NewGray(bounds)
+	draw.Draw(gray, img.Bounds(), img, img.Bounds().Min, draw.Src)
+	return gray
+}
+
+// windowSums holds the running sums needed for the mean, variance and covariance of a window.
+type windowSums struct {
+	x  float64
+	y  float64
+	xx float64
+	yy float64
+	xy float64
+}
+
+func (w *windowSums) add(o windowSums) {
+	w.x += o.x
+	w.y += o.y
+	w.xx += o.xx
+	w.yy += o.yy
+	w.xy += o.xy
+}
+
+func (w *windowSums) sub(o windowSums) {
+	w.x -= o.x
+	w.y -= o.y
+	w.xx -= o.xx
+	w.yy -= o.yy
+	w.xy -= o.xy
+}
+
+// process computes the SSIM of the window centered on each pixel in rows [startY, endY), writes the heatmap and returns
+// the sum of the SSIM values. Windows are clipped at the image edges and slide in O(1) per pixel by keeping per-column
+// sums over the window rows.
+func (s *SSIMDiff) process(baseline *image.Gray, target *image.Gray, heatmap *image.RGBA, startY int, endY int) float64 {
+	if startY >= endY {
+		return 0.0
+	}
+
+	bounds := heatmap.Bounds()
+	width := bounds.Dx()
+	radius := s.windowRadius
+
+	columns := make([]windowSums, width)
+	addRow := func(y int, sign float64) {
+		baselineRow := baseline.Pix[baseline.PixOffset(bounds.Min.X, y):]
+		targetRow := target.Pix[target.PixOffset(bounds.Min.X, y):]
+		for x := 0; x < width; x++ {
+			bv := float64(baselineRow[x])
+			tv := float64(targetRow[x])
+			columns[x].add(windowSums{
+				x:  sign * bv,
+				y:  sign * tv,
+				xx: sign * bv * bv,
+				yy: sign * tv * tv,
+				xy: sign * bv * tv,
+			})
+		}
+	}
+
+	top := max(startY-radius, bounds.Min.Y)
+	bottom := min(startY+radius, bounds.Max.Y-1)
+	for y := top; y <= bottom; y++ {
+		addRow(y, 1.0)
+	}
+
+	var ssimSum float64
+	for y := startY; y < endY; y++ {
+		if y > startY {
+			if y+radius < bounds.Max.Y {
+				addRow(y+radius, 1.0)
+				bottom = y + radius
+			}
+			if y-radius-1 >= bounds.Min.Y {
+				addRow(y-radius-1, -1.0)
+				top = y - radius
+			}
+		}
+		rowCount := bottom - top + 1
+
+		var window windowSums
+		left := 0
+		right := min(radius, width-1)
+		for x := left; x <= right; x++ {
+			window.add(columns[x])
+		}
+
+		targetRow := target.Pix[target.PixOffset(bounds.Min.X, y):]
+		heatmapRow := heatmap.Pix[heatmap.PixOffset(bounds.Min.X, y):]
+		for x := 0; x < width; x++ {
+			if x > 0 {
+				if x+radius < width {
+					window.add(columns[x+radius])
+					right = x + radius
+				}
+				if x-radius-1 >= 0 {
+					window.sub(columns[x-radius-1])
+					left = x - radius
+				}
+			}
+
+			ssim := s.ssim(window, float64(rowCount*(right-left+1)))
+			ssimSum += ssim
+
+			c := s.heatColor(targetRow[x], ssim)
+			heatmapRow[x*4] = c.R
+			heatmapRow[x*4+1] = c.G
+			heatmapRow[x*4+2] = c.B
+			heatmapRow[x*4+3] = c.A
+		}
+	}
+
+	return ssimSum
+}
+
+func (s *SSIMDiff) ssim(window windowSums, n float64) float64 {
+	meanX := window.x / n
+	meanY := window.y / n
+	varianceX := window.xx/n - meanX*meanX
+	varianceY := window.yy/n - meanY*meanY
+	covariance := window.xy/n - meanX*meanY
+
+	return ((2*meanX*meanY + ssimC1) * (2*covariance + ssimC2)) /
+		((meanX*meanX + meanY*meanY + ssimC1) * (varianceX + varianceY + ssimC2))
+}
+
+// heatColor blends the faded target luminance toward red as the similarity decreases.
+func (s *SSIMDiff) heatColor(luminance uint8, ssim float64) color.RGBA {
+	intensity := 1.0 - ssim
+	if intensity < 0.0 {
+		intensity = 0.0
+	} else if intensity > 1.0 {
+		intensity = 1.0
+	}
+
+	faded := 255.0 - (255.0-float64(luminance))*0.25
+	return color.RGBA{
+		R: uint8(faded + (255.0-faded)*intensity),
+		G: uint8(faded * (1.0 - intensity)),
+		B: uint8(faded * (1.0 - intensity)),
+		A: 255,
+	}
+}
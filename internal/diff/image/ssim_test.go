@@ -0,0 +1,87 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestSSIMDiff_Calculate(t *testing.T) {
+	sd := NewSSIMDiff()
+
+	t.Run("NoDifference", func(t *testing.T) {
+		img1 := createTestImage(100, 100, color.White)
+		img2 := createTestImage(100, 100, color.White)
+
+		result := sd.Calculate(img1, img2)
+
+		if result.DiffAmount > 1e-9 {
+			t.Errorf("Expected DiffAmount to be 0.0, got %f", result.DiffAmount)
+		}
+	})
+
+	t.Run("CompleteDifference", func(t *testing.T) {
+		img1 := createTestImage(100, 100, color.White)
+		img2 := createTestImage(100, 100, color.Black)
+
+		result := sd.Calculate(img1, img2)
+
+		if result.DiffAmount < 0.99 {
+			t.Errorf("Expected DiffAmount to be close to 1.0, got %f", result.DiffAmount)
+		}
+
+		r, g, b, _ := result.Image.At(50, 50).RGBA()
+		if r>>8 < 250 || g>>8 > 5 || b>>8 > 5 {
+			t.Errorf("Expected dissimilar pixel to be red, got (%d, %d, %d)", r>>8, g>>8, b>>8)
+		}
+	})
+
+	t.Run("Shift", func(t *testing.T) {
+		img1 := createTestImage(200, 200, color.White)
+		img2 := createTestImage(200, 200, color.White)
+
+		for y := 20; y < 180; y++ {
+			for x := 20; x < 180; x++ {
+				img1.Set(x, y, color.Black)
+				img2.Set(x+1, y, color.Black)
+			}
+		}
+
+		ssimResult := sd.Calculate(img1, img2)
+		pixelResult := NewPixelDiff(0.1).Calculate(img1, img2)
+
+		if ssimResult.DiffAmount >= 0.1 {
+			t.Errorf("Expected DiffAmount of 1px shift to be small, got %f", ssimResult.DiffAmount)
+		}
+		if ssimResult.DiffAmount == 0.0 {
+			t.Errorf("Expected DiffAmount of 1px shift to be greater than 0")
+		}
+		if pixelResult.DiffAmount == 0.0 {
+			t.Errorf("Expected pixel DiffAmount of 1px shift to be greater than 0")
+		}
+	})
+
+	t.Run("DifferentSizes", func(t *testing.T) {
+		img1 := createTestImage(100, 100, color.White)
+		img2 := createTestImage(100, 50, color.White)
+
+		result := sd.Calculate(img1, img2)
+
+		if !result.Image.Bounds().Eq(image.Rect(0, 0, 100, 100)) {
+			t.Errorf("Expected heatmap to cover both images, got %v", result.Image.Bounds())
+		}
+		if result.DiffAmount < 0.4 {
+			t.Errorf("Expected missing half to be dissimilar, got %f", result.DiffAmount)
+		}
+	})
+
+	t.Run("SameImageInstance", func(t *testing.T) {
+		img := createTestImage(100, 100, color.White)
+
+		result := sd.Calculate(img, img)
+
+		if result.DiffAmount != 0.0 {
+			t.Errorf("Expected DiffAmount to be 0.0 for same image instance, got %f", result.DiffAmount)
+		}
+	})
+}
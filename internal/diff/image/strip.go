@@ -0,0 +1,179 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"sync"
+)
+
+// stripHeight returns the number of rows compared at once when rows of rowBytes bytes exceed the memory budget in
+// total, or 0 when the whole image fits in the budget.
+func (o *options) stripHeight(rowBytes int, height int) int {
+	if o.memoryBudget <= 0 || rowBytes*height <= o.memoryBudget {
+		return 0
+	}
+	return max(o.memoryBudget/max(rowBytes, 1), 1)
+}
+
+// stripImage is an image rendered on demand one horizontal strip at a time, so that only a single strip is held in
+// memory. Encoders and draw.Draw read images row by row, which renders every strip only once.
+type stripImage struct {
+	mu      sync.Mutex
+	bounds  image.Rectangle
+	height  int
+	buffer  []uint8
+	current *image.RGBA
+	render  func(strip *image.RGBA)
+}
+
+func newStripImage(bounds image.Rectangle, height int, render func(strip *image.RGBA)) *stripImage {
+	return &stripImage{
+		bounds: bounds,
+		height: height,
+		render: render,
+	}
+}
+
+func (s *stripImage) ColorModel() color.Model {
+	return color.RGBAModel
+}
+
+func (s *stripImage) Bounds() image.Rectangle {
+	return s.bounds
+}
+
+func (s *stripImage) At(x int, y int) color.Color {
+	return s.RGBAAt(x, y)
+}
+
+func (s *stripImage) RGBAAt(x int, y int) color.RGBA {
+	if !(image.Point{X: x, Y: y}.In(s.bounds)) {
+		return color.RGBA{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current == nil || y < s.current.Rect.Min.Y || y >= s.current.Rect.Max.Y {
+		s.current = s.strip(y)
+		s.render(s.current)
+	}
+	return s.current.RGBAAt(x, y)
+}
+
+// strip returns the buffer viewed as the strip containing the row y without rendering it. The view shares the buffer
+// with the rendered strip, so it must not be used while the image is read.
+func (s *stripImage) strip(y int) *image.RGBA {
+	top := s.bounds.Min.Y + (y-s.bounds.Min.Y)/s.height*s.height
+	rect := image.Rect(s.bounds.Min.X, top, s.bounds.Max.X, min(top+s.height, s.bounds.Max.Y))
+	if s.buffer == nil {
+		s.buffer = make([]uint8, s.bounds.Dx()*4*s.height)
+	}
+	s.current = nil
+	return &image.RGBA{
+		Pix:    s.buffer[:rect.Dx()*4*rect.Dy()],
+		Stride: rect.Dx() * 4,
+		Rect:   rect,
+	}
+}
+
+// componentLabeler finds the bounding boxes of 8-connected components of changed pixels row by row with union-find,
+// keeping only the labels of the previous row instead of a visited map of the whole image. Labels are created in raster
+// order, so the smallest label of a component is the one of its first pixel, which is the order in which
+// findBoundingBox discovers the components.
+type componentLabeler struct {
+	// previous and current hold the label+1 of each pixel of the rows, or 0 for unchanged pixels.
+	previous []int
+	current  []int
+	parent   []int
+	boxes    []image.Rectangle
+}
+
+func newComponentLabeler(width int) *componentLabeler {
+	return &componentLabeler{
+		previous: make([]int, width),
+		current:  make([]int, width),
+	}
+}
+
+// addRow labels the changed pixels of the row y, which must follow the previously added row.
+func (l *componentLabeler) addRow(row []bool, y int) {
+	width := len(l.current)
+	for x := 0; x < width; x++ {
+		if x >= len(row) || !row[x] {
+			l.current[x] = 0
+			continue
+		}
+
+		label := -1
+		neighbors := [4]int{0, l.previous[x], 0, 0}
+		if x > 0 {
+			neighbors[0] = l.current[x-1]
+			neighbors[2] = l.previous[x-1]
+		}
+		if x+1 < width {
+			neighbors[3] = l.previous[x+1]
+		}
+		for _, neighbor := range neighbors {
+			if neighbor == 0 {
+				continue
+			}
+			if label < 0 {
+				label = l.find(neighbor - 1)
+			} else {
+				label = l.union(label, neighbor-1)
+			}
+		}
+
+		pixel := image.Rect(x, y, x+1, y+1)
+		if label < 0 {
+			label = len(l.parent)
+			l.parent = append(l.parent, label)
+			l.boxes = append(l.boxes, pixel)
+		} else {
+			l.boxes[label] = l.boxes[label].Union(pixel)
+		}
+		l.current[x] = label + 1
+	}
+	l.previous, l.current = l.current, l.previous
+}
+
+func (l *componentLabeler) find(label int) int {
+	for l.parent[label] != label {
+		l.parent[label] = l.parent[l.parent[label]]
+		label = l.parent[label]
+	}
+	return label
+}
+
+// union merges the components of the labels into the one with the smaller label and returns it.
+func (l *componentLabeler) union(a int, b int) int {
+	a, b = l.find(a), l.find(b)
+	if a == b {
+		return a
+	}
+	if b < a {
+		a, b = b, a
+	}
+	l.parent[b] = a
+	l.boxes[a] = l.boxes[a].Union(l.boxes[b])
+	return a
+}
+
+// rectangles returns the bounding boxes of the components in the order of their first pixels.
+func (l *componentLabeler) rectangles(offsetX int, offsetY int) []Rectangle {
+	var rectangles []Rectangle
+	for label := range l.parent {
+		if l.find(label) != label {
+			continue
+		}
+		box := l.boxes[label]
+		rectangles = append(rectangles, Rectangle{
+			X:      box.Min.X + offsetX,
+			Y:      box.Min.Y + offsetY,
+			Width:  box.Dx(),
+			Height: box.Dy(),
+		})
+	}
+	return rectangles
+}
@@ -0,0 +1,148 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func createNoisyTestImages(width int, height int) (*image.RGBA, *image.RGBA) {
+	random := rand.New(rand.NewSource(1))
+	baseline := createRectTestImage(width, height, color.White)
+	target := createRectTestImage(width, height, color.White)
+
+	// Blocks and scattered pixels make components that span several strips, merge and get dropped as noise.
+	for i := 0; i < 20; i++ {
+		x, y := random.Intn(width), random.Intn(height)
+		w, h := 1+random.Intn(12), 1+random.Intn(40)
+		for dy := 0; dy < h && y+dy < height; dy++ {
+			for dx := 0; dx < w && x+dx < width; dx++ {
+				target.Set(x+dx, y+dy, color.Black)
+			}
+		}
+	}
+	for i := 0; i < 300; i++ {
+		baseline.Set(random.Intn(width), random.Intn(height), color.RGBA{R: uint8(random.Intn(256)), A: 255})
+	}
+	return baseline, target
+}
+
+func assertSameImage(t *testing.T, expected image.Image, actual image.Image) {
+	t.Helper()
+
+	if expected.Bounds() != actual.Bounds() {
+		t.Fatalf("Expected bounds %v, got %v", expected.Bounds(), actual.Bounds())
+	}
+	bounds := expected.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if e, a := color.RGBAModel.Convert(expected.At(x, y)), color.RGBAModel.Convert(actual.At(x, y)); e != a {
+				t.Fatalf("Expected %v at (%d, %d), got %v", e, x, y, a)
+			}
+		}
+	}
+}
+
+func TestPixelDiff_MemoryBudget(t *testing.T) {
+	baseline, target := createNoisyTestImages(160, 400)
+	opts := []Option{WithAntiAliasingDetection(true), WithIgnoreRegions(Rectangle{X: 10, Y: 20, Width: 30, Height: 15})}
+
+	expected := NewPixelDiff(0.1, opts...).Calculate(baseline, target)
+	actual := NewPixelDiff(0.1, append(opts, WithMemoryBudget(160*4*7))...).Calculate(baseline, target)
+
+	if _, ok := actual.Image.(*stripImage); !ok {
+		t.Fatalf("Expected the diff image to be rendered in strips, got %T", actual.Image)
+	}
+	if actual.DiffAmount != expected.DiffAmount {
+		t.Errorf("Expected DiffAmount %f, got %f", expected.DiffAmount, actual.DiffAmount)
+	}
+	assertSameImage(t, expected.Image, actual.Image)
+}
+
+func TestRectangleDiff_MemoryBudget(t *testing.T) {
+	baseline, target := createNoisyTestImages(160, 400)
+	opts := []Option{WithIgnoreRegions(Rectangle{X: 10, Y: 20, Width: 30, Height: 15})}
+
+	expected := NewRectangleDiff(opts...).Calculate(baseline, target)
+	if len(expected.Regions) < 2 {
+		t.Fatalf("Expected several regions, got %d", len(expected.Regions))
+	}
+
+	for _, stripHeight := range []int{1, 7, 50} {
+		actual := NewRectangleDiff(append(opts, WithMemoryBudget(160*6*stripHeight))...).Calculate(baseline, target)
+
+		if _, ok := actual.Image.(*stripImage); !ok {
+			t.Fatalf("Expected the diff image to be rendered in strips, got %T", actual.Image)
+		}
+		if actual.DiffAmount != expected.DiffAmount {
+			t.Errorf("Expected DiffAmount %f with %d rows per strip, got %f", expected.DiffAmount, stripHeight, actual.DiffAmount)
+		}
+		if !reflect.DeepEqual(actual.Regions, expected.Regions) {
+			t.Errorf("Expected regions %v with %d rows per strip, got %v", expected.Regions, stripHeight, actual.Regions)
+		}
+		assertSameImage(t, expected.Image, actual.Image)
+	}
+}
+
+func TestMemoryBudget_Fits(t *testing.T) {
+	baseline, target := createNoisyTestImages(160, 400)
+
+	result := NewPixelDiff(0.1, WithMemoryBudget(160*4*400)).Calculate(baseline, target)
+
+	if _, ok := result.Image.(*image.RGBA); !ok {
+		t.Errorf("Expected a full-size diff image within the budget, got %T", result.Image)
+	}
+}
+
+// allocatedBytes returns the bytes allocated on the heap while f runs.
+func allocatedBytes(f func()) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	f()
+	runtime.ReadMemStats(&after)
+	return after.TotalAlloc - before.TotalAlloc
+}
+
+func TestMemoryBudget_Allocations(t *testing.T) {
+	const width, height, budget = 400, 2000, 400 * 4 * 20
+	baseline, target := createNoisyTestImages(width, height)
+	taller := createRectTestImage(width, height+100, color.White)
+	draw.Draw(taller, baseline.Bounds(), target, image.Point{}, draw.Src)
+
+	tests := []struct {
+		name   string
+		target image.Image
+		opts   []Option
+	}{
+		{name: "SameSize", target: target},
+		{name: "PadWithColor", target: taller, opts: []Option{WithSizeMismatch(SizeMismatchPadWithColor, nil)}},
+		{name: "ScaleToBaseline", target: taller, opts: []Option{WithSizeMismatch(SizeMismatchScaleToBaseline, nil)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The diff image is read through RGBAAt as At boxes every color on the heap.
+			allocated := allocatedBytes(func() {
+				result := NewPixelDiff(0.1, append(tt.opts, WithMemoryBudget(budget))...).Calculate(baseline, tt.target)
+				strips := result.Image.(*stripImage)
+				bounds := strips.Bounds()
+				for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+					for x := bounds.Min.X; x < bounds.Max.X; x++ {
+						strips.RGBAAt(x, y)
+					}
+				}
+			})
+
+			// The comparison holds a strip of the diff image and a strip of changed pixels besides bookkeeping, far
+			// from the 3.2 MB of a single full-size copy.
+			if limit := uint64(4 * budget); allocated > limit {
+				t.Errorf("Expected at most %d bytes allocated within a budget of %d, got %d", limit, budget, allocated)
+			}
+		})
+	}
+}
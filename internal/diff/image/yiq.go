@@ -0,0 +1,37 @@
+package image
+
+// maxYIQDelta is the largest possible yiqDelta, between black and white.
+const maxYIQDelta = 35215.0
+
+// WithPerceptualColorDistance additionally flags pixels whose color changed without getting brighter or darker beyond
+// the threshold, such as a red button turning green, by their distance in the YIQ color space. The tolerance is the
+// distance from 0 to 1 relative to the distance between black and white, as the threshold of pixelmatch, below which
+// colors are considered equal. Such pixels are counted as differences and rendered in Palette.Hue.
+// Only PixelDiff supports it.
+// Reference: Y. Kotsarenko and F. Ramos, "Measuring perceived color difference using YIQ NTSC transmission color space in
+// mobile applications", 2010
+func WithPerceptualColorDistance(tolerance float64) Option {
+	return func(o *options) {
+		o.perceptualColorDistance = true
+		o.colorTolerance = tolerance
+	}
+}
+
+// yiqDelta returns the squared perceptual distance between the colors, weighting the luma and chroma channels by how
+// much they contribute to the perceived difference.
+func yiqDelta(r1 uint8, g1 uint8, b1 uint8, r2 uint8, g2 uint8, b2 uint8) float64 {
+	y1, i1, q1 := rgbToYIQ(r1, g1, b1)
+	y2, i2, q2 := rgbToYIQ(r2, g2, b2)
+	dy := y1 - y2
+	di := i1 - i2
+	dq := q1 - q2
+	return 0.5053*dy*dy + 0.299*di*di + 0.1957*dq*dq
+}
+
+func rgbToYIQ(r uint8, g uint8, b uint8) (float64, float64, float64) {
+	fr, fg, fb := float64(r), float64(g), float64(b)
+	y := fr*0.29889531 + fg*0.58662247 + fb*0.11448223
+	i := fr*0.59597799 - fg*0.27417610 - fb*0.32180189
+	q := fr*0.21147017 - fg*0.52261711 + fb*0.31114694
+	return y, i, q
+}
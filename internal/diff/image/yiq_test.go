@@ -0,0 +1,49 @@
+package image
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestPixelDiff_PerceptualColorDistance(t *testing.T) {
+	baseline := createRectTestImage(10, 10, color.White)
+	target := createRectTestImage(10, 10, color.White)
+	for x := 0; x < 5; x++ {
+		baseline.Set(x, 0, color.RGBA{R: 255, A: 255})
+		target.Set(x, 0, color.RGBA{G: 255, A: 255})
+	}
+	target.Set(5, 5, color.Black)
+
+	t.Run("Brightness", func(t *testing.T) {
+		result := NewPixelDiff(0.1).Calculate(baseline, target)
+
+		if result.DiffAmount != 0.01 {
+			t.Errorf("Expected only the darker pixel to be counted, got DiffAmount %f", result.DiffAmount)
+		}
+	})
+
+	t.Run("Perceptual", func(t *testing.T) {
+		palette := DefaultPalette()
+		result := NewPixelDiff(0.1, WithPerceptualColorDistance(0.1)).Calculate(baseline, target)
+
+		if result.DiffAmount != 0.06 {
+			t.Errorf("Expected the hue changes to be counted, got DiffAmount %f", result.DiffAmount)
+		}
+		if c := color.RGBAModel.Convert(result.Image.At(0, 0)); c != palette.Hue {
+			t.Errorf("Expected the hue change in %v, got %v", palette.Hue, c)
+		}
+		if c := color.RGBAModel.Convert(result.Image.At(5, 5)); c != palette.Removed {
+			t.Errorf("Expected the darker pixel in %v, got %v", palette.Removed, c)
+		}
+	})
+
+	t.Run("Tolerance", func(t *testing.T) {
+		slightlyRed := createRectTestImage(10, 10, color.RGBA{R: 255, G: 250, B: 250, A: 255})
+
+		result := NewPixelDiff(0.1, WithPerceptualColorDistance(0.1)).Calculate(createRectTestImage(10, 10, color.White), slightlyRed)
+
+		if result.DiffAmount != 0 {
+			t.Errorf("Expected changes within the tolerance to be ignored, got DiffAmount %f", result.DiffAmount)
+		}
+	})
+}
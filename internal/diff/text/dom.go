@@ -3,6 +3,9 @@ package text
 import (
 	"bytes"
 	"fmt"
+	"hash/fnv"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
 
@@ -13,24 +16,48 @@ const (
 	symbolAdded     = "[+]"
 	symbolRemoved   = "[-]"
 	symbolModified  = "[~]"
+	symbolMoved     = "[>]"
 	symbolUnchanged = "[ ]"
 	indentSize      = 2
 )
 
+// Boundaries emitted by the capturer when flattening shadow roots and frames into a single document.
+const (
+	shadowRootAttribute = "shadowrootmode"
+	frameAttribute      = "data-snapshot-frame"
+	shadowRootSegment   = "#shadow-root"
+	frameSegment        = "#document"
+)
+
 type DOMDiff struct {
 	parser     *htmlParser
 	builder    *treeBuilder
 	comparator *nodeComparator
 	formatter  *diffFormatter
+	ignore     *Ignore
 }
 
-func NewDOMDiff() *DOMDiff {
-	return &DOMDiff{
+type DOMDiffOption func(*DOMDiff)
+
+func NewDOMDiff(opts ...DOMDiffOption) *DOMDiff {
+	d := &DOMDiff{
 		parser:     &htmlParser{},
 		builder:    &treeBuilder{},
 		comparator: &nodeComparator{},
 		formatter:  &diffFormatter{},
 	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// WithDOMIgnore removes the ignored elements and attributes from the trees before they are compared, along with the
+// nodes whose lines in the output match the ignored lines.
+func WithDOMIgnore(ignore *Ignore) DOMDiffOption {
+	return func(d *DOMDiff) {
+		d.ignore = ignore
+	}
 }
 
 func (d *DOMDiff) Calculate(baseline []byte, target []byte) (*DiffResult, error) {
@@ -44,8 +71,22 @@ func (d *DOMDiff) Calculate(baseline []byte, target []byte) (*DiffResult, error)
 		return nil, fmt.Errorf("failed to parse target HTML: %w", err)
 	}
 
+	var rules *ignoreRules
+	if d.ignore != nil {
+		rules, err = d.ignore.compile()
+		if err != nil {
+			return nil, err
+		}
+		rules.prune(baselineDoc)
+		rules.prune(targetDoc)
+	}
+
 	baselineTree := d.builder.buildTree(baselineDoc)
 	targetTree := d.builder.buildTree(targetDoc)
+	if rules != nil && len(rules.lines) > 0 {
+		d.dropIgnoredNodes(baselineTree, rules)
+		d.dropIgnoredNodes(targetTree, rules)
+	}
 
 	comparison := d.comparator.compare(baselineTree, targetTree)
 	formattedDiff := d.formatter.format(comparison)
@@ -53,9 +94,19 @@ func (d *DOMDiff) Calculate(baseline []byte, target []byte) (*DiffResult, error)
 	return &DiffResult{
 		Diff:       formattedDiff,
 		DiffAmount: comparison.diffRatio(),
+		Changes:    d.formatter.changes(comparison),
 	}, nil
 }
 
+func (d *DOMDiff) dropIgnoredNodes(node *treeNode, rules *ignoreRules) {
+	node.children = slices.DeleteFunc(node.children, func(child *treeNode) bool {
+		return rules.ignoresLine(d.formatter.formatNode(child))
+	})
+	for _, child := range node.children {
+		d.dropIgnoredNodes(child, rules)
+	}
+}
+
 type treeNode struct {
 	path     string
 	depth    int
@@ -69,7 +120,41 @@ type treeNode struct {
 type htmlParser struct{}
 
 func (p *htmlParser) parse(content []byte) (*html.Node, error) {
-	return html.Parse(bytes.NewReader(content))
+	doc, err := html.Parse(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if err := p.parseFrames(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// parseFrames replaces the escaped document of each inlined frame with its nodes, parsed as a whole document so that
+// its doctype, html, head and body are kept.
+func (p *htmlParser) parseFrames(n *html.Node) error {
+	if document, ok := frameDocument(n); ok {
+		doc, err := p.parse([]byte(document))
+		if err != nil {
+			return fmt.Errorf("failed to parse frame document: %w", err)
+		}
+		for n.FirstChild != nil {
+			n.RemoveChild(n.FirstChild)
+		}
+		for doc.FirstChild != nil {
+			child := doc.FirstChild
+			doc.RemoveChild(child)
+			n.AppendChild(child)
+		}
+		return nil
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if err := p.parseFrames(c); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type treeBuilder struct{}
@@ -105,8 +190,17 @@ func (b *treeBuilder) createTreeNode(n *html.Node, path string, depth int) *tree
 
 	switch n.Type {
 	case html.ElementNode:
+		if mode, ok := shadowRootMode(n); ok {
+			node.tag = shadowRootSegment
+			node.attrs = map[string]string{"mode": mode}
+			return node
+		}
 		node.tag = n.Data
 		node.attrs = b.extractAttributes(n)
+		if isFrameDocument(n) {
+			node.tag = "iframe"
+			delete(node.attrs, frameAttribute)
+		}
 		return node
 	case html.TextNode:
 		text := strings.TrimSpace(n.Data)
@@ -134,6 +228,10 @@ func (b *treeBuilder) shouldAddNode(n *html.Node, node *treeNode) bool {
 }
 
 func (b *treeBuilder) processChildren(n *html.Node, parent *treeNode, path string, depth int) {
+	if isFrameDocument(n) {
+		path = path + "/" + frameSegment
+	}
+
 	childIndex := 0
 	textIndex := 0
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
@@ -145,7 +243,14 @@ func (b *treeBuilder) processChildren(n *html.Node, parent *treeNode, path strin
 func (b *treeBuilder) buildChildPath(n *html.Node, parentPath string, childIndex, textIndex *int) string {
 	switch n.Type {
 	case html.ElementNode:
-		path := fmt.Sprintf("%s/%s[%d]", parentPath, n.Data, *childIndex)
+		if _, ok := shadowRootMode(n); ok {
+			return parentPath + "/" + shadowRootSegment
+		}
+		tag := n.Data
+		if isFrameDocument(n) {
+			tag = "iframe"
+		}
+		path := fmt.Sprintf("%s/%s[%d]", parentPath, tag, *childIndex)
 		*childIndex++
 		return path
 	case html.TextNode:
@@ -158,6 +263,48 @@ func (b *treeBuilder) buildChildPath(n *html.Node, parentPath string, childIndex
 	return parentPath
 }
 
+// shadowRootMode reports whether n is a declarative shadow root and returns its mode.
+func shadowRootMode(n *html.Node) (string, bool) {
+	if n.Type != html.ElementNode || n.Data != "template" {
+		return "", false
+	}
+	for _, attr := range n.Attr {
+		if attr.Key == shadowRootAttribute {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+// frameDocument returns the escaped document of an inlined frame. Frames inlined as markup by earlier captures lost
+// their doctype, html, head and body to the template contents, and are compared as they were parsed.
+func frameDocument(n *html.Node) (string, bool) {
+	if !isFrameDocument(n) || n.FirstChild == nil {
+		return "", false
+	}
+	var document strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.TextNode {
+			return "", false
+		}
+		document.WriteString(c.Data)
+	}
+	return document.String(), true
+}
+
+// isFrameDocument reports whether n holds an inlined frame document in place of its iframe.
+func isFrameDocument(n *html.Node) bool {
+	if n.Type != html.ElementNode || n.Data != "template" {
+		return false
+	}
+	for _, attr := range n.Attr {
+		if attr.Key == frameAttribute {
+			return true
+		}
+	}
+	return false
+}
+
 type comparisonResult struct {
 	changes    []change
 	totalNodes int
@@ -169,6 +316,8 @@ type change struct {
 	baseline   *treeNode
 	target     *treeNode
 	indent     int
+	// modified reports whether a moved node also changed.
+	modified bool
 }
 
 type changeType int
@@ -178,6 +327,7 @@ const (
 	changeRemoved
 	changeModified
 	changeUnchanged
+	changeMoved
 )
 
 func (r *comparisonResult) diffRatio() float64 {
@@ -199,90 +349,398 @@ func (r *comparisonResult) diffRatio() float64 {
 	return ratio
 }
 
+// nodeComparator matches the nodes of the baseline and target trees in the manner of GumTree, so that an inserted
+// sibling does not shift the nodes after it, and reports the nodes that were added, removed, modified or moved.
+// Identical subtrees are matched first, largest first, then elements by their id, then containers by the matches of
+// their children, and finally the remaining children of matched nodes by aligning their tags.
+// Reference: J.-R. Falleri et al., "Fine-grained and Accurate Source Code Differencing", 2014
 type nodeComparator struct{}
 
+// matching is the state of matching a baseline tree to a target tree.
+type matching struct {
+	baselineParents map[*treeNode]*treeNode
+	targetParents   map[*treeNode]*treeNode
+	hashes          map[*treeNode]uint64
+	sizes           map[*treeNode]int
+	// baselineToTarget and targetToBaseline are the matched nodes in both directions.
+	baselineToTarget map[*treeNode]*treeNode
+	targetToBaseline map[*treeNode]*treeNode
+}
+
 func (c *nodeComparator) compare(baseline, target *treeNode) *comparisonResult {
+	m := &matching{
+		baselineParents:  make(map[*treeNode]*treeNode),
+		targetParents:    make(map[*treeNode]*treeNode),
+		hashes:           make(map[*treeNode]uint64),
+		sizes:            make(map[*treeNode]int),
+		baselineToTarget: make(map[*treeNode]*treeNode),
+		targetToBaseline: make(map[*treeNode]*treeNode),
+	}
+	baselineNodes := c.index(baseline, m.baselineParents, m)
+	targetNodes := c.index(target, m.targetParents, m)
+
+	c.match(m, baseline, target)
+	c.matchIdenticalSubtrees(m, baselineNodes, targetNodes)
+	c.matchIDs(m, baselineNodes, targetNodes)
+	c.matchContainers(m, target)
+	c.matchChildren(m, target)
+
 	result := &comparisonResult{
 		changes: []change{},
 	}
-	c.compareNodes(baseline, target, 0, result)
+	c.writeMatchedChildren(m, baseline, target, 0, result)
 	return result
 }
 
-func (c *nodeComparator) compareNodes(baseline, target *treeNode, indent int, result *comparisonResult) {
-	baselineMap := c.buildNodeMap(baseline.children)
-	targetMap := c.buildNodeMap(target.children)
+// index records the parents, hashes and sizes of the subtree of the root and returns its nodes in preorder, excluding
+// the root.
+func (c *nodeComparator) index(root *treeNode, parents map[*treeNode]*treeNode, m *matching) []*treeNode {
+	var nodes []*treeNode
+	var visit func(node *treeNode)
+	visit = func(node *treeNode) {
+		h := fnv.New64a()
+		fmt.Fprintf(h, "%d\x00%s\x00%s\x00", node.nodeType, node.tag, node.text)
+		for _, key := range slices.Sorted(maps.Keys(node.attrs)) {
+			fmt.Fprintf(h, "%s=%s\x00", key, node.attrs[key])
+		}
+		size := 1
+		for _, child := range node.children {
+			parents[child] = node
+			nodes = append(nodes, child)
+			visit(child)
+			fmt.Fprintf(h, "%x\x00", m.hashes[child])
+			size += m.sizes[child]
+		}
+		m.hashes[node] = h.Sum64()
+		m.sizes[node] = size
+	}
+	visit(root)
+	return nodes
+}
 
-	allPaths := c.collectAllPaths(baselineMap, targetMap)
+func (c *nodeComparator) match(m *matching, baseline, target *treeNode) {
+	m.baselineToTarget[baseline] = target
+	m.targetToBaseline[target] = baseline
+}
 
-	for _, path := range allPaths {
-		c.processNodeComparison(path, baselineMap, targetMap, indent, result)
+// matchSubtree matches the identical subtrees node by node.
+func (c *nodeComparator) matchSubtree(m *matching, baseline, target *treeNode) {
+	c.match(m, baseline, target)
+	for i := range baseline.children {
+		c.matchSubtree(m, baseline.children[i], target.children[i])
 	}
 }
 
-func (c *nodeComparator) buildNodeMap(nodes []*treeNode) map[string]*treeNode {
-	nodeMap := make(map[string]*treeNode)
-	for _, node := range nodes {
-		nodeMap[node.path] = node
+// matchIdenticalSubtrees matches the identical subtrees of more than one node, largest first. A subtree that occurs
+// several times is matched to the occurrence under the match of its parent, or only when it occurs once in each tree,
+// which leaves repeated markup such as list items to the alignment of children.
+func (c *nodeComparator) matchIdenticalSubtrees(m *matching, baselineNodes, targetNodes []*treeNode) {
+	targetsByHash := make(map[uint64][]*treeNode)
+	for _, node := range targetNodes {
+		targetsByHash[m.hashes[node]] = append(targetsByHash[m.hashes[node]], node)
+	}
+	baselineCounts := make(map[uint64]int)
+	for _, node := range baselineNodes {
+		baselineCounts[m.hashes[node]]++
+	}
+
+	candidates := slices.Clone(baselineNodes)
+	slices.SortStableFunc(candidates, func(a, b *treeNode) int {
+		return m.sizes[b] - m.sizes[a]
+	})
+
+	for _, node := range candidates {
+		if m.sizes[node] < 2 {
+			break
+		}
+		if _, ok := m.baselineToTarget[node]; ok {
+			continue
+		}
+
+		var unmatched []*treeNode
+		for _, target := range targetsByHash[m.hashes[node]] {
+			if _, ok := m.targetToBaseline[target]; !ok {
+				unmatched = append(unmatched, target)
+			}
+		}
+		if len(unmatched) == 0 {
+			continue
+		}
+
+		var matched *treeNode
+		parentMatch, parentMatched := m.baselineToTarget[m.baselineParents[node]]
+		for _, target := range unmatched {
+			if parentMatched && m.targetParents[target] == parentMatch {
+				matched = target
+				break
+			}
+		}
+		if matched == nil && len(unmatched) == 1 && len(targetsByHash[m.hashes[node]]) == 1 && baselineCounts[m.hashes[node]] == 1 {
+			matched = unmatched[0]
+		}
+		if matched != nil {
+			c.matchSubtree(m, node, matched)
+		}
 	}
-	return nodeMap
 }
 
-func (c *nodeComparator) collectAllPaths(baselineMap, targetMap map[string]*treeNode) []string {
-	pathSet := make(map[string]bool)
-	for path := range baselineMap {
-		pathSet[path] = true
+// matchIDs matches the elements with the same tag and id that are unique in both trees.
+func (c *nodeComparator) matchIDs(m *matching, baselineNodes, targetNodes []*treeNode) {
+	key := func(node *treeNode) (string, bool) {
+		id, ok := node.attrs["id"]
+		if node.nodeType != html.ElementNode || !ok || id == "" {
+			return "", false
+		}
+		return node.tag + "#" + id, true
 	}
-	for path := range targetMap {
-		pathSet[path] = true
+
+	baselineByKey := make(map[string][]*treeNode)
+	for _, node := range baselineNodes {
+		if k, ok := key(node); ok {
+			baselineByKey[k] = append(baselineByKey[k], node)
+		}
+	}
+	targetByKey := make(map[string][]*treeNode)
+	for _, node := range targetNodes {
+		if k, ok := key(node); ok {
+			targetByKey[k] = append(targetByKey[k], node)
+		}
 	}
 
-	paths := make([]string, 0, len(pathSet))
-	for path := range pathSet {
-		paths = append(paths, path)
+	for k, baselines := range baselineByKey {
+		targets := targetByKey[k]
+		if len(baselines) != 1 || len(targets) != 1 {
+			continue
+		}
+		_, baselineMatched := m.baselineToTarget[baselines[0]]
+		_, targetMatched := m.targetToBaseline[targets[0]]
+		if !baselineMatched && !targetMatched {
+			c.match(m, baselines[0], targets[0])
+		}
 	}
-	sort.Strings(paths)
-	return paths
 }
 
-func (c *nodeComparator) processNodeComparison(path string, baselineMap, targetMap map[string]*treeNode, indent int, result *comparisonResult) {
-	baseNode, inBase := baselineMap[path]
-	targetNode, inTarget := targetMap[path]
+// matchContainers matches the unmatched target elements, children first, to the unmatched baseline element of the
+// same tag that holds the most of the matches of their children, when it holds at least half of them.
+func (c *nodeComparator) matchContainers(m *matching, node *treeNode) {
+	for _, child := range node.children {
+		c.matchContainers(m, child)
+	}
+	if _, ok := m.targetToBaseline[node]; ok || node.nodeType != html.ElementNode || len(node.children) == 0 {
+		return
+	}
 
-	result.totalNodes++
+	votes := make(map[*treeNode]int)
+	var best *treeNode
+	for _, child := range node.children {
+		match, ok := m.targetToBaseline[child]
+		if !ok {
+			continue
+		}
+		candidate := m.baselineParents[match]
+		if _, matched := m.baselineToTarget[candidate]; matched || candidate.nodeType != html.ElementNode || candidate.tag != node.tag {
+			continue
+		}
+		votes[candidate]++
+		if best == nil || votes[candidate] > votes[best] {
+			best = candidate
+		}
+	}
+	if best != nil && votes[best]*2 >= len(node.children) {
+		c.match(m, best, node)
+	}
+}
 
-	var change change
-	change.path = path
-	change.indent = indent
+// matchChildren aligns the unmatched children of every matched pair of nodes, parents first, by their tags, so that a
+// changed text or element is reported as modified in place.
+func (c *nodeComparator) matchChildren(m *matching, node *treeNode) {
+	if baseline, ok := m.targetToBaseline[node]; ok {
+		var baselineChildren, targetChildren []*treeNode
+		for _, child := range baseline.children {
+			if _, matched := m.baselineToTarget[child]; !matched {
+				baselineChildren = append(baselineChildren, child)
+			}
+		}
+		for _, child := range node.children {
+			if _, matched := m.targetToBaseline[child]; !matched {
+				targetChildren = append(targetChildren, child)
+			}
+		}
 
+		i, j := 0, 0
+		for _, op := range diff(c.labels(baselineChildren), c.labels(targetChildren)) {
+			switch op {
+			case operationEqual:
+				c.match(m, baselineChildren[i], targetChildren[j])
+				i++
+				j++
+			case operationAdded:
+				j++
+			case operationRemoved:
+				i++
+			}
+		}
+	}
+
+	for _, child := range node.children {
+		c.matchChildren(m, child)
+	}
+}
+
+func (c *nodeComparator) labels(nodes []*treeNode) []string {
+	labels := make([]string, len(nodes))
+	for i, node := range nodes {
+		if node.nodeType == html.TextNode {
+			labels[i] = "#text"
+		} else {
+			labels[i] = node.tag
+		}
+	}
+	return labels
+}
+
+// writeMatchedChildren writes the children of the target node in their order, with the removed children of its match
+// at their positions before the next matched sibling. Children matched to nodes elsewhere in the baseline, and
+// children reordered among their siblings, are reported as moved.
+func (c *nodeComparator) writeMatchedChildren(m *matching, baseline, target *treeNode, indent int, result *comparisonResult) {
+	positions := make(map[*treeNode]int)
+	if baseline != nil {
+		for i, child := range baseline.children {
+			positions[child] = i
+		}
+	}
+	stayed := c.stayedInPlace(m, baseline, target, positions)
+
+	next := 0
+	writeRemoved := func(until int) {
+		for ; next < until; next++ {
+			if _, ok := m.baselineToTarget[baseline.children[next]]; !ok {
+				c.writeRemoved(m, baseline.children[next], indent, result)
+			}
+		}
+	}
+
+	for _, child := range target.children {
+		match, ok := m.targetToBaseline[child]
+		if !ok {
+			c.writeAdded(m, child, indent, result)
+			continue
+		}
+		if baseline != nil && m.baselineParents[match] == baseline {
+			writeRemoved(positions[match] + 1)
+		}
+		c.writeMatched(m, match, child, !stayed[child], indent, result)
+	}
+	if baseline != nil {
+		writeRemoved(len(baseline.children))
+	}
+}
+
+// stayedInPlace returns the children of the target node that are matched to children of the baseline node and keep
+// their order, the longest increasing sequence of their positions among the baseline children.
+func (c *nodeComparator) stayedInPlace(m *matching, baseline, target *treeNode, positions map[*treeNode]int) map[*treeNode]bool {
+	stayed := make(map[*treeNode]bool)
+	if baseline == nil {
+		return stayed
+	}
+
+	var children []*treeNode
+	var order []int
+	for _, child := range target.children {
+		if match, ok := m.targetToBaseline[child]; ok && m.baselineParents[match] == baseline {
+			children = append(children, child)
+			order = append(order, positions[match])
+		}
+	}
+
+	for _, i := range longestIncreasingSubsequence(order) {
+		stayed[children[i]] = true
+	}
+	return stayed
+}
+
+func (c *nodeComparator) writeMatched(m *matching, baseline, target *treeNode, moved bool, indent int, result *comparisonResult) {
+	result.totalNodes++
+	change := change{
+		path:     target.path,
+		baseline: baseline,
+		target:   target,
+		indent:   indent,
+	}
 	switch {
-	case !inBase && inTarget:
-		change.changeType = changeAdded
-		change.target = targetNode
-		result.changes = append(result.changes, change)
-		if len(targetNode.children) > 0 {
-			c.compareNodes(&treeNode{children: []*treeNode{}}, targetNode, indent+1, result)
-		}
-	case inBase && !inTarget:
-		change.changeType = changeRemoved
-		change.baseline = baseNode
-		result.changes = append(result.changes, change)
-		if len(baseNode.children) > 0 {
-			c.compareNodes(baseNode, &treeNode{children: []*treeNode{}}, indent+1, result)
-		}
-	case inBase && inTarget:
-		if c.nodesEqual(baseNode, targetNode) {
-			change.changeType = changeUnchanged
-			change.baseline = baseNode
-			result.changes = append(result.changes, change)
+	case moved:
+		change.changeType = changeMoved
+		change.modified = !c.nodesEqual(baseline, target)
+	case c.nodesEqual(baseline, target):
+		change.changeType = changeUnchanged
+	default:
+		change.changeType = changeModified
+	}
+	result.changes = append(result.changes, change)
+
+	c.writeMatchedChildren(m, baseline, target, indent+1, result)
+}
+
+// writeAdded writes the added node and its children, of which the matched ones moved into it.
+func (c *nodeComparator) writeAdded(m *matching, target *treeNode, indent int, result *comparisonResult) {
+	result.totalNodes++
+	result.changes = append(result.changes, change{
+		changeType: changeAdded,
+		path:       target.path,
+		target:     target,
+		indent:     indent,
+	})
+
+	c.writeMatchedChildren(m, nil, target, indent+1, result)
+}
+
+// writeRemoved writes the removed node and its removed descendants. The matched descendants are written where they
+// moved to.
+func (c *nodeComparator) writeRemoved(m *matching, baseline *treeNode, indent int, result *comparisonResult) {
+	result.totalNodes++
+	result.changes = append(result.changes, change{
+		changeType: changeRemoved,
+		path:       baseline.path,
+		baseline:   baseline,
+		indent:     indent,
+	})
+
+	for _, child := range baseline.children {
+		if _, ok := m.baselineToTarget[child]; !ok {
+			c.writeRemoved(m, child, indent+1, result)
+		}
+	}
+}
+
+// longestIncreasingSubsequence returns the indices of a longest strictly increasing subsequence of the values.
+func longestIncreasingSubsequence(values []int) []int {
+	// tails[k] is the index of the smallest last value of the increasing subsequences of length k+1.
+	var tails []int
+	previous := make([]int, len(values))
+	for i, value := range values {
+		k, _ := slices.BinarySearchFunc(tails, value, func(index int, target int) int {
+			return values[index] - target
+		})
+		if k > 0 {
+			previous[i] = tails[k-1]
+		} else {
+			previous[i] = -1
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
 		} else {
-			change.changeType = changeModified
-			change.baseline = baseNode
-			change.target = targetNode
-			result.changes = append(result.changes, change)
+			tails[k] = i
 		}
-		c.compareNodes(baseNode, targetNode, indent+1, result)
 	}
+
+	indices := make([]int, len(tails))
+	if len(tails) == 0 {
+		return indices
+	}
+	for k, i := len(tails)-1, tails[len(tails)-1]; k >= 0; k, i = k-1, previous[i] {
+		indices[k] = i
+	}
+	return indices
 }
 
 func (c *nodeComparator) nodesEqual(a, b *treeNode) bool {
@@ -325,6 +783,24 @@ func (f *diffFormatter) format(comparison *comparisonResult) []byte {
 	return buf.Bytes()
 }
 
+// changes returns the changed nodes for machine consumption.
+func (f *diffFormatter) changes(comparison *comparisonResult) []Change {
+	var changes []Change
+	for _, change := range comparison.changes {
+		switch change.changeType {
+		case changeAdded:
+			changes = append(changes, Change{Type: ChangeAdded, Path: change.path, New: f.formatNode(change.target)})
+		case changeRemoved:
+			changes = append(changes, Change{Type: ChangeRemoved, Path: change.path, Old: f.formatNode(change.baseline)})
+		case changeModified:
+			changes = append(changes, Change{Type: ChangeModified, Path: change.path, Old: f.formatNode(change.baseline), New: f.formatNode(change.target)})
+		case changeMoved:
+			changes = append(changes, Change{Type: ChangeMoved, Path: change.path, From: change.baseline.path, Old: f.formatNode(change.baseline), New: f.formatNode(change.target)})
+		}
+	}
+	return changes
+}
+
 func (f *diffFormatter) writeHeader(w *bytes.Buffer) {
 	w.WriteString("DOM Tree Diff:\n")
 	w.WriteString("==============\n\n")
@@ -347,6 +823,13 @@ func (f *diffFormatter) writeChange(w *bytes.Buffer, change change) {
 	case changeModified:
 		fmt.Fprintf(w, "%s%s %s → %s\n", indent, symbolModified,
 			f.formatNode(change.baseline), f.formatNode(change.target))
+	case changeMoved:
+		if change.modified {
+			fmt.Fprintf(w, "%s%s %s → %s (from %s)\n", indent, symbolMoved,
+				f.formatNode(change.baseline), f.formatNode(change.target), change.baseline.path)
+		} else {
+			fmt.Fprintf(w, "%s%s %s (from %s)\n", indent, symbolMoved, f.formatNode(change.target), change.baseline.path)
+		}
 	case changeUnchanged:
 		fmt.Fprintf(w, "%s%s %s\n", indent, symbolUnchanged, f.formatNode(change.baseline))
 	}
@@ -358,6 +841,10 @@ func (f *diffFormatter) formatNode(n *treeNode) string {
 	}
 
 	if n.nodeType == html.ElementNode {
+		if n.tag == shadowRootSegment {
+			return fmt.Sprintf("%s (%s)", shadowRootSegment, n.attrs["mode"])
+		}
+
 		if len(n.attrs) == 0 {
 			return fmt.Sprintf("<%s>", n.tag)
 		}
@@ -383,6 +870,7 @@ func (f *diffFormatter) writeLegend(w *bytes.Buffer) {
 	w.WriteString("  " + symbolAdded + " Added\n")
 	w.WriteString("  " + symbolRemoved + " Removed\n")
 	w.WriteString("  " + symbolModified + " Modified\n")
+	w.WriteString("  " + symbolMoved + " Moved\n")
 	w.WriteString("  " + symbolUnchanged + " Unchanged\n")
 }
 
@@ -0,0 +1,173 @@
+package text
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestDOMDiff_Calculate(t *testing.T) {
+	changedLines := func(result *DiffResult) []string {
+		changes, _, _ := strings.Cut(string(result.Diff), "\nLegend:")
+		var changed []string
+		for _, line := range strings.Split(changes, "\n") {
+			if strings.Contains(line, "[") && !strings.Contains(line, symbolUnchanged) {
+				changed = append(changed, line)
+			}
+		}
+		return changed
+	}
+
+	tests := []struct {
+		name     string
+		baseline string
+		target   string
+		expected []string
+	}{
+		{
+			name:     "InsertedSibling",
+			baseline: `<ul><li>a</li><li>b</li><li>c</li></ul>`,
+			target:   `<ul><li>x</li><li>a</li><li>b</li><li>c</li></ul>`,
+			expected: []string{
+				"      [+] <li>",
+				"        [+] text: \"x\"",
+			},
+		},
+		{
+			name:     "RemovedSibling",
+			baseline: `<ul><li>a</li><li>b</li><li>c</li></ul>`,
+			target:   `<ul><li>a</li><li>c</li></ul>`,
+			expected: []string{
+				"      [-] <li>",
+				"        [-] text: \"b\"",
+			},
+		},
+		{
+			name:     "MovedAcrossParents",
+			baseline: `<div id="a"><p>one</p></div><div id="b"></div>`,
+			target:   `<div id="a"></div><div id="b"><p>one</p></div>`,
+			expected: []string{
+				"      [>] <p> (from /html[0]/body[1]/div[0]/p[0])",
+			},
+		},
+		{
+			name:     "ReorderedSiblings",
+			baseline: `<ul><li>a</li><li>b</li><li>c</li></ul>`,
+			target:   `<ul><li>c</li><li>a</li><li>b</li></ul>`,
+			expected: []string{
+				"      [>] <li> (from /html[0]/body[1]/ul[0]/li[2])",
+			},
+		},
+		{
+			name:     "Modified",
+			baseline: `<p>hello</p><p class="x">b</p>`,
+			target:   `<p>world</p><p class="y">b</p>`,
+			expected: []string{
+				"      [~] text: \"hello\" → text: \"world\"",
+				"    [~] <p class=\"x\"> → <p class=\"y\">",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewDOMDiff().Calculate([]byte(tt.baseline), []byte(tt.target))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if changed := changedLines(result); strings.Join(changed, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Expected changes:\n%s\ngot:\n%s", strings.Join(tt.expected, "\n"), result.Diff)
+			}
+		})
+	}
+
+	t.Run("Changes", func(t *testing.T) {
+		result, err := NewDOMDiff().Calculate([]byte(`<div id="a"><p>one</p></div><div id="b"></div><p>x</p>`), []byte(`<div id="a"></div><div id="b"><p>one</p></div>`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := []Change{
+			{Type: ChangeMoved, Path: "/html[0]/body[1]/div[1]/p[0]", From: "/html[0]/body[1]/div[0]/p[0]", Old: "<p>", New: "<p>"},
+			{Type: ChangeRemoved, Path: "/html[0]/body[1]/p[2]", Old: "<p>"},
+			{Type: ChangeRemoved, Path: "/html[0]/body[1]/p[2]/text[0]", Old: `text: "x"`},
+		}
+		if !reflect.DeepEqual(result.Changes, expected) {
+			t.Errorf("Expected changes %+v, got %+v", expected, result.Changes)
+		}
+	})
+
+	t.Run("ShadowRoot", func(t *testing.T) {
+		result, err := NewDOMDiff().Calculate(
+			[]byte(`<x-card><template shadowrootmode="open"><p>one</p></template></x-card>`),
+			[]byte(`<x-card><template shadowrootmode="open"><p>two</p></template></x-card>`),
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := "          [~] text: \"one\" → text: \"two\""
+		if changed := changedLines(result); strings.Join(changed, "\n") != expected {
+			t.Errorf("Expected changes:\n%s\ngot:\n%s", expected, result.Diff)
+		}
+		if !strings.Contains(string(result.Diff), "#shadow-root (open)") {
+			t.Errorf("Expected the shadow root, got:\n%s", result.Diff)
+		}
+	})
+
+	t.Run("Frame", func(t *testing.T) {
+		frame := func(text string) []byte {
+			document := html.EscapeString(`<!DOCTYPE html><html><head><title>frame</title></head><body><p>` + text + `</p></body></html>`)
+			return []byte(`<p>page</p><template data-snapshot-frame src="frame.html">` + document + `</template>`)
+		}
+
+		result, err := NewDOMDiff().Calculate(frame("one"), frame("two"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := "            [~] text: \"one\" → text: \"two\""
+		if changed := changedLines(result); strings.Join(changed, "\n") != expected {
+			t.Errorf("Expected changes:\n%s\ngot:\n%s", expected, result.Diff)
+		}
+		if !strings.Contains(string(result.Diff), `<iframe src="frame.html">`) || !strings.Contains(string(result.Diff), "<title>") {
+			t.Errorf("Expected the iframe with its head, got:\n%s", result.Diff)
+		}
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		result, err := NewDOMDiff().Calculate([]byte(`<p>a</p>`), []byte(`<p>a</p>`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.DiffAmount != 0 {
+			t.Errorf("Expected no changes, got DiffAmount %f", result.DiffAmount)
+		}
+	})
+}
+
+func TestDiffFormatter_FormatNode(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     *treeNode
+		expected string
+	}{
+		{name: "Text", node: &treeNode{nodeType: html.TextNode, text: "hello"}, expected: `text: "hello"`},
+		{name: "Element", node: &treeNode{nodeType: html.ElementNode, tag: "p", attrs: map[string]string{}}, expected: "<p>"},
+		{name: "Attributes", node: &treeNode{nodeType: html.ElementNode, tag: "a", attrs: map[string]string{"href": "/", "class": "link"}}, expected: `<a class="link" href="/">`},
+		{name: "ShadowRoot", node: &treeNode{nodeType: html.ElementNode, tag: shadowRootSegment, attrs: map[string]string{"mode": "open"}}, expected: "#shadow-root (open)"},
+		{name: "Unknown", node: &treeNode{nodeType: html.CommentNode}, expected: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := (&diffFormatter{}).formatNode(tt.node); actual != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package text
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Ignore excludes the parts of captured HTML that change without mattering, such as ads, timestamps or tracking
+// attributes, from the HTML diffs, so that their diff amounts reflect meaningful changes only. The fields mirror the
+// htmlIgnore field of the CRDs and are passed to the worker as JSON.
+type Ignore struct {
+	// Selectors are CSS selectors whose elements are removed with their subtrees.
+	Selectors []string `json:"selectors,omitempty"`
+	// Attributes are the names of the attributes removed from every element.
+	Attributes []string `json:"attributes,omitempty"`
+	// Lines are regular expressions in the RE2 syntax. LineDiff removes the lines they match, and DOMDiff the nodes
+	// whose line in its output they match, such as text: "Updated .*", with their subtrees.
+	Lines []string `json:"lines,omitempty"`
+}
+
+// ignoreRules are the compiled rules of an Ignore.
+type ignoreRules struct {
+	selectors  []selector
+	attributes map[string]bool
+	lines      []*regexp.Regexp
+}
+
+// Validate reports invalid selectors and line patterns, so that callers can reject the rules before diffing anything.
+func (i *Ignore) Validate() error {
+	_, err := i.compile()
+	return err
+}
+
+func (i *Ignore) compile() (*ignoreRules, error) {
+	rules := &ignoreRules{attributes: make(map[string]bool, len(i.Attributes))}
+	for _, source := range i.Selectors {
+		selectors, err := parseSelectors(source)
+		if err != nil {
+			return nil, err
+		}
+		rules.selectors = append(rules.selectors, selectors...)
+	}
+	for _, attribute := range i.Attributes {
+		rules.attributes[strings.ToLower(attribute)] = true
+	}
+	for _, line := range i.Lines {
+		pattern, err := regexp.Compile(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile ignored line pattern %q: %w", line, err)
+		}
+		rules.lines = append(rules.lines, pattern)
+	}
+	return rules, nil
+}
+
+// prunesMarkup reports whether the rules remove elements or attributes, which requires parsing the HTML.
+func (r *ignoreRules) prunesMarkup() bool {
+	return len(r.selectors) > 0 || len(r.attributes) > 0
+}
+
+// prune removes the elements matched by the selectors and the ignored attributes from the tree in place. The
+// whitespace before a removed element goes with it, so that the lines around it stay as they were. The escaped
+// documents of inlined frames are pruned as documents of their own.
+func (r *ignoreRules) prune(node *html.Node) {
+	if document, ok := frameDocument(node); ok {
+		if pruned, err := r.pruneDocument([]byte(document)); err == nil {
+			for node.FirstChild != nil {
+				node.RemoveChild(node.FirstChild)
+			}
+			node.AppendChild(&html.Node{Type: html.TextNode, Data: string(pruned)})
+		}
+	}
+	if node.Type == html.ElementNode && len(r.attributes) > 0 {
+		node.Attr = slices.DeleteFunc(node.Attr, func(attr html.Attribute) bool {
+			return r.attributes[attr.Key]
+		})
+	}
+
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+		if child.Type == html.ElementNode && slices.ContainsFunc(r.selectors, func(s selector) bool { return s.matches(child) }) {
+			if previous := child.PrevSibling; previous != nil && previous.Type == html.TextNode && strings.TrimSpace(previous.Data) == "" {
+				node.RemoveChild(previous)
+			}
+			node.RemoveChild(child)
+		} else {
+			r.prune(child)
+		}
+		child = next
+	}
+}
+
+// ignoresLine reports whether a line matches any of the line patterns.
+func (r *ignoreRules) ignoresLine(line string) bool {
+	for _, pattern := range r.lines {
+		if pattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneDocument removes the ignored elements and attributes from an HTML document and renders it again.
+func (r *ignoreRules) pruneDocument(data []byte) ([]byte, error) {
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	r.prune(doc)
+
+	var buffer bytes.Buffer
+	if err := html.Render(&buffer, doc); err != nil {
+		return nil, fmt.Errorf("failed to render HTML: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,105 @@
+package text
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestIgnore(t *testing.T) {
+	baseline := "<html><head></head><body>\n<div class=\"ad\">Sale 10%</div>\n<p data-ts=\"1\">Hello</p>\n<p>Updated 10:00</p>\n</body></html>"
+	target := "<html><head></head><body>\n<div class=\"ad\">Sale 20%</div>\n<p data-ts=\"2\">Hello</p>\n<p>Updated 11:00</p>\n</body></html>"
+	ignore := &Ignore{
+		Selectors:  []string{".ad"},
+		Attributes: []string{"data-ts"},
+		Lines:      []string{`Updated \d+:\d+`},
+	}
+
+	t.Run("LineDiff", func(t *testing.T) {
+		result, err := NewLineDiff().Calculate([]byte(baseline), []byte(target))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.DiffAmount == 0 {
+			t.Fatalf("Expected changes without ignore rules")
+		}
+
+		result, err = NewLineDiff(WithIgnore(ignore)).Calculate([]byte(baseline), []byte(target))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.DiffAmount != 0 {
+			t.Errorf("Expected no changes, got:\n%s", result.Diff)
+		}
+	})
+
+	t.Run("DOMDiff", func(t *testing.T) {
+		domIgnore := &Ignore{Selectors: ignore.Selectors, Attributes: ignore.Attributes, Lines: []string{`^text: "Updated`}}
+		result, err := NewDOMDiff(WithDOMIgnore(domIgnore)).Calculate([]byte(baseline), []byte(target))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.DiffAmount != 0 || len(result.Changes) != 0 {
+			t.Errorf("Expected no changes, got:\n%s", result.Diff)
+		}
+		if strings.Contains(string(result.Diff), "Sale") || strings.Contains(string(result.Diff), "data-ts") {
+			t.Errorf("Expected the ignored nodes and attributes to be removed, got:\n%s", result.Diff)
+		}
+	})
+
+	t.Run("Frame", func(t *testing.T) {
+		frame := func(text string) []byte {
+			document := html.EscapeString("<html><body>\n<p class=\"ad\">" + text + "</p>\n<p>same</p>\n</body></html>")
+			return []byte("<p>page</p>\n<template data-snapshot-frame>" + document + "</template>\n")
+		}
+		frameIgnore := &Ignore{Selectors: []string{".ad"}}
+
+		result, err := NewLineDiff(WithIgnore(frameIgnore)).Calculate(frame("one"), frame("two"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.DiffAmount != 0 {
+			t.Errorf("Expected the ignored element of the frame to be removed from the line diff, got:\n%s", result.Diff)
+		}
+
+		result, err = NewDOMDiff(WithDOMIgnore(frameIgnore)).Calculate(frame("one"), frame("two"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.DiffAmount != 0 {
+			t.Errorf("Expected the ignored element of the frame to be removed from the DOM diff, got:\n%s", result.Diff)
+		}
+	})
+
+	t.Run("KeepsSurroundingLines", func(t *testing.T) {
+		result, err := NewLineDiff(WithIgnore(&Ignore{Selectors: []string{"#x"}})).Calculate(
+			[]byte("<ul>\n  <li>a</li>\n  <li id=\"x\">b</li>\n  <li>c</li>\n</ul>\n"),
+			[]byte("<ul>\n  <li>a</li>\n  <li>c</li>\n</ul>\n"),
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.DiffAmount != 0 {
+			t.Errorf("Expected no changes, got:\n%s", result.Diff)
+		}
+	})
+
+	t.Run("InvalidRules", func(t *testing.T) {
+		for _, invalid := range []*Ignore{{Selectors: []string{"a:hover"}}, {Lines: []string{"("}}} {
+			if _, err := NewLineDiff(WithIgnore(invalid)).Calculate(nil, nil); err == nil {
+				t.Errorf("Expected an error for %+v", invalid)
+			}
+			if _, err := NewDOMDiff(WithDOMIgnore(invalid)).Calculate(nil, nil); err == nil {
+				t.Errorf("Expected an error for %+v", invalid)
+			}
+			if err := invalid.Validate(); err == nil {
+				t.Errorf("Expected a validation error for %+v", invalid)
+			}
+		}
+
+		if err := ignore.Validate(); err != nil {
+			t.Errorf("Unexpected validation error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Granularities of the intra-line highlighting of LineDiff.
+const (
+	HighlightWord      = "word"
+	HighlightCharacter = "character"
+)
+
+// SegmentUnchanged is the type of a Segment kept in both lines. The changed segments have the types ChangeAdded and
+// ChangeRemoved.
+const SegmentUnchanged = "unchanged"
+
+// Segment is a run of text of a modified line pair, which is either kept, removed from the baseline line or added in
+// the target line.
+type Segment struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// WithHighlight sets the granularity of the second pass that diffs the modified line pairs, HighlightWord or
+// HighlightCharacter. Words are runs of letters and digits, runs of whitespace and single other characters, so that a
+// changed attribute value does not take its quotes along.
+func WithHighlight(granularity string) LineDiffOption {
+	return func(l *LineDiff) {
+		l.highlight = granularity
+	}
+}
+
+// maxHighlightTokens bounds the tokens of each line between the common prefix and suffix of a modified line pair
+// that the second pass diffs, as its time grows with their product. Pairs that differ in more tokens, such as
+// rewritten lines of minified HTML, are highlighted as a whole line removed and added.
+const maxHighlightTokens = 2000
+
+// segments diffs a modified line pair into the runs of text kept, removed and added.
+func (h *LineDiff) segments(old string, new string) []Segment {
+	before := h.tokenize(old)
+	after := h.tokenize(new)
+
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix && before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+	if len(before)-prefix-suffix > maxHighlightTokens || len(after)-prefix-suffix > maxHighlightTokens {
+		return h.wholeLineSegments(old, new)
+	}
+
+	var segments []Segment
+	var text strings.Builder
+	segmentType := ""
+	appendSegment := func(t string, token string) {
+		if t != segmentType && text.Len() > 0 {
+			segments = append(segments, Segment{Type: segmentType, Text: text.String()})
+			text.Reset()
+		}
+		segmentType = t
+		text.WriteString(token)
+	}
+
+	i, j := 0, 0
+	for _, op := range diff(before, after) {
+		switch op {
+		case operationEqual:
+			appendSegment(SegmentUnchanged, before[i])
+			i++
+			j++
+		case operationAdded:
+			appendSegment(ChangeAdded, after[j])
+			j++
+		case operationRemoved:
+			appendSegment(ChangeRemoved, before[i])
+			i++
+		}
+	}
+	if text.Len() > 0 {
+		segments = append(segments, Segment{Type: segmentType, Text: text.String()})
+	}
+	return segments
+}
+
+func (h *LineDiff) wholeLineSegments(old string, new string) []Segment {
+	var segments []Segment
+	if old != "" {
+		segments = append(segments, Segment{Type: ChangeRemoved, Text: old})
+	}
+	if new != "" {
+		segments = append(segments, Segment{Type: ChangeAdded, Text: new})
+	}
+	return segments
+}
+
+func (h *LineDiff) tokenize(line string) []string {
+	var tokens []string
+	if h.highlight == HighlightCharacter {
+		for _, r := range line {
+			tokens = append(tokens, string(r))
+		}
+		return tokens
+	}
+
+	class := func(r rune) int {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			return 1
+		case unicode.IsSpace(r):
+			return 2
+		default:
+			return 0
+		}
+	}
+
+	start := 0
+	previous := -1
+	for i, r := range line {
+		c := class(r)
+		if i > start && (c != previous || c == 0) {
+			tokens = append(tokens, line[start:i])
+			start = i
+		}
+		previous = c
+	}
+	if start < len(line) {
+		tokens = append(tokens, line[start:])
+	}
+	return tokens
+}
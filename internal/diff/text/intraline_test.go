@@ -0,0 +1,132 @@
+package text
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLineDiff_Highlight(t *testing.T) {
+	baseline := `<div class="card" data-id="12"><a href="/a">Link</a></div>`
+	target := `<div class="card" data-id="13"><a href="/a">Link</a></div>`
+
+	tests := []struct {
+		name        string
+		granularity string
+		expected    []Segment
+	}{
+		{
+			name:        "Word",
+			granularity: HighlightWord,
+			expected: []Segment{
+				{Type: SegmentUnchanged, Text: `<div class="card" data-id="`},
+				{Type: ChangeRemoved, Text: "12"},
+				{Type: ChangeAdded, Text: "13"},
+				{Type: SegmentUnchanged, Text: `"><a href="/a">Link</a></div>`},
+			},
+		},
+		{
+			name:        "Character",
+			granularity: HighlightCharacter,
+			expected: []Segment{
+				{Type: SegmentUnchanged, Text: `<div class="card" data-id="1`},
+				{Type: ChangeRemoved, Text: "2"},
+				{Type: ChangeAdded, Text: "3"},
+				{Type: SegmentUnchanged, Text: `"><a href="/a">Link</a></div>`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewLineDiff(WithHighlight(tt.granularity)).Calculate([]byte(baseline+"\n"), []byte(target+"\n"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(result.Changes) != 1 || !reflect.DeepEqual(result.Changes[0].Segments, tt.expected) {
+				t.Errorf("Expected segments %+v, got %+v", tt.expected, result.Changes)
+			}
+		})
+	}
+
+	t.Run("WordBoundaries", func(t *testing.T) {
+		result, err := NewLineDiff().Calculate([]byte("hello world\n"), []byte("hello there\n"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := []Segment{
+			{Type: SegmentUnchanged, Text: "hello "},
+			{Type: ChangeRemoved, Text: "world"},
+			{Type: ChangeAdded, Text: "there"},
+		}
+		if len(result.Changes) != 1 || !reflect.DeepEqual(result.Changes[0].Segments, expected) {
+			t.Errorf("Expected segments %+v, got %+v", expected, result.Changes)
+		}
+	})
+}
+
+func TestLineDiff_HighlightLongLines(t *testing.T) {
+	long := strings.Repeat(`<div class="item">text</div>`, 4000)
+
+	t.Run("SmallChange", func(t *testing.T) {
+		result, err := NewLineDiff(WithHighlight(HighlightCharacter)).Calculate([]byte(long+"a"+long+"\n"), []byte(long+"b"+long+"\n"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := []Segment{
+			{Type: SegmentUnchanged, Text: long},
+			{Type: ChangeRemoved, Text: "a"},
+			{Type: ChangeAdded, Text: "b"},
+			{Type: SegmentUnchanged, Text: long},
+		}
+		if len(result.Changes) != 1 || !reflect.DeepEqual(result.Changes[0].Segments, expected) {
+			t.Errorf("Expected the change to be highlighted, got %d changes", len(result.Changes))
+		}
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		baseline := strings.Repeat("a ", maxHighlightTokens)
+		target := strings.Repeat("b ", maxHighlightTokens)
+		result, err := NewLineDiff().Calculate([]byte(baseline+"\n"), []byte(target+"\n"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := []Segment{
+			{Type: ChangeRemoved, Text: baseline},
+			{Type: ChangeAdded, Text: target},
+		}
+		if len(result.Changes) != 1 || !reflect.DeepEqual(result.Changes[0].Segments, expected) {
+			t.Errorf("Expected the whole line to be highlighted, got %d changes", len(result.Changes))
+		}
+		if !strings.Contains(string(result.Rendered), "<del>"+baseline+"</del>") || !strings.Contains(string(result.Rendered), "<ins>"+target+"</ins>") {
+			t.Errorf("Expected the whole line in <del> and <ins>")
+		}
+	})
+}
+
+func TestLineDiff_Rendered(t *testing.T) {
+	result, err := NewLineDiff().Calculate([]byte("a\n<p id=\"x\">\n"), []byte("a\n<p id=\"y\">\nb"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		`<pre class="line-diff">`,
+		`<span class="diff-header">--- baseline</span>`,
+		`<span class="diff-header">+++ target</span>`,
+		`<span class="diff-hunk">@@ -1,2 +1,3 @@</span>`,
+		`<span class="diff-context"> a</span>`,
+		`<span class="diff-removed">-&lt;p id=&#34;<del>x</del>&#34;&gt;</span>`,
+		`<span class="diff-added">+&lt;p id=&#34;<ins>y</ins>&#34;&gt;</span>`,
+		`<span class="diff-added">+b</span>`,
+		`<span class="diff-marker">\ No newline at end of file</span>`,
+		`</pre>`,
+	}, "\n") + "\n"
+	if string(result.Rendered) != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result.Rendered)
+	}
+}
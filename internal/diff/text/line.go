@@ -3,22 +3,108 @@ package text
 import (
 	"bytes"
 	"fmt"
+	"html"
 )
 
-type LineDiff struct{}
+const noNewlineMarker = "\\ No newline at end of file"
 
-func NewLineDiff() *LineDiff {
-	return &LineDiff{}
+// LineDiff compares documents line by line and renders the changes in the unified diff format, with hunks of the
+// changed lines surrounded by context lines, so that the output can be applied with patch. A second pass diffs the
+// removed and added lines paired as modified by word or character, which the changes and the rendered HTML highlight.
+type LineDiff struct {
+	contextLines  int
+	baselineLabel string
+	targetLabel   string
+	highlight     string
+	ignore        *Ignore
+}
+
+type LineDiffOption func(*LineDiff)
+
+func NewLineDiff(opts ...LineDiffOption) *LineDiff {
+	l := &LineDiff{
+		contextLines:  3,
+		baselineLabel: "baseline",
+		targetLabel:   "target",
+		highlight:     HighlightWord,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// WithContextLines sets the number of unchanged lines shown around each change.
+func WithContextLines(lines int) LineDiffOption {
+	return func(l *LineDiff) {
+		l.contextLines = max(lines, 0)
+	}
+}
+
+// WithLabels sets the names of the baseline and the target in the "---" and "+++" headers, such as their URLs.
+func WithLabels(baseline string, target string) LineDiffOption {
+	return func(l *LineDiff) {
+		l.baselineLabel = baseline
+		l.targetLabel = target
+	}
+}
+
+// WithIgnore removes the ignored elements, attributes and lines from the documents before they are compared. The
+// documents are rendered again when elements or attributes are ignored, and the line numbers of the diff refer to the
+// documents without the ignored lines.
+func WithIgnore(ignore *Ignore) LineDiffOption {
+	return func(l *LineDiff) {
+		l.ignore = ignore
+	}
+}
+
+// document is a document split into lines. A document that does not end with a newline keeps it in
+// missingNewline, and its last line does not match a line with a newline.
+type document struct {
+	lines          [][]byte
+	missingNewline bool
+}
+
+// lineEdit is an operation of the edit script with the indices of its lines in the baseline and the target. The index
+// of the side an operation does not consume is that of the next line of the side.
+type lineEdit struct {
+	operation operation
+	before    int
+	after     int
 }
 
 func (h *LineDiff) Calculate(baseline []byte, target []byte) (*DiffResult, error) {
-	beforeLines := h.splitLines(baseline)
-	afterLines := h.splitLines(target)
+	var rules *ignoreRules
+	if h.ignore != nil {
+		var err error
+		rules, err = h.ignore.compile()
+		if err != nil {
+			return nil, err
+		}
+		if rules.prunesMarkup() {
+			baseline, err = rules.pruneDocument(baseline)
+			if err != nil {
+				return nil, fmt.Errorf("failed to prune baseline HTML: %w", err)
+			}
+			target, err = rules.pruneDocument(target)
+			if err != nil {
+				return nil, fmt.Errorf("failed to prune target HTML: %w", err)
+			}
+		}
+	}
 
-	lcs := h.calculateLCS(beforeLines, afterLines)
-	diff, addedCount, removedCount := h.generateDiff(beforeLines, afterLines, lcs)
+	before := h.splitLines(baseline)
+	after := h.splitLines(target)
+	if rules != nil && len(rules.lines) > 0 {
+		before = h.dropIgnoredLines(before, rules)
+		after = h.dropIgnoredLines(after, rules)
+	}
+
+	edits, addedCount, removedCount := h.edits(before, after)
+	hunks := h.hunks(edits)
+	changes := h.changes(before, after, edits)
 
-	totalLines := len(beforeLines) + len(afterLines)
+	totalLines := len(before.lines) + len(after.lines)
 
 	diffAmount := 0.0
 	if totalLines > 0 {
@@ -29,68 +115,271 @@ func (h *LineDiff) Calculate(baseline []byte, target []byte) (*DiffResult, error
 	}
 
 	return &DiffResult{
-		Diff:       diff,
+		Diff:       h.generateDiff(before, after, hunks),
 		DiffAmount: diffAmount,
+		Changes:    changes,
+		Rendered:   h.render(before, after, hunks, changes),
 	}, nil
 }
 
-func (h *LineDiff) splitLines(data []byte) [][]byte {
+func (h *LineDiff) splitLines(data []byte) document {
 	if len(data) == 0 {
-		return [][]byte{}
+		return document{}
+	}
+	if bytes.HasSuffix(data, []byte("\n")) {
+		return document{lines: bytes.Split(data[:len(data)-1], []byte("\n"))}
+	}
+	return document{lines: bytes.Split(data, []byte("\n")), missingNewline: true}
+}
+
+func (h *LineDiff) dropIgnoredLines(d document, rules *ignoreRules) document {
+	var kept document
+	for i, line := range d.lines {
+		if rules.ignoresLine(string(line)) {
+			continue
+		}
+		kept.lines = append(kept.lines, line)
+		kept.missingNewline = d.missingNewline && i == len(d.lines)-1
+	}
+	return kept
+}
+
+// keys returns the lines to compare. The last line of a document without a newline gets one appended, which no
+// split line contains, so that it differs from the same line followed by a newline as in the unified diff format.
+func (h *LineDiff) keys(d document) []string {
+	keys := make([]string, len(d.lines))
+	for i, line := range d.lines {
+		keys[i] = string(line)
+	}
+	if d.missingNewline {
+		keys[len(keys)-1] += "\n"
 	}
-	return bytes.Split(data, []byte("\n"))
+	return keys
 }
 
-func (h *LineDiff) calculateLCS(before, after [][]byte) [][]int {
-	m, n := len(before), len(after)
-	lcs := make([][]int, m+1)
-	for i := range lcs {
-		lcs[i] = make([]int, n+1)
+// edits returns the edit script turning before into after, with the numbers of added and removed lines.
+func (h *LineDiff) edits(before, after document) ([]lineEdit, int, int) {
+	operations := diff(h.keys(before), h.keys(after))
+
+	edits := make([]lineEdit, 0, len(operations))
+	addedCount := 0
+	removedCount := 0
+	i, j := 0, 0
+	for _, op := range operations {
+		edits = append(edits, lineEdit{operation: op, before: i, after: j})
+		switch op {
+		case operationEqual:
+			i++
+			j++
+		case operationAdded:
+			j++
+			addedCount++
+		case operationRemoved:
+			i++
+			removedCount++
+		}
 	}
+	return edits, addedCount, removedCount
+}
 
-	for i := 1; i <= m; i++ {
-		for j := 1; j <= n; j++ {
-			if bytes.Equal(before[i-1], after[j-1]) {
-				lcs[i][j] = lcs[i-1][j-1] + 1
+// hunks splits the changed lines of the edit script into hunks surrounded by their context lines.
+func (h *LineDiff) hunks(edits []lineEdit) [][]lineEdit {
+	var changes []int
+	for k, edit := range edits {
+		if edit.operation != operationEqual {
+			changes = append(changes, k)
+		}
+	}
+
+	var hunks [][]lineEdit
+	for start := 0; start < len(changes); {
+		// Changes separated by at most twice the context lines share a hunk, as their contexts would overlap.
+		end := start
+		for end+1 < len(changes) && changes[end+1]-changes[end]-1 <= 2*h.contextLines {
+			end++
+		}
+		hunks = append(hunks, edits[max(changes[start]-h.contextLines, 0):min(changes[end]+h.contextLines+1, len(edits))])
+		start = end + 1
+	}
+	return hunks
+}
+
+func (h *LineDiff) generateDiff(before, after document, hunks [][]lineEdit) []byte {
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	var result bytes.Buffer
+	fmt.Fprintf(&result, "--- %s\n+++ %s\n", h.baselineLabel, h.targetLabel)
+	for _, hunk := range hunks {
+		h.writeHunk(&result, before, after, hunk)
+	}
+	return result.Bytes()
+}
+
+// changes pairs the removed and added lines of every run of edits in order as modified lines, and reports the rest as
+// removed or added.
+func (h *LineDiff) changes(before, after document, edits []lineEdit) []Change {
+	var changes []Change
+	for k := 0; k < len(edits); {
+		if edits[k].operation == operationEqual {
+			k++
+			continue
+		}
+
+		var removed, added []lineEdit
+		for ; k < len(edits) && edits[k].operation != operationEqual; k++ {
+			if edits[k].operation == operationRemoved {
+				removed = append(removed, edits[k])
 			} else {
-				lcs[i][j] = max(lcs[i-1][j], lcs[i][j-1])
+				added = append(added, edits[k])
+			}
+		}
+
+		for i := 0; i < max(len(removed), len(added)); i++ {
+			switch {
+			case i < len(removed) && i < len(added):
+				changes = append(changes, Change{
+					Type:         ChangeModified,
+					BaselineLine: removed[i].before + 1,
+					TargetLine:   added[i].after + 1,
+					Old:          string(before.lines[removed[i].before]),
+					New:          string(after.lines[added[i].after]),
+					Segments:     h.segments(string(before.lines[removed[i].before]), string(after.lines[added[i].after])),
+				})
+			case i < len(removed):
+				changes = append(changes, Change{
+					Type:         ChangeRemoved,
+					BaselineLine: removed[i].before + 1,
+					Old:          string(before.lines[removed[i].before]),
+				})
+			default:
+				changes = append(changes, Change{
+					Type:       ChangeAdded,
+					TargetLine: added[i].after + 1,
+					New:        string(after.lines[added[i].after]),
+				})
 			}
 		}
 	}
+	return changes
+}
+
+func (h *LineDiff) writeHunk(result *bytes.Buffer, before, after document, edits []lineEdit) {
+	result.WriteString(h.hunkHeader(edits))
+	result.WriteByte('\n')
 
-	return lcs
+	for _, edit := range edits {
+		switch edit.operation {
+		case operationEqual:
+			h.writeLine(result, ' ', before, edit.before)
+		case operationAdded:
+			h.writeLine(result, '+', after, edit.after)
+		case operationRemoved:
+			h.writeLine(result, '-', before, edit.before)
+		}
+	}
 }
 
-func (h *LineDiff) generateDiff(before, after [][]byte, lcs [][]int) ([]byte, int, int) {
-	var result bytes.Buffer
-	i, j := len(before), len(after)
+func (h *LineDiff) hunkHeader(edits []lineEdit) string {
+	beforeCount := 0
+	afterCount := 0
+	for _, edit := range edits {
+		if edit.operation != operationAdded {
+			beforeCount++
+		}
+		if edit.operation != operationRemoved {
+			afterCount++
+		}
+	}
+	return fmt.Sprintf("@@ -%s +%s @@", h.hunkRange(edits[0].before, beforeCount), h.hunkRange(edits[0].after, afterCount))
+}
 
-	var lines [][]byte
-	addedCount := 0
-	removedCount := 0
+// hunkRange formats the range of a hunk from the 0-based index of its first line. A range of a single line omits the
+// count, and an empty range starts at the line before it.
+func (h *LineDiff) hunkRange(start int, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
 
-	for i > 0 || j > 0 {
-		if i > 0 && j > 0 && bytes.Equal(before[i-1], after[j-1]) {
-			lines = append([][]byte{[]byte(fmt.Sprintf("  %s", before[i-1]))}, lines...)
-			i--
-			j--
-		} else if j > 0 && (i == 0 || lcs[i][j-1] >= lcs[i-1][j]) {
-			lines = append([][]byte{[]byte(fmt.Sprintf("+ %s", after[j-1]))}, lines...)
-			j--
-			addedCount++
-		} else if i > 0 && (j == 0 || lcs[i][j-1] < lcs[i-1][j]) {
-			lines = append([][]byte{[]byte(fmt.Sprintf("- %s", before[i-1]))}, lines...)
-			i--
-			removedCount++
+func (h *LineDiff) writeLine(result *bytes.Buffer, prefix byte, d document, index int) {
+	result.WriteByte(prefix)
+	result.Write(d.lines[index])
+	result.WriteByte('\n')
+	if d.missingNewline && index == len(d.lines)-1 {
+		result.WriteString(noNewlineMarker)
+		result.WriteByte('\n')
+	}
+}
+
+// render renders the hunks as a <pre> element with a span per line, classed by its kind, where the changed segments of
+// modified lines are in <del> and <ins>. All text is escaped.
+func (h *LineDiff) render(before, after document, hunks [][]lineEdit, changes []Change) []byte {
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	removedSegments := make(map[int][]Segment)
+	addedSegments := make(map[int][]Segment)
+	for _, change := range changes {
+		if change.Type == ChangeModified {
+			removedSegments[change.BaselineLine-1] = change.Segments
+			addedSegments[change.TargetLine-1] = change.Segments
 		}
 	}
 
-	for i, line := range lines {
-		if i > 0 {
-			result.WriteByte('\n')
+	var result bytes.Buffer
+	result.WriteString(`<pre class="line-diff">` + "\n")
+	h.renderLine(&result, "diff-header", "--- "+h.baselineLabel)
+	h.renderLine(&result, "diff-header", "+++ "+h.targetLabel)
+	for _, hunk := range hunks {
+		h.renderLine(&result, "diff-hunk", h.hunkHeader(hunk))
+
+		for _, edit := range hunk {
+			switch edit.operation {
+			case operationEqual:
+				h.renderDocumentLine(&result, "diff-context", " ", before, edit.before, nil, "")
+			case operationAdded:
+				h.renderDocumentLine(&result, "diff-added", "+", after, edit.after, addedSegments[edit.after], ChangeRemoved)
+			case operationRemoved:
+				h.renderDocumentLine(&result, "diff-removed", "-", before, edit.before, removedSegments[edit.before], ChangeAdded)
+			}
 		}
-		result.Write(line)
 	}
+	result.WriteString("</pre>\n")
+	return result.Bytes()
+}
 
-	return result.Bytes(), addedCount, removedCount
+func (h *LineDiff) renderLine(result *bytes.Buffer, class string, text string) {
+	fmt.Fprintf(result, `<span class="%s">%s</span>`+"\n", class, html.EscapeString(text))
+}
+
+// renderDocumentLine renders a line of a document, from the segments of its modified line pair other than those of the
+// skipped type when there are any.
+func (h *LineDiff) renderDocumentLine(result *bytes.Buffer, class string, prefix string, d document, index int, segments []Segment, skipped string) {
+	fmt.Fprintf(result, `<span class="%s">%s`, class, prefix)
+	if segments == nil {
+		result.WriteString(html.EscapeString(string(d.lines[index])))
+	}
+	for _, segment := range segments {
+		switch segment.Type {
+		case skipped:
+		case ChangeAdded:
+			result.WriteString("<ins>" + html.EscapeString(segment.Text) + "</ins>")
+		case ChangeRemoved:
+			result.WriteString("<del>" + html.EscapeString(segment.Text) + "</del>")
+		default:
+			result.WriteString(html.EscapeString(segment.Text))
+		}
+	}
+	result.WriteString("</span>\n")
+	if d.missingNewline && index == len(d.lines)-1 {
+		h.renderLine(result, "diff-marker", noNewlineMarker)
+	}
 }
@@ -0,0 +1,112 @@
+package text
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLineDiff_Calculate(t *testing.T) {
+	var baseline, target strings.Builder
+	for i := 1; i <= 20; i++ {
+		fmt.Fprintf(&baseline, "line %d\n", i)
+		switch i {
+		case 2:
+			target.WriteString("changed 2\n")
+		case 18:
+		default:
+			fmt.Fprintf(&target, "line %d\n", i)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		opts     []LineDiffOption
+		expected string
+	}{
+		{
+			name: "DefaultContext",
+			opts: []LineDiffOption{WithLabels("https://example.com/a", "https://example.com/b")},
+			expected: "--- https://example.com/a\n+++ https://example.com/b\n" +
+				"@@ -1,5 +1,5 @@\n line 1\n-line 2\n+changed 2\n line 3\n line 4\n line 5\n" +
+				"@@ -15,6 +15,5 @@\n line 15\n line 16\n line 17\n-line 18\n line 19\n line 20\n",
+		},
+		{
+			name: "NoContext",
+			opts: []LineDiffOption{WithContextLines(0)},
+			expected: "--- baseline\n+++ target\n" +
+				"@@ -2 +2 @@\n-line 2\n+changed 2\n" +
+				"@@ -18 +17,0 @@\n-line 18\n",
+		},
+		{
+			name: "MergedHunks",
+			opts: []LineDiffOption{WithContextLines(8)},
+			expected: "--- baseline\n+++ target\n" +
+				"@@ -1,20 +1,19 @@\n line 1\n-line 2\n+changed 2\n" + lines(3, 17) + "-line 18\n line 19\n line 20\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewLineDiff(tt.opts...).Calculate([]byte(baseline.String()), []byte(target.String()))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if string(result.Diff) != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, result.Diff)
+			}
+			if expected := 3.0 / 39.0; result.DiffAmount != expected {
+				t.Errorf("Expected DiffAmount %f, got %f", expected, result.DiffAmount)
+			}
+		})
+	}
+
+	t.Run("Unchanged", func(t *testing.T) {
+		result, err := NewLineDiff().Calculate([]byte(baseline.String()), []byte(baseline.String()))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(result.Diff) != 0 || result.DiffAmount != 0 {
+			t.Errorf("Expected no diff, got %q", result.Diff)
+		}
+	})
+}
+
+func TestLineDiff_MissingNewline(t *testing.T) {
+	result, err := NewLineDiff().Calculate([]byte("a\nb\n"), []byte("a\nb"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "--- baseline\n+++ target\n@@ -1,2 +1,2 @@\n a\n-b\n+b\n" + noNewlineMarker + "\n"
+	if string(result.Diff) != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result.Diff)
+	}
+}
+
+func TestLineDiff_Changes(t *testing.T) {
+	result, err := NewLineDiff().Calculate([]byte("a\nb\nc\nd\n"), []byte("a\nB\nx\nc\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []Change{
+		{Type: ChangeModified, BaselineLine: 2, TargetLine: 2, Old: "b", New: "B", Segments: []Segment{{Type: ChangeRemoved, Text: "b"}, {Type: ChangeAdded, Text: "B"}}},
+		{Type: ChangeAdded, TargetLine: 3, New: "x"},
+		{Type: ChangeRemoved, BaselineLine: 4, Old: "d"},
+	}
+	if !reflect.DeepEqual(result.Changes, expected) {
+		t.Errorf("Expected changes %+v, got %+v", expected, result.Changes)
+	}
+}
+
+func lines(from int, to int) string {
+	var b strings.Builder
+	for i := from; i <= to; i++ {
+		fmt.Fprintf(&b, " line %d\n", i)
+	}
+	return b.String()
+}
@@ -0,0 +1,165 @@
+package text
+
+// operation is an edit of an edit script.
+type operation int
+
+const (
+	operationEqual operation = iota
+	operationAdded
+	operationRemoved
+)
+
+// diff returns a shortest edit script turning a into b, with one operation per element: operationEqual consumes an
+// element of both, operationRemoved one of a and operationAdded one of b. Within each run of edits between equal
+// elements the removals come before the additions.
+//
+// It uses the Myers O(ND) difference algorithm with the linear space refinement, which bisects the edit graph at the
+// middle snake of the forward and backward searches, so that the memory does not grow with the product of the lengths.
+// Reference: E. W. Myers, "An O(ND) Difference Algorithm and Its Variations", 1986
+func diff[T comparable](a []T, b []T) []operation {
+	d := &myers[T]{
+		a:          a,
+		b:          b,
+		operations: make([]operation, 0, max(len(a), len(b))),
+	}
+	d.compare(0, len(a), 0, len(b))
+	return d.operations
+}
+
+type myers[T comparable] struct {
+	a          []T
+	b          []T
+	operations []operation
+}
+
+// compare appends the edit script of a[aLow:aHigh] and b[bLow:bHigh].
+func (d *myers[T]) compare(aLow int, aHigh int, bLow int, bHigh int) {
+	for aLow < aHigh && bLow < bHigh && d.a[aLow] == d.b[bLow] {
+		d.operations = append(d.operations, operationEqual)
+		aLow++
+		bLow++
+	}
+	suffix := 0
+	for aLow < aHigh && bLow < bHigh && d.a[aHigh-1] == d.b[bHigh-1] {
+		aHigh--
+		bHigh--
+		suffix++
+	}
+
+	if aLow == aHigh || bLow == bHigh {
+		d.appendEdits(aHigh-aLow, bHigh-bLow)
+	} else if x, y, ok := d.middleSnake(aLow, aHigh, bLow, bHigh); ok {
+		d.compare(aLow, x, bLow, y)
+		d.compare(x, aHigh, y, bHigh)
+	} else {
+		d.appendEdits(aHigh-aLow, bHigh-bLow)
+	}
+
+	for ; suffix > 0; suffix-- {
+		d.operations = append(d.operations, operationEqual)
+	}
+}
+
+// appendEdits appends the removals and additions, keeping the removals of a preceding run first.
+func (d *myers[T]) appendEdits(removedCount int, addedCount int) {
+	start := len(d.operations)
+	for start > 0 && d.operations[start-1] != operationEqual {
+		start--
+	}
+	for i := start; i < len(d.operations); i++ {
+		if d.operations[i] == operationRemoved {
+			removedCount++
+		} else {
+			addedCount++
+		}
+	}
+	d.operations = d.operations[:start]
+	for ; removedCount > 0; removedCount-- {
+		d.operations = append(d.operations, operationRemoved)
+	}
+	for ; addedCount > 0; addedCount-- {
+		d.operations = append(d.operations, operationAdded)
+	}
+}
+
+// middleSnake searches the shortest edit script of a[aLow:aHigh] and b[bLow:bHigh] from both ends at once and returns
+// the point where the searches meet, which lies on a shortest edit script and splits it into two halves of about half
+// the edits. The ranges must be non-empty and differ in their first and last elements.
+func (d *myers[T]) middleSnake(aLow int, aHigh int, bLow int, bHigh int) (int, int, bool) {
+	n, m := aHigh-aLow, bHigh-bLow
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	forward := make([]int, 2*maxD+2)
+	backward := make([]int, 2*maxD+2)
+	for i := range forward {
+		forward[i] = -1
+		backward[i] = -1
+	}
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	delta := n - m
+	// An odd delta makes the forward search reach the overlap first, an even one the backward search.
+	front := delta%2 != 0
+	// The bounds of the diagonals are narrowed when the searches leave the edit graph.
+	forwardStart, forwardEnd, backwardStart, backwardEnd := 0, 0, 0, 0
+
+	for step := 0; step < maxD; step++ {
+		for k := -step + forwardStart; k <= step-forwardEnd; k += 2 {
+			var x int
+			if k == -step || (k != step && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[aLow+x] == d.b[bLow+y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			if x > n {
+				forwardEnd += 2
+			} else if y > m {
+				forwardStart += 2
+			} else if front {
+				backwardK := offset + delta - k
+				if backwardK >= 0 && backwardK < len(backward) && backward[backwardK] != -1 && x >= n-backward[backwardK] {
+					return aLow + x, bLow + y, true
+				}
+			}
+		}
+
+		for k := -step + backwardStart; k <= step-backwardEnd; k += 2 {
+			var x int
+			if k == -step || (k != step && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[aHigh-1-x] == d.b[bHigh-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			if x > n {
+				backwardEnd += 2
+			} else if y > m {
+				backwardStart += 2
+			} else if !front {
+				forwardK := offset + delta - k
+				if forwardK >= 0 && forwardK < len(forward) && forward[forwardK] != -1 {
+					forwardX := forward[forwardK]
+					if forwardX >= n-x {
+						return aLow + forwardX, bLow + offset + forwardX - forwardK, true
+					}
+				}
+			}
+		}
+	}
+
+	return 0, 0, false
+}
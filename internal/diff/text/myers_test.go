@@ -0,0 +1,101 @@
+package text
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	randomTokens := func() []byte {
+		tokens := make([]byte, random.Intn(30))
+		for i := range tokens {
+			tokens[i] = "abc"[random.Intn(3)]
+		}
+		return tokens
+	}
+
+	for i := 0; i < 1000; i++ {
+		a, b := randomTokens(), randomTokens()
+
+		operations := diff(a, b)
+
+		var kept, removed, added []byte
+		x, y := 0, 0
+		for _, op := range operations {
+			switch op {
+			case operationEqual:
+				if a[x] != b[y] {
+					t.Fatalf("%q -> %q: matched %q with %q", a, b, a[x], b[y])
+				}
+				kept = append(kept, a[x])
+				x++
+				y++
+			case operationRemoved:
+				removed = append(removed, a[x])
+				x++
+			case operationAdded:
+				added = append(added, b[y])
+				y++
+			}
+		}
+		if x != len(a) || y != len(b) {
+			t.Fatalf("%q -> %q: consumed %d and %d elements", a, b, x, y)
+		}
+		if expected := lcsLength(a, b); len(kept) != expected {
+			t.Fatalf("%q -> %q: expected %d equal elements, got %d", a, b, expected, len(kept))
+		}
+	}
+}
+
+func TestDiff_RemovalsFirst(t *testing.T) {
+	operations := diff([]string{"a", "b", "c"}, []string{"a", "x", "y", "c"})
+
+	expected := []operation{operationEqual, operationRemoved, operationAdded, operationAdded, operationEqual}
+	if len(operations) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, operations)
+	}
+	for i := range expected {
+		if operations[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, operations)
+		}
+	}
+}
+
+func TestLineDiff_LargeInput(t *testing.T) {
+	var baseline, target strings.Builder
+	for i := 0; i < 20000; i++ {
+		baseline.WriteString("<div>line</div>\n")
+		if i%1000 == 0 {
+			target.WriteString("<div>changed</div>\n")
+		} else {
+			target.WriteString("<div>line</div>\n")
+		}
+	}
+
+	result, err := NewLineDiff().Calculate([]byte(baseline.String()), []byte(target.String()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if expected := 40.0 / 40000.0; result.DiffAmount != expected {
+		t.Errorf("Expected DiffAmount %f, got %f", expected, result.DiffAmount)
+	}
+}
+
+func lcsLength(a []byte, b []byte) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				current[j] = previous[j-1] + 1
+			} else {
+				current[j] = max(previous[j], current[j-1])
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
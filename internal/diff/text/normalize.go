@@ -0,0 +1,208 @@
+package text
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Normalizer rewrites captured HTML before it is diffed so that changes which do not matter, such as reordered
+// attributes, reflowed whitespace or per-request tokens, do not show up as differences. The fields mirror the
+// htmlNormalization field of the CRDs and are passed to the worker as JSON.
+type Normalizer struct {
+	// PrettyPrint puts every tag and text on its own line, indented by its depth, so that the line diff reports
+	// changes per element instead of per line of the original markup.
+	PrettyPrint bool `json:"prettyPrint,omitempty"`
+	// SortAttributes orders the attributes of every element by name.
+	SortAttributes bool `json:"sortAttributes,omitempty"`
+	// CollapseWhitespace trims texts and collapses their runs of whitespace into a single space, except in the
+	// elements whose whitespace is significant.
+	CollapseWhitespace bool `json:"collapseWhitespace,omitempty"`
+	// DropComments removes comments.
+	DropComments bool `json:"dropComments,omitempty"`
+	// Replacements are applied in order to the normalized HTML.
+	Replacements []Replacement `json:"replacements,omitempty"`
+}
+
+// Replacement replaces the matches of a regular expression, for example nonce="[^"]+" with nonce="X".
+type Replacement struct {
+	// Pattern is a regular expression in the RE2 syntax.
+	Pattern string `json:"pattern"`
+	// Replacement may refer to the submatches of Pattern as $1 or ${name}.
+	Replacement string `json:"replacement"`
+}
+
+// preformattedElements keep their whitespace and their content on the original lines.
+var preformattedElements = map[string]bool{
+	"pre":      true,
+	"textarea": true,
+	"script":   true,
+	"style":    true,
+}
+
+var whitespacePattern = regexp.MustCompile(`\s+`)
+
+// Validate reports invalid replacement patterns, so that callers can reject the rules before capturing anything.
+func (n *Normalizer) Validate() error {
+	_, err := n.compile()
+	return err
+}
+
+func (n *Normalizer) compile() ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, len(n.Replacements))
+	for i, replacement := range n.Replacements {
+		pattern, err := regexp.Compile(replacement.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile replacement pattern %q: %w", replacement.Pattern, err)
+		}
+		patterns[i] = pattern
+	}
+	return patterns, nil
+}
+
+func (n *Normalizer) Normalize(data []byte) ([]byte, error) {
+	patterns, err := n.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	if n.PrettyPrint || n.SortAttributes || n.CollapseWhitespace || n.DropComments {
+		doc, err := html.Parse(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse HTML: %w", err)
+		}
+		n.rewrite(doc, false)
+
+		var buffer bytes.Buffer
+		if n.PrettyPrint {
+			err = n.prettyPrint(&buffer, doc, 0)
+		} else {
+			err = html.Render(&buffer, doc)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to render HTML: %w", err)
+		}
+		data = buffer.Bytes()
+	}
+
+	for i, pattern := range patterns {
+		data = pattern.ReplaceAll(data, []byte(n.Replacements[i].Replacement))
+	}
+	return data, nil
+}
+
+// rewrite applies the structural rules to the tree in place.
+func (n *Normalizer) rewrite(node *html.Node, preformatted bool) {
+	if node.Type == html.ElementNode {
+		if n.SortAttributes {
+			slices.SortStableFunc(node.Attr, func(a html.Attribute, b html.Attribute) int {
+				return strings.Compare(a.Namespace+":"+a.Key, b.Namespace+":"+b.Key)
+			})
+		}
+		preformatted = preformatted || preformattedElements[node.Data]
+	}
+
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+		switch {
+		case child.Type == html.CommentNode && n.DropComments:
+			node.RemoveChild(child)
+		case child.Type == html.TextNode && n.CollapseWhitespace && !preformatted:
+			child.Data = strings.TrimSpace(whitespacePattern.ReplaceAllString(child.Data, " "))
+			if child.Data == "" {
+				node.RemoveChild(child)
+			}
+		default:
+			n.rewrite(child, preformatted)
+		}
+		child = next
+	}
+}
+
+func (n *Normalizer) prettyPrint(buffer *bytes.Buffer, node *html.Node, depth int) error {
+	indent := strings.Repeat(" ", depth*indentSize)
+
+	switch node.Type {
+	case html.DocumentNode:
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if err := n.prettyPrint(buffer, child, depth); err != nil {
+				return err
+			}
+		}
+		return nil
+	case html.TextNode:
+		text := strings.TrimSpace(node.Data)
+		if text == "" {
+			return nil
+		}
+		buffer.WriteString(indent)
+		buffer.WriteString(html.EscapeString(text))
+		buffer.WriteByte('\n')
+		return nil
+	case html.ElementNode:
+	default:
+		// Doctypes and comments render on their own.
+		buffer.WriteString(indent)
+		if err := html.Render(buffer, node); err != nil {
+			return err
+		}
+		buffer.WriteByte('\n')
+		return nil
+	}
+
+	buffer.WriteString(indent)
+	buffer.WriteString(html.Token{Type: html.StartTagToken, Data: node.Data, Attr: node.Attr}.String())
+	buffer.WriteByte('\n')
+
+	if preformattedElements[node.Data] {
+		// The content is rendered as is, since indenting it would change it. Scripts and styles are raw text, which
+		// is not escaped.
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.TextNode && (node.Data == "script" || node.Data == "style") {
+				buffer.WriteString(child.Data)
+				continue
+			}
+			if err := html.Render(buffer, child); err != nil {
+				return err
+			}
+		}
+		if node.FirstChild != nil {
+			buffer.WriteByte('\n')
+		}
+	} else {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if err := n.prettyPrint(buffer, child, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !voidElements[node.Data] {
+		buffer.WriteString(indent)
+		buffer.WriteString("</" + node.Data + ">")
+		buffer.WriteByte('\n')
+	}
+	return nil
+}
+
+// voidElements have no end tag.
+var voidElements = map[string]bool{
+	"area":   true,
+	"base":   true,
+	"br":     true,
+	"col":    true,
+	"embed":  true,
+	"hr":     true,
+	"img":    true,
+	"input":  true,
+	"link":   true,
+	"meta":   true,
+	"param":  true,
+	"source": true,
+	"track":  true,
+	"wbr":    true,
+}
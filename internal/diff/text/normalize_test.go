@@ -0,0 +1,76 @@
+package text
+
+import (
+	"testing"
+)
+
+func TestNormalizer_Normalize(t *testing.T) {
+	tests := []struct {
+		name       string
+		normalizer Normalizer
+		input      string
+		expected   string
+	}{
+		{
+			name:       "SortAttributes",
+			normalizer: Normalizer{SortAttributes: true},
+			input:      `<div id="a" class="b">text</div>`,
+			expected:   `<html><head></head><body><div class="b" id="a">text</div></body></html>`,
+		},
+		{
+			name:       "CollapseWhitespace",
+			normalizer: Normalizer{CollapseWhitespace: true},
+			input:      "<p>\n  Hello\n   world  </p><pre> keep  this</pre>",
+			expected:   "<html><head></head><body><p>Hello world</p><pre> keep  this</pre></body></html>",
+		},
+		{
+			name:       "DropComments",
+			normalizer: Normalizer{DropComments: true},
+			input:      "<p>a<!-- build 1234 -->b</p>",
+			expected:   "<html><head></head><body><p>ab</p></body></html>",
+		},
+		{
+			name:       "PrettyPrint",
+			normalizer: Normalizer{PrettyPrint: true},
+			input:      `<!DOCTYPE html><p>Hello <b>world</b></p><br><script>if (a < b) {}</script>`,
+			expected: "<!DOCTYPE html>\n<html>\n  <head>\n  </head>\n  <body>\n    <p>\n      Hello\n      <b>\n        world\n      </b>\n    </p>\n" +
+				"    <br>\n    <script>\nif (a < b) {}\n    </script>\n  </body>\n</html>\n",
+		},
+		{
+			name: "Replacements",
+			normalizer: Normalizer{Replacements: []Replacement{
+				{Pattern: `nonce="[^"]+"`, Replacement: `nonce="X"`},
+				{Pattern: `app\.[0-9a-f]+\.js`, Replacement: `app.HASH.js`},
+			}},
+			input:    `<script nonce="r4nd0m" src="/app.3f9a2c.js"></script>`,
+			expected: `<script nonce="X" src="/app.HASH.js"></script>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.normalizer.Normalize([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if string(result) != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, result)
+			}
+		})
+	}
+
+	t.Run("InvalidPattern", func(t *testing.T) {
+		normalizer := Normalizer{Replacements: []Replacement{{Pattern: "("}}}
+
+		if _, err := normalizer.Normalize([]byte("<p></p>")); err == nil {
+			t.Error("Expected an error for an invalid pattern")
+		}
+		if err := normalizer.Validate(); err == nil {
+			t.Error("Expected a validation error for an invalid pattern")
+		}
+		if err := (&Normalizer{Replacements: []Replacement{{Pattern: `nonce="[^"]+"`}}}).Validate(); err != nil {
+			t.Errorf("Unexpected validation error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,288 @@
+package text
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// selector is a complex selector of compound selectors joined by combinators, with the subject last.
+type selector []compoundSelector
+
+// compoundSelector matches an element by its tag, id, classes and attributes. The combinator relates it to the
+// previous compound selector: ' ' for a descendant and '>' for a child.
+type compoundSelector struct {
+	combinator byte
+	tag        string
+	id         string
+	classes    []string
+	attributes []attributeSelector
+}
+
+// attributeSelector matches an attribute by its presence, or by its value with one of the operators =, ~=, |=, ^=,
+// $= and *=.
+type attributeSelector struct {
+	key      string
+	operator string
+	value    string
+}
+
+// parseSelectors parses a list of selectors separated by commas. It supports the type, universal, id, class and
+// attribute selectors combined by the descendant and child combinators, which is what masking dynamic content usually
+// needs; pseudo-classes and the sibling combinators are rejected.
+func parseSelectors(source string) ([]selector, error) {
+	p := &selectorParser{source: source}
+	var selectors []selector
+	for {
+		s, err := p.parseSelector()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse selector %q: %w", source, err)
+		}
+		selectors = append(selectors, s)
+		if p.done() {
+			return selectors, nil
+		}
+		p.position++ // ','
+	}
+}
+
+type selectorParser struct {
+	source   string
+	position int
+}
+
+func (p *selectorParser) done() bool {
+	return p.position >= len(p.source)
+}
+
+func (p *selectorParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.source[p.position]
+}
+
+func (p *selectorParser) skipWhitespace() bool {
+	start := p.position
+	for !p.done() && strings.IndexByte(" \t\n\r\f", p.peek()) >= 0 {
+		p.position++
+	}
+	return p.position > start
+}
+
+func (p *selectorParser) parseSelector() (selector, error) {
+	var s selector
+	combinator := byte(' ')
+	p.skipWhitespace()
+	for {
+		compound, err := p.parseCompound()
+		if err != nil {
+			return nil, err
+		}
+		compound.combinator = combinator
+		s = append(s, compound)
+
+		whitespace := p.skipWhitespace()
+		switch c := p.peek(); {
+		case c == 0 || c == ',':
+			return s, nil
+		case c == '>':
+			combinator = '>'
+			p.position++
+			p.skipWhitespace()
+		case c == '+' || c == '~':
+			return nil, fmt.Errorf("unsupported combinator %q", c)
+		case whitespace:
+			combinator = ' '
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d", c, p.position)
+		}
+	}
+}
+
+func (p *selectorParser) parseCompound() (compoundSelector, error) {
+	var compound compoundSelector
+	start := p.position
+	if p.peek() == '*' {
+		p.position++
+	} else if tag := p.parseIdentifier(); tag != "" {
+		compound.tag = strings.ToLower(tag)
+	}
+
+	for {
+		switch p.peek() {
+		case '#':
+			p.position++
+			id := p.parseIdentifier()
+			if id == "" {
+				return compound, fmt.Errorf("missing id at %d", p.position)
+			}
+			compound.id = id
+		case '.':
+			p.position++
+			class := p.parseIdentifier()
+			if class == "" {
+				return compound, fmt.Errorf("missing class at %d", p.position)
+			}
+			compound.classes = append(compound.classes, class)
+		case '[':
+			p.position++
+			attribute, err := p.parseAttribute()
+			if err != nil {
+				return compound, err
+			}
+			compound.attributes = append(compound.attributes, attribute)
+		case ':':
+			return compound, fmt.Errorf("unsupported pseudo-class at %d", p.position)
+		default:
+			if p.position == start {
+				return compound, fmt.Errorf("missing selector at %d", p.position)
+			}
+			return compound, nil
+		}
+	}
+}
+
+func (p *selectorParser) parseAttribute() (attributeSelector, error) {
+	var attribute attributeSelector
+	p.skipWhitespace()
+	attribute.key = strings.ToLower(p.parseIdentifier())
+	if attribute.key == "" {
+		return attribute, fmt.Errorf("missing attribute name at %d", p.position)
+	}
+	p.skipWhitespace()
+
+	if p.peek() == ']' {
+		p.position++
+		return attribute, nil
+	}
+	if strings.IndexByte("~|^$*", p.peek()) >= 0 {
+		attribute.operator = string(p.peek())
+		p.position++
+	}
+	if p.peek() != '=' {
+		return attribute, fmt.Errorf("unexpected %q at %d", p.peek(), p.position)
+	}
+	attribute.operator += "="
+	p.position++
+	p.skipWhitespace()
+
+	if quote := p.peek(); quote == '"' || quote == '\'' {
+		end := strings.IndexByte(p.source[p.position+1:], quote)
+		if end < 0 {
+			return attribute, fmt.Errorf("unterminated string at %d", p.position)
+		}
+		attribute.value = p.source[p.position+1 : p.position+1+end]
+		p.position += end + 2
+	} else {
+		attribute.value = p.parseIdentifier()
+	}
+	p.skipWhitespace()
+
+	if p.peek() != ']' {
+		return attribute, fmt.Errorf("unterminated attribute selector at %d", p.position)
+	}
+	p.position++
+	return attribute, nil
+}
+
+// parseIdentifier parses a name of letters, digits, hyphens, underscores and non-ASCII characters. Escapes are not
+// supported.
+func (p *selectorParser) parseIdentifier() string {
+	start := p.position
+	for !p.done() {
+		c := p.peek()
+		if c >= 0x80 || c == '-' || c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+			p.position++
+			continue
+		}
+		break
+	}
+	return p.source[start:p.position]
+}
+
+// matches reports whether the element is the subject of the selector.
+func (s selector) matches(n *html.Node) bool {
+	return s.matchesFrom(len(s)-1, n)
+}
+
+func (s selector) matchesFrom(index int, n *html.Node) bool {
+	if !s[index].matches(n) {
+		return false
+	}
+	if index == 0 {
+		return true
+	}
+	for parent := n.Parent; parent != nil && parent.Type == html.ElementNode; parent = parent.Parent {
+		if s.matchesFrom(index-1, parent) {
+			return true
+		}
+		if s[index].combinator == '>' {
+			return false
+		}
+	}
+	return false
+}
+
+func (c *compoundSelector) matches(n *html.Node) bool {
+	if n.Type != html.ElementNode || (c.tag != "" && c.tag != n.Data) {
+		return false
+	}
+	if c.id != "" && attributeValue(n, "id") != c.id {
+		return false
+	}
+	if len(c.classes) > 0 {
+		classes := strings.Fields(attributeValue(n, "class"))
+		for _, class := range c.classes {
+			if !slices.Contains(classes, class) {
+				return false
+			}
+		}
+	}
+	for _, attribute := range c.attributes {
+		if !attribute.matches(n) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *attributeSelector) matches(n *html.Node) bool {
+	index := slices.IndexFunc(n.Attr, func(attr html.Attribute) bool {
+		return attr.Namespace == "" && attr.Key == a.key
+	})
+	if index < 0 {
+		return false
+	}
+	value := n.Attr[index].Val
+
+	switch a.operator {
+	case "":
+		return true
+	case "=":
+		return value == a.value
+	case "~=":
+		return slices.Contains(strings.Fields(value), a.value)
+	case "|=":
+		return value == a.value || strings.HasPrefix(value, a.value+"-")
+	case "^=":
+		return a.value != "" && strings.HasPrefix(value, a.value)
+	case "$=":
+		return a.value != "" && strings.HasSuffix(value, a.value)
+	case "*=":
+		return a.value != "" && strings.Contains(value, a.value)
+	default:
+		return false
+	}
+}
+
+func attributeValue(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Namespace == "" && attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package text
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestParseSelectors(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<div id="main" class="card wide"><p lang="en-US" data-ts="1700000000">a</p><section><p class="ad">b</p></section></div>`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var elements []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			elements = append(elements, n)
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	tests := []struct {
+		selector string
+		expected []string
+	}{
+		{selector: "p", expected: []string{"p", "p.ad"}},
+		{selector: "#main", expected: []string{"div#main"}},
+		{selector: "div.card.wide", expected: []string{"div#main"}},
+		{selector: ".card.narrow", expected: nil},
+		{selector: "div > p", expected: []string{"p"}},
+		{selector: "div p", expected: []string{"p", "p.ad"}},
+		{selector: "#main>section  .ad", expected: []string{"p.ad"}},
+		{selector: "[data-ts]", expected: []string{"p"}},
+		{selector: `p[lang|="en"]`, expected: []string{"p"}},
+		{selector: `[class~=wide]`, expected: []string{"div#main"}},
+		{selector: `[data-ts^='17']`, expected: []string{"p"}},
+		{selector: `[class$="ad"]`, expected: []string{"p.ad"}},
+		{selector: "section, .ad", expected: []string{"section", "p.ad"}},
+		{selector: "*", expected: []string{"html", "head", "body", "div#main", "p", "section", "p.ad"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selectors, err := parseSelectors(tt.selector)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var matched []string
+			for _, element := range elements {
+				for _, s := range selectors {
+					if s.matches(element) {
+						matched = append(matched, describe(element))
+						break
+					}
+				}
+			}
+			if strings.Join(matched, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("Expected %v, got %v", tt.expected, matched)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"", "a,", "a:hover", "a + b", "[href", `[class$="ad" i]`, "#", "a ) b"} {
+		if _, err := parseSelectors(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func describe(n *html.Node) string {
+	switch {
+	case attributeValue(n, "id") != "":
+		return n.Data + "#" + attributeValue(n, "id")
+	case attributeValue(n, "class") == "ad":
+		return n.Data + ".ad"
+	default:
+		return n.Data
+	}
+}
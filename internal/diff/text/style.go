@@ -0,0 +1,199 @@
+package text
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StyleDiff compares computed style snapshots, the JSON encoded capture.ElementStyle lists, and reports property
+// changes per element. Elements are matched by selector and by their index among the selector matches.
+type StyleDiff struct{}
+
+func NewStyleDiff() *StyleDiff {
+	return &StyleDiff{}
+}
+
+type elementStyle struct {
+	Selector   string            `json:"selector"`
+	Index      int               `json:"index"`
+	Properties map[string]string `json:"properties"`
+}
+
+func (e *elementStyle) key() string {
+	return fmt.Sprintf("%s[%d]", e.Selector, e.Index)
+}
+
+type propertyChange struct {
+	changeType changeType
+	name       string
+	baseline   string
+	target     string
+}
+
+type elementChange struct {
+	changeType changeType
+	key        string
+	properties []propertyChange
+}
+
+func (s *StyleDiff) Calculate(baseline []byte, target []byte) (*DiffResult, error) {
+	baselineStyles, err := s.parse(baseline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse baseline styles: %w", err)
+	}
+
+	targetStyles, err := s.parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse target styles: %w", err)
+	}
+
+	changes, changedProperties, totalProperties := s.compare(baselineStyles, targetStyles)
+
+	diffAmount := 0.0
+	if totalProperties > 0 {
+		diffAmount = float64(changedProperties) / float64(totalProperties)
+	}
+
+	return &DiffResult{
+		Diff:       s.format(changes),
+		DiffAmount: diffAmount,
+	}, nil
+}
+
+func (s *StyleDiff) parse(data []byte) ([]*elementStyle, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	var styles []*elementStyle
+	if err := json.Unmarshal(data, &styles); err != nil {
+		return nil, err
+	}
+	return styles, nil
+}
+
+// compare matches elements in baseline order followed by the elements only present in the target.
+func (s *StyleDiff) compare(baseline, target []*elementStyle) ([]elementChange, int, int) {
+	targetMap := make(map[string]*elementStyle, len(target))
+	for _, element := range target {
+		targetMap[element.key()] = element
+	}
+
+	baselineKeys := make(map[string]bool, len(baseline))
+	changes := make([]elementChange, 0, len(baseline)+len(target))
+	changedProperties := 0
+	totalProperties := 0
+
+	for _, element := range baseline {
+		key := element.key()
+		baselineKeys[key] = true
+
+		targetElement, ok := targetMap[key]
+		if !ok {
+			changes = append(changes, elementChange{changeType: changeRemoved, key: key})
+			changedProperties += len(element.Properties)
+			totalProperties += len(element.Properties)
+			continue
+		}
+
+		properties, changed, total := s.compareProperties(element.Properties, targetElement.Properties)
+		changedProperties += changed
+		totalProperties += total
+
+		change := elementChange{changeType: changeUnchanged, key: key, properties: properties}
+		if changed > 0 {
+			change.changeType = changeModified
+		}
+		changes = append(changes, change)
+	}
+
+	for _, element := range target {
+		key := element.key()
+		if baselineKeys[key] {
+			continue
+		}
+		changes = append(changes, elementChange{changeType: changeAdded, key: key})
+		changedProperties += len(element.Properties)
+		totalProperties += len(element.Properties)
+	}
+
+	return changes, changedProperties, totalProperties
+}
+
+func (s *StyleDiff) compareProperties(baseline, target map[string]string) ([]propertyChange, int, int) {
+	nameSet := make(map[string]bool, len(baseline)+len(target))
+	for name := range baseline {
+		nameSet[name] = true
+	}
+	for name := range target {
+		nameSet[name] = true
+	}
+
+	names := make([]string, 0, len(nameSet))
+	for name := range nameSet {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var changes []propertyChange
+	for _, name := range names {
+		baselineValue, inBase := baseline[name]
+		targetValue, inTarget := target[name]
+
+		switch {
+		case !inBase:
+			changes = append(changes, propertyChange{changeType: changeAdded, name: name, target: targetValue})
+		case !inTarget:
+			changes = append(changes, propertyChange{changeType: changeRemoved, name: name, baseline: baselineValue})
+		case baselineValue != targetValue:
+			changes = append(changes, propertyChange{changeType: changeModified, name: name, baseline: baselineValue, target: targetValue})
+		}
+	}
+
+	return changes, len(changes), len(names)
+}
+
+func (s *StyleDiff) format(changes []elementChange) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("Computed Style Diff:\n")
+	buf.WriteString("====================\n\n")
+
+	indent := strings.Repeat(" ", indentSize)
+	for _, change := range changes {
+		switch change.changeType {
+		case changeAdded:
+			fmt.Fprintf(&buf, "%s %s\n", symbolAdded, change.key)
+		case changeRemoved:
+			fmt.Fprintf(&buf, "%s %s\n", symbolRemoved, change.key)
+		case changeModified:
+			fmt.Fprintf(&buf, "%s %s\n", symbolModified, change.key)
+		case changeUnchanged:
+			fmt.Fprintf(&buf, "%s %s\n", symbolUnchanged, change.key)
+		}
+
+		for _, property := range change.properties {
+			switch property.changeType {
+			case changeAdded:
+				fmt.Fprintf(&buf, "%s%s %s: %s\n", indent, symbolAdded, property.name, property.target)
+			case changeRemoved:
+				fmt.Fprintf(&buf, "%s%s %s: %s\n", indent, symbolRemoved, property.name, property.baseline)
+			case changeModified:
+				fmt.Fprintf(&buf, "%s%s %s: %s → %s\n", indent, symbolModified, property.name, property.baseline, property.target)
+			}
+		}
+	}
+
+	buf.WriteString("\nLegend:\n")
+	buf.WriteString("  " + symbolAdded + " Added\n")
+	buf.WriteString("  " + symbolRemoved + " Removed\n")
+	buf.WriteString("  " + symbolModified + " Modified\n")
+	buf.WriteString("  " + symbolUnchanged + " Unchanged\n")
+
+	return buf.Bytes()
+}
+
+var _ Differ = (*StyleDiff)(nil)
@@ -0,0 +1,83 @@
+package text
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStyleDiff_Calculate(t *testing.T) {
+	baseline := `[
+		{"selector": "h1", "index": 0, "properties": {"color": "red", "font-size": "12px"}},
+		{"selector": "p", "index": 0, "properties": {"color": "black"}},
+		{"selector": "p", "index": 1, "properties": {"margin": "0px"}}
+	]`
+	target := `[
+		{"selector": "p", "index": 0, "properties": {"color": "black"}},
+		{"selector": "h1", "index": 0, "properties": {"color": "blue", "font-weight": "700"}},
+		{"selector": "span", "index": 0, "properties": {"display": "inline"}}
+	]`
+
+	result, err := NewStyleDiff().Calculate([]byte(baseline), []byte(target))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Elements are matched by selector[index] regardless of their order, in baseline order followed by the added ones.
+	expected := "[~] h1[0]\n" +
+		"  [~] color: red → blue\n" +
+		"  [-] font-size: 12px\n" +
+		"  [+] font-weight: 700\n" +
+		"[ ] p[0]\n" +
+		"[-] p[1]\n" +
+		"[+] span[0]\n"
+	if changes, _, _ := strings.Cut(strings.TrimPrefix(string(result.Diff), "Computed Style Diff:\n====================\n\n"), "\nLegend:"); changes != expected {
+		t.Errorf("Expected changes:\n%s\ngot:\n%s", expected, result.Diff)
+	}
+
+	// h1 has 3 changed properties, p[1] and span 1 each, out of 6 properties with the unchanged one of p[0].
+	if expectedDiffAmount := 5.0 / 6.0; result.DiffAmount != expectedDiffAmount {
+		t.Errorf("Expected DiffAmount %f, got %f", expectedDiffAmount, result.DiffAmount)
+	}
+
+	t.Run("Unchanged", func(t *testing.T) {
+		result, err := NewStyleDiff().Calculate([]byte(baseline), []byte(baseline))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.DiffAmount != 0 {
+			t.Errorf("Expected no changes, got DiffAmount %f", result.DiffAmount)
+		}
+		if strings.Contains(string(result.Diff), "\n"+symbolModified) {
+			t.Errorf("Expected no modified elements, got:\n%s", result.Diff)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		result, err := NewStyleDiff().Calculate([]byte(""), []byte("  \n"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.DiffAmount != 0 {
+			t.Errorf("Expected no changes, got DiffAmount %f", result.DiffAmount)
+		}
+	})
+
+	t.Run("AllAdded", func(t *testing.T) {
+		result, err := NewStyleDiff().Calculate(nil, []byte(target))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.DiffAmount != 1 {
+			t.Errorf("Expected DiffAmount 1, got %f", result.DiffAmount)
+		}
+	})
+
+	t.Run("InvalidJSON", func(t *testing.T) {
+		if _, err := NewStyleDiff().Calculate([]byte("{"), []byte(target)); err == nil {
+			t.Errorf("Expected an error for invalid baseline styles")
+		}
+	})
+}
@@ -3,8 +3,48 @@ package text
 type DiffResult struct {
 	Diff       []byte
 	DiffAmount float64
+	// Changes are the changes in Diff for machine consumption. Only LineDiff and DOMDiff report them.
+	Changes []Change
+	// Rendered is Diff rendered as an HTML fragment, with the changed words of modified lines in <del> and <ins>. Only
+	// LineDiff renders it.
+	Rendered []byte
+}
+
+// Change types of Change.
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+	ChangeMoved    = "moved"
+)
+
+// Change is an added, removed, modified or moved line or node. Lines are numbered from 1 and nodes are identified by
+// their path, such as /html[0]/body[1]/div[2].
+type Change struct {
+	Type string `json:"type"`
+	// Path is the path of the node in the target, or in the baseline when the node was removed.
+	Path string `json:"path,omitempty"`
+	// From is the path of a moved node in the baseline.
+	From         string `json:"from,omitempty"`
+	BaselineLine int    `json:"baselineLine,omitempty"`
+	TargetLine   int    `json:"targetLine,omitempty"`
+	// Old is the line or node in the baseline, absent for additions.
+	Old string `json:"old,omitempty"`
+	// New is the line or node in the target, absent for removals.
+	New string `json:"new,omitempty"`
+	// Segments are the changes within a modified line.
+	Segments []Segment `json:"segments,omitempty"`
 }
 
 type Differ interface {
 	Calculate(baseline []byte, target []byte) (*DiffResult, error)
 }
+
+// Extension returns the file extension of the diffs rendered in the format. The line diff is a unified diff, which
+// patch and code review tools recognize by the extension.
+func Extension(format string) string {
+	if format == "line" {
+		return "diff"
+	}
+	return "txt"
+}
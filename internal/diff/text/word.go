@@ -0,0 +1,154 @@
+package text
+
+import (
+	"bytes"
+	"strings"
+)
+
+const (
+	operationRemovedOpen  = "[-"
+	operationRemovedClose = "-]"
+	operationAddedOpen    = "{+"
+	operationAddedClose   = "+}"
+)
+
+// WordDiff compares visible text word by word and renders the result in wdiff style,
+// keeping the line structure of the target text.
+type WordDiff struct{}
+
+func NewWordDiff() *WordDiff {
+	return &WordDiff{}
+}
+
+type wordEdit struct {
+	operation operation
+	token     string
+}
+
+func (w *WordDiff) Calculate(baseline []byte, target []byte) (*DiffResult, error) {
+	beforeTokens := w.tokenize(baseline)
+	afterTokens := w.tokenize(target)
+
+	edits := w.diffTokens(beforeTokens, afterTokens)
+
+	addedCount := 0
+	removedCount := 0
+	for _, edit := range edits {
+		if edit.token == "\n" {
+			continue
+		}
+		switch edit.operation {
+		case operationAdded:
+			addedCount++
+		case operationRemoved:
+			removedCount++
+		}
+	}
+
+	totalWords := w.countWords(beforeTokens) + w.countWords(afterTokens)
+
+	diffAmount := 0.0
+	if totalWords > 0 {
+		diffAmount = float64(addedCount+removedCount) / float64(totalWords)
+		if diffAmount > 1.0 {
+			diffAmount = 1.0
+		}
+	}
+
+	return &DiffResult{
+		Diff:       w.format(edits),
+		DiffAmount: diffAmount,
+	}, nil
+}
+
+// tokenize splits text into words, keeping line breaks as "\n" tokens.
+func (w *WordDiff) tokenize(data []byte) []string {
+	var tokens []string
+	for i, line := range strings.Split(string(data), "\n") {
+		if i > 0 {
+			tokens = append(tokens, "\n")
+		}
+		tokens = append(tokens, strings.Fields(line)...)
+	}
+	return tokens
+}
+
+func (w *WordDiff) countWords(tokens []string) int {
+	count := 0
+	for _, token := range tokens {
+		if token != "\n" {
+			count++
+		}
+	}
+	return count
+}
+
+func (w *WordDiff) diffTokens(before, after []string) []wordEdit {
+	edits := make([]wordEdit, 0, max(len(before), len(after)))
+	i, j := 0, 0
+	for _, op := range diff(before, after) {
+		switch op {
+		case operationEqual:
+			edits = append(edits, wordEdit{operation: operationEqual, token: before[i]})
+			i++
+			j++
+		case operationAdded:
+			edits = append(edits, wordEdit{operation: operationAdded, token: after[j]})
+			j++
+		case operationRemoved:
+			edits = append(edits, wordEdit{operation: operationRemoved, token: before[i]})
+			i++
+		}
+	}
+	return edits
+}
+
+func (w *WordDiff) format(edits []wordEdit) []byte {
+	var result bytes.Buffer
+
+	lineStart := true
+	for i := 0; i < len(edits); {
+		edit := edits[i]
+
+		if edit.token == "\n" {
+			if edit.operation != operationRemoved {
+				result.WriteByte('\n')
+				lineStart = true
+			}
+			i++
+			continue
+		}
+
+		if edit.operation == operationEqual {
+			if !lineStart {
+				result.WriteByte(' ')
+			}
+			result.WriteString(edit.token)
+			lineStart = false
+			i++
+			continue
+		}
+
+		j := i
+		var words []string
+		for j < len(edits) && edits[j].operation == edit.operation && edits[j].token != "\n" {
+			words = append(words, edits[j].token)
+			j++
+		}
+
+		if !lineStart {
+			result.WriteByte(' ')
+		}
+		if edit.operation == operationRemoved {
+			result.WriteString(operationRemovedOpen + strings.Join(words, " ") + operationRemovedClose)
+		} else {
+			result.WriteString(operationAddedOpen + strings.Join(words, " ") + operationAddedClose)
+		}
+		lineStart = false
+		i = j
+	}
+
+	return result.Bytes()
+}
+
+var _ Differ = (*WordDiff)(nil)
@@ -0,0 +1,95 @@
+package text
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWordDiff_Tokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{name: "Words", input: "the  quick\tfox", expected: []string{"the", "quick", "fox"}},
+		{name: "LineBreaks", input: "a b\n c\n\nd", expected: []string{"a", "b", "\n", "c", "\n", "\n", "d"}},
+		{name: "Empty", input: "", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := NewWordDiff().tokenize([]byte(tt.input)); !slices.Equal(actual, tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWordDiff_Calculate(t *testing.T) {
+	tests := []struct {
+		name               string
+		baseline           string
+		target             string
+		expected           string
+		expectedDiffAmount float64
+	}{
+		{
+			name:               "Unchanged",
+			baseline:           "a b c",
+			target:             "a b c",
+			expected:           "a b c",
+			expectedDiffAmount: 0,
+		},
+		{
+			name:               "ReplacedWord",
+			baseline:           "the quick fox",
+			target:             "the slow fox",
+			expected:           "the [-quick-] {+slow+} fox",
+			expectedDiffAmount: 2.0 / 6.0,
+		},
+		{
+			name:               "GroupsStopAtLineBreaks",
+			baseline:           "a b\nc d",
+			target:             "a x\ny d",
+			expected:           "a [-b-] {+x+}\n[-c-] {+y+} d",
+			expectedDiffAmount: 4.0 / 8.0,
+		},
+		{
+			name:               "AddedLines",
+			baseline:           "one two",
+			target:             "one two three\nfour five",
+			expected:           "one two {+three+}\n{+four five+}",
+			expectedDiffAmount: 3.0 / 7.0,
+		},
+		{
+			name:               "JoinedLines",
+			baseline:           "a\nb",
+			target:             "a b",
+			expected:           "a b",
+			expectedDiffAmount: 0,
+		},
+		{
+			name:               "Empty",
+			baseline:           "",
+			target:             "",
+			expected:           "",
+			expectedDiffAmount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewWordDiff().Calculate([]byte(tt.baseline), []byte(tt.target))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if string(result.Diff) != tt.expected {
+				t.Errorf("Expected diff %q, got %q", tt.expected, result.Diff)
+			}
+			if result.DiffAmount != tt.expectedDiffAmount {
+				t.Errorf("Expected DiffAmount %f, got %f", tt.expectedDiffAmount, result.DiffAmount)
+			}
+		})
+	}
+}
@@ -17,14 +17,44 @@ import (
 )
 
 type ArtifactsResponse struct {
-	Baseline       string  `json:"baseline,omitempty"`
-	Target         string  `json:"target,omitempty"`
-	BaselineHTML   string  `json:"baselineHtml,omitempty"`
-	TargetHTML     string  `json:"targetHtml,omitempty"`
-	ScreenshotDiff string  `json:"screenshotDiff,omitempty"`
-	HTMLDiff       string  `json:"htmlDiff,omitempty"`
-	DiffAmount     float64 `json:"diffAmount,omitempty"`
-	HTMLDiffAmount float64 `json:"htmlDiffAmount,omitempty"`
+	Baseline       string `json:"baseline,omitempty"`
+	Target         string `json:"target,omitempty"`
+	BaselineHTML   string `json:"baselineHtml,omitempty"`
+	TargetHTML     string `json:"targetHtml,omitempty"`
+	BaselineText   string `json:"baselineText,omitempty"`
+	TargetText     string `json:"targetText,omitempty"`
+	ScreenshotDiff string `json:"screenshotDiff,omitempty"`
+	// ScreenshotDiffRegions are the changed regions as stored, so that they can be consumed without decoding.
+	ScreenshotDiffRegions json.RawMessage `json:"screenshotDiffRegions,omitempty"`
+	HTMLDiff              string          `json:"htmlDiff,omitempty"`
+	// HTMLDiffChanges are the changes of the HTML diff as stored.
+	HTMLDiffChanges json.RawMessage `json:"htmlDiffChanges,omitempty"`
+	// HTMLDiffRendered is the HTML fragment of the line HTML diff, whose text is escaped.
+	HTMLDiffRendered string              `json:"htmlDiffRendered,omitempty"`
+	TextDiff         string              `json:"textDiff,omitempty"`
+	DiffAmount       float64             `json:"diffAmount,omitempty"`
+	InsertedBands    []v1.Band           `json:"insertedBands,omitempty"`
+	RemovedBands     []v1.Band           `json:"removedBands,omitempty"`
+	DimensionChange  *v1.DimensionChange `json:"dimensionChange,omitempty"`
+	HTMLDiffAmount   float64             `json:"htmlDiffAmount,omitempty"`
+	TextDiffAmount   float64             `json:"textDiffAmount,omitempty"`
+	BaselineStyles   string              `json:"baselineStyles,omitempty"`
+	TargetStyles     string              `json:"targetStyles,omitempty"`
+	StyleDiff        string              `json:"styleDiff,omitempty"`
+	StyleDiffAmount  float64             `json:"styleDiffAmount,omitempty"`
+	PageDiffs        []PageDiffArtifact  `json:"pageDiffs,omitempty"`
+	Composites       []CompositeArtifact `json:"composites,omitempty"`
+}
+
+type CompositeArtifact struct {
+	Format string `json:"format"`
+	Data   string `json:"data,omitempty"`
+}
+
+type PageDiffArtifact struct {
+	Page       int     `json:"page"`
+	Diff       string  `json:"diff,omitempty"`
+	DiffAmount float64 `json:"diffAmount"`
 }
 
 func ListArtifacts(dynamicClient *dynamic.DynamicClient, storageClient storage.Storage) http.HandlerFunc {
@@ -64,8 +94,13 @@ func ListArtifacts(dynamicClient *dynamic.DynamicClient, storageClient storage.S
 			}
 
 			response = ArtifactsResponse{
-				DiffAmount:     snapshot.Status.ScreenshotDiffAmount,
-				HTMLDiffAmount: snapshot.Status.HTMLDiffAmount,
+				DiffAmount:      snapshot.Status.ScreenshotDiffAmount,
+				InsertedBands:   snapshot.Status.ScreenshotInsertedBands,
+				RemovedBands:    snapshot.Status.ScreenshotRemovedBands,
+				DimensionChange: snapshot.Status.ScreenshotDimensionChange,
+				HTMLDiffAmount:  snapshot.Status.HTMLDiffAmount,
+				TextDiffAmount:  snapshot.Status.TextDiffAmount,
+				StyleDiffAmount: snapshot.Status.StyleDiffAmount,
 			}
 
 			if snapshot.Status.BaselineURL != "" {
@@ -93,11 +128,77 @@ func ListArtifacts(dynamicClient *dynamic.DynamicClient, storageClient storage.S
 					response.ScreenshotDiff = base64.StdEncoding.EncodeToString(data)
 				}
 			}
+			if snapshot.Status.ScreenshotDiffRegionsURL != "" {
+				if data, err := storageClient.Get(r.Context(), snapshot.Status.ScreenshotDiffRegionsURL); err == nil && json.Valid(data) {
+					response.ScreenshotDiffRegions = data
+				}
+			}
 			if snapshot.Status.HTMLDiffURL != "" {
 				if data, err := storageClient.Get(r.Context(), snapshot.Status.HTMLDiffURL); err == nil {
 					response.HTMLDiff = base64.StdEncoding.EncodeToString(data)
 				}
 			}
+			if snapshot.Status.HTMLDiffChangesURL != "" {
+				if data, err := storageClient.Get(r.Context(), snapshot.Status.HTMLDiffChangesURL); err == nil && json.Valid(data) {
+					response.HTMLDiffChanges = data
+				}
+			}
+			if snapshot.Status.HTMLDiffRenderedURL != "" {
+				if data, err := storageClient.Get(r.Context(), snapshot.Status.HTMLDiffRenderedURL); err == nil {
+					response.HTMLDiffRendered = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			if snapshot.Status.BaselineTextURL != "" {
+				if data, err := storageClient.Get(r.Context(), snapshot.Status.BaselineTextURL); err == nil {
+					response.BaselineText = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			if snapshot.Status.TargetTextURL != "" {
+				if data, err := storageClient.Get(r.Context(), snapshot.Status.TargetTextURL); err == nil {
+					response.TargetText = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			if snapshot.Status.TextDiffURL != "" {
+				if data, err := storageClient.Get(r.Context(), snapshot.Status.TextDiffURL); err == nil {
+					response.TextDiff = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			if snapshot.Status.BaselineStylesURL != "" {
+				if data, err := storageClient.Get(r.Context(), snapshot.Status.BaselineStylesURL); err == nil {
+					response.BaselineStyles = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			if snapshot.Status.TargetStylesURL != "" {
+				if data, err := storageClient.Get(r.Context(), snapshot.Status.TargetStylesURL); err == nil {
+					response.TargetStyles = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			if snapshot.Status.StyleDiffURL != "" {
+				if data, err := storageClient.Get(r.Context(), snapshot.Status.StyleDiffURL); err == nil {
+					response.StyleDiff = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			for _, composite := range snapshot.Status.ScreenshotComposites {
+				artifact := CompositeArtifact{
+					Format: composite.Format,
+				}
+				if data, err := storageClient.Get(r.Context(), composite.URL); err == nil {
+					artifact.Data = base64.StdEncoding.EncodeToString(data)
+				}
+				response.Composites = append(response.Composites, artifact)
+			}
+			for _, pageDiff := range snapshot.Status.PageDiffs {
+				artifact := PageDiffArtifact{
+					Page:       pageDiff.Page,
+					DiffAmount: pageDiff.DiffAmount,
+				}
+				if pageDiff.DiffURL != "" {
+					if data, err := storageClient.Get(r.Context(), pageDiff.DiffURL); err == nil {
+						artifact.Diff = base64.StdEncoding.EncodeToString(data)
+					}
+				}
+				response.PageDiffs = append(response.PageDiffs, artifact)
+			}
 		case "scheduledsnapshot":
 			var scheduledSnapshot v1.ScheduledSnapshot
 			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &scheduledSnapshot); err != nil {
@@ -107,8 +208,13 @@ func ListArtifacts(dynamicClient *dynamic.DynamicClient, storageClient storage.S
 			}
 
 			response = ArtifactsResponse{
-				DiffAmount:     scheduledSnapshot.Status.ScreenshotDiffAmount,
-				HTMLDiffAmount: scheduledSnapshot.Status.HTMLDiffAmount,
+				DiffAmount:      scheduledSnapshot.Status.ScreenshotDiffAmount,
+				InsertedBands:   scheduledSnapshot.Status.ScreenshotInsertedBands,
+				RemovedBands:    scheduledSnapshot.Status.ScreenshotRemovedBands,
+				DimensionChange: scheduledSnapshot.Status.ScreenshotDimensionChange,
+				HTMLDiffAmount:  scheduledSnapshot.Status.HTMLDiffAmount,
+				TextDiffAmount:  scheduledSnapshot.Status.TextDiffAmount,
+				StyleDiffAmount: scheduledSnapshot.Status.StyleDiffAmount,
 			}
 
 			if scheduledSnapshot.Status.BaselineURL != "" {
@@ -136,11 +242,77 @@ func ListArtifacts(dynamicClient *dynamic.DynamicClient, storageClient storage.S
 					response.ScreenshotDiff = base64.StdEncoding.EncodeToString(data)
 				}
 			}
+			if scheduledSnapshot.Status.ScreenshotDiffRegionsURL != "" {
+				if data, err := storageClient.Get(r.Context(), scheduledSnapshot.Status.ScreenshotDiffRegionsURL); err == nil && json.Valid(data) {
+					response.ScreenshotDiffRegions = data
+				}
+			}
 			if scheduledSnapshot.Status.HTMLDiffURL != "" {
 				if data, err := storageClient.Get(r.Context(), scheduledSnapshot.Status.HTMLDiffURL); err == nil {
 					response.HTMLDiff = base64.StdEncoding.EncodeToString(data)
 				}
 			}
+			if scheduledSnapshot.Status.HTMLDiffChangesURL != "" {
+				if data, err := storageClient.Get(r.Context(), scheduledSnapshot.Status.HTMLDiffChangesURL); err == nil && json.Valid(data) {
+					response.HTMLDiffChanges = data
+				}
+			}
+			if scheduledSnapshot.Status.HTMLDiffRenderedURL != "" {
+				if data, err := storageClient.Get(r.Context(), scheduledSnapshot.Status.HTMLDiffRenderedURL); err == nil {
+					response.HTMLDiffRendered = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			if scheduledSnapshot.Status.BaselineTextURL != "" {
+				if data, err := storageClient.Get(r.Context(), scheduledSnapshot.Status.BaselineTextURL); err == nil {
+					response.BaselineText = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			if scheduledSnapshot.Status.TargetTextURL != "" {
+				if data, err := storageClient.Get(r.Context(), scheduledSnapshot.Status.TargetTextURL); err == nil {
+					response.TargetText = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			if scheduledSnapshot.Status.TextDiffURL != "" {
+				if data, err := storageClient.Get(r.Context(), scheduledSnapshot.Status.TextDiffURL); err == nil {
+					response.TextDiff = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			if scheduledSnapshot.Status.BaselineStylesURL != "" {
+				if data, err := storageClient.Get(r.Context(), scheduledSnapshot.Status.BaselineStylesURL); err == nil {
+					response.BaselineStyles = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			if scheduledSnapshot.Status.TargetStylesURL != "" {
+				if data, err := storageClient.Get(r.Context(), scheduledSnapshot.Status.TargetStylesURL); err == nil {
+					response.TargetStyles = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			if scheduledSnapshot.Status.StyleDiffURL != "" {
+				if data, err := storageClient.Get(r.Context(), scheduledSnapshot.Status.StyleDiffURL); err == nil {
+					response.StyleDiff = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			for _, composite := range scheduledSnapshot.Status.ScreenshotComposites {
+				artifact := CompositeArtifact{
+					Format: composite.Format,
+				}
+				if data, err := storageClient.Get(r.Context(), composite.URL); err == nil {
+					artifact.Data = base64.StdEncoding.EncodeToString(data)
+				}
+				response.Composites = append(response.Composites, artifact)
+			}
+			for _, pageDiff := range scheduledSnapshot.Status.PageDiffs {
+				artifact := PageDiffArtifact{
+					Page:       pageDiff.Page,
+					DiffAmount: pageDiff.DiffAmount,
+				}
+				if pageDiff.DiffURL != "" {
+					if data, err := storageClient.Get(r.Context(), pageDiff.DiffURL); err == nil {
+						artifact.Diff = base64.StdEncoding.EncodeToString(data)
+					}
+				}
+				response.PageDiffs = append(response.PageDiffs, artifact)
+			}
 		default:
 			http.Error(w, "Unsupported resource kind", http.StatusBadRequest)
 			return
@@ -16,14 +16,34 @@ import (
 )
 
 type ArtifactsRequest struct {
-	BaselineURL          string  `json:"baselineURL"`
-	TargetURL            string  `json:"targetURL"`
-	BaselineHTMLURL      string  `json:"baselineHTMLURL"`
-	TargetHTMLURL        string  `json:"targetHTMLURL"`
-	ScreenshotDiffURL    string  `json:"screenshotDiffURL"`
-	ScreenshotDiffAmount float64 `json:"screenshotDiffAmount"`
-	HTMLDiffURL          string  `json:"htmlDiffURL"`
-	HTMLDiffAmount       float64 `json:"htmlDiffAmount"`
+	BaselineURL               string              `json:"baselineURL"`
+	TargetURL                 string              `json:"targetURL"`
+	BaselineHTMLURL           string              `json:"baselineHTMLURL"`
+	TargetHTMLURL             string              `json:"targetHTMLURL"`
+	BaselineTextURL           string              `json:"baselineTextURL"`
+	TargetTextURL             string              `json:"targetTextURL"`
+	ScreenshotDiffURL         string              `json:"screenshotDiffURL"`
+	ScreenshotDiffRegionsURL  string              `json:"screenshotDiffRegionsURL"`
+	ScreenshotComposites      []v1.Composite      `json:"screenshotComposites"`
+	ScreenshotDiffAmount      float64             `json:"screenshotDiffAmount"`
+	ScreenshotInsertedBands   []v1.Band           `json:"screenshotInsertedBands"`
+	ScreenshotRemovedBands    []v1.Band           `json:"screenshotRemovedBands"`
+	ScreenshotDimensionChange *v1.DimensionChange `json:"screenshotDimensionChange"`
+	HTMLDiffURL               string              `json:"htmlDiffURL"`
+	HTMLDiffChangesURL        string              `json:"htmlDiffChangesURL"`
+	HTMLDiffRenderedURL       string              `json:"htmlDiffRenderedURL"`
+	HTMLDiffAmount            float64             `json:"htmlDiffAmount"`
+	TextDiffURL               string              `json:"textDiffURL"`
+	TextDiffAmount            float64             `json:"textDiffAmount"`
+	BaselineStylesURL         string              `json:"baselineStylesURL"`
+	TargetStylesURL           string              `json:"targetStylesURL"`
+	StyleDiffURL              string              `json:"styleDiffURL"`
+	StyleDiffAmount           float64             `json:"styleDiffAmount"`
+	BaselinePDFURL            string              `json:"baselinePDFURL"`
+	TargetPDFURL              string              `json:"targetPDFURL"`
+	BaselinePageURLs          []string            `json:"baselinePageURLs"`
+	TargetPageURLs            []string            `json:"targetPageURLs"`
+	PageDiffs                 []v1.PageDiff       `json:"pageDiffs"`
 }
 
 func UpdateArtifacts(dynamicClient *dynamic.DynamicClient) http.HandlerFunc {
@@ -52,15 +72,35 @@ func UpdateArtifacts(dynamicClient *dynamic.DynamicClient) http.HandlerFunc {
 		switch kind {
 		case "snapshot":
 			status := v1.SnapshotStatus{
-				BaselineURL:          request.BaselineURL,
-				TargetURL:            request.TargetURL,
-				BaselineHTMLURL:      request.BaselineHTMLURL,
-				TargetHTMLURL:        request.TargetHTMLURL,
-				ScreenshotDiffURL:    request.ScreenshotDiffURL,
-				ScreenshotDiffAmount: request.ScreenshotDiffAmount,
-				HTMLDiffURL:          request.HTMLDiffURL,
-				HTMLDiffAmount:       request.HTMLDiffAmount,
-				LastSnapshotTime:     &metav1.Time{Time: time.Now()},
+				BaselineURL:               request.BaselineURL,
+				TargetURL:                 request.TargetURL,
+				BaselineHTMLURL:           request.BaselineHTMLURL,
+				TargetHTMLURL:             request.TargetHTMLURL,
+				BaselineTextURL:           request.BaselineTextURL,
+				TargetTextURL:             request.TargetTextURL,
+				ScreenshotDiffURL:         request.ScreenshotDiffURL,
+				ScreenshotDiffRegionsURL:  request.ScreenshotDiffRegionsURL,
+				ScreenshotComposites:      request.ScreenshotComposites,
+				ScreenshotDiffAmount:      request.ScreenshotDiffAmount,
+				ScreenshotInsertedBands:   request.ScreenshotInsertedBands,
+				ScreenshotRemovedBands:    request.ScreenshotRemovedBands,
+				ScreenshotDimensionChange: request.ScreenshotDimensionChange,
+				HTMLDiffURL:               request.HTMLDiffURL,
+				HTMLDiffChangesURL:        request.HTMLDiffChangesURL,
+				HTMLDiffRenderedURL:       request.HTMLDiffRenderedURL,
+				HTMLDiffAmount:            request.HTMLDiffAmount,
+				TextDiffURL:               request.TextDiffURL,
+				TextDiffAmount:            request.TextDiffAmount,
+				BaselineStylesURL:         request.BaselineStylesURL,
+				TargetStylesURL:           request.TargetStylesURL,
+				StyleDiffURL:              request.StyleDiffURL,
+				StyleDiffAmount:           request.StyleDiffAmount,
+				BaselinePDFURL:            request.BaselinePDFURL,
+				TargetPDFURL:              request.TargetPDFURL,
+				BaselinePageURLs:          request.BaselinePageURLs,
+				TargetPageURLs:            request.TargetPageURLs,
+				PageDiffs:                 request.PageDiffs,
+				LastSnapshotTime:          &metav1.Time{Time: time.Now()},
 			}
 
 			statusPatch := map[string]interface{}{
@@ -75,15 +115,35 @@ func UpdateArtifacts(dynamicClient *dynamic.DynamicClient) http.HandlerFunc {
 			}
 		case "scheduledsnapshot":
 			status := v1.ScheduledSnapshotStatus{
-				BaselineURL:          request.BaselineURL,
-				TargetURL:            request.TargetURL,
-				BaselineHTMLURL:      request.BaselineHTMLURL,
-				TargetHTMLURL:        request.TargetHTMLURL,
-				ScreenshotDiffURL:    request.ScreenshotDiffURL,
-				ScreenshotDiffAmount: request.ScreenshotDiffAmount,
-				HTMLDiffURL:          request.HTMLDiffURL,
-				HTMLDiffAmount:       request.HTMLDiffAmount,
-				LastSnapshotTime:     &metav1.Time{Time: time.Now()},
+				BaselineURL:               request.BaselineURL,
+				TargetURL:                 request.TargetURL,
+				BaselineHTMLURL:           request.BaselineHTMLURL,
+				TargetHTMLURL:             request.TargetHTMLURL,
+				BaselineTextURL:           request.BaselineTextURL,
+				TargetTextURL:             request.TargetTextURL,
+				ScreenshotDiffURL:         request.ScreenshotDiffURL,
+				ScreenshotDiffRegionsURL:  request.ScreenshotDiffRegionsURL,
+				ScreenshotComposites:      request.ScreenshotComposites,
+				ScreenshotDiffAmount:      request.ScreenshotDiffAmount,
+				ScreenshotInsertedBands:   request.ScreenshotInsertedBands,
+				ScreenshotRemovedBands:    request.ScreenshotRemovedBands,
+				ScreenshotDimensionChange: request.ScreenshotDimensionChange,
+				HTMLDiffURL:               request.HTMLDiffURL,
+				HTMLDiffChangesURL:        request.HTMLDiffChangesURL,
+				HTMLDiffRenderedURL:       request.HTMLDiffRenderedURL,
+				HTMLDiffAmount:            request.HTMLDiffAmount,
+				TextDiffURL:               request.TextDiffURL,
+				TextDiffAmount:            request.TextDiffAmount,
+				BaselineStylesURL:         request.BaselineStylesURL,
+				TargetStylesURL:           request.TargetStylesURL,
+				StyleDiffURL:              request.StyleDiffURL,
+				StyleDiffAmount:           request.StyleDiffAmount,
+				BaselinePDFURL:            request.BaselinePDFURL,
+				TargetPDFURL:              request.TargetPDFURL,
+				BaselinePageURLs:          request.BaselinePageURLs,
+				TargetPageURLs:            request.TargetPageURLs,
+				PageDiffs:                 request.PageDiffs,
+				LastSnapshotTime:          &metav1.Time{Time: time.Now()},
 			}
 
 			statusPatch := map[string]interface{}{
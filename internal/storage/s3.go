@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"mime"
 	"net/http"
 	"os"
+	"path"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -51,7 +53,12 @@ func NewS3Storage(ctx context.Context, s S3Config) (Storage, error) {
 }
 
 func (s *s3Storage) Put(ctx context.Context, key string, data []byte) (string, error) {
-	contentType := http.DetectContentType(data)
+	// Detecting from the data alone labels JSON, CSS and similar text artifacts as text/plain, so the extension of
+	// the key takes precedence.
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
 
 	if _, err := s.client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(s.config.Bucket),
@@ -91,6 +91,8 @@ func main() {
 	var distributedCallbackHost string
 	var distributedWorkerImage string
 
+	var diffMemoryBudget int
+
 	flag.StringVar(&metricsAddr, "metrics-bind-address", envOrDefaultValue("METRICS_BIND_ADDRESS", "0.0.0.0:8080"), "The address the metric endpoint binds to.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", envOrDefaultValue("METRICS_SECURE", false), "If set the metrics endpoint is served securely")
 	flag.BoolVar(&enableHTTP2, "enable-http2", envOrDefaultValue("ENABLE_HTTP2", false), "If set, HTTP/2 will be enabled for the metrics and webhook servers")
@@ -101,6 +103,7 @@ func main() {
 	flag.BoolVar(&distributed, "distributed", envOrDefaultValue("DISTRIBUTED", false), "Enable distributed mode using Jobs/CronJobs")
 	flag.StringVar(&distributedCallbackHost, "distributed-callback-host", envOrDefaultValue("DISTRIBUTED_CALLBACK_HOST", "snapshot-controller.snapshot-controller.svc.cluster.local:8082"), "Enable callback host for distributed mode")
 	flag.StringVar(&distributedWorkerImage, "distributed-worker-image", envOrDefaultValue("DISTRIBUTED_WORKER_IMAGE", "ghcr.io/kaidotdev/snapshot-controller/snapshot-worker:main"), "The image to use for the distributed worker jobs")
+	flag.IntVar(&diffMemoryBudget, "diff-memory-budget", envOrDefaultValue("DIFF_MEMORY_BUDGET", 0), "Bytes allocated for comparing screenshots, excluding the decoded screenshots themselves, above which they are compared in strips (0 for no budget)")
 	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
 	klog.InitFlags(flag.CommandLine)
@@ -161,6 +164,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	httpCapturer, err := capture.NewHTTPCapturer(ctx, capture.DefaultHTTPConfig())
+	if err != nil {
+		entrypointLogger.Error(err, "unable to create HTTP capturer")
+		os.Exit(1)
+	}
+
 	s3, err := storage.NewS3Storage(ctx, storage.S3Config{
 		Bucket: os.Getenv("S3_BUCKET"),
 	})
@@ -176,8 +185,10 @@ func main() {
 		Recorder:                m.GetEventRecorderFor("snapshot-controller"),
 		Capturer:                capturer,
 		Storage:                 s3,
+		HTTPCapturer:            httpCapturer,
 		Distributed:             distributed,
 		DistributedCallbackHost: distributedCallbackHost,
+		DiffMemoryBudget:        diffMemoryBudget,
 	}).SetupWithManager(m); err != nil {
 		entrypointLogger.Error(err, "unable to create controller", "controller", "Snapshot")
 		os.Exit(1)
@@ -190,8 +201,10 @@ func main() {
 		Recorder:                m.GetEventRecorderFor("scheduledsnapshot-controller"),
 		Capturer:                capturer,
 		Storage:                 s3,
+		HTTPCapturer:            httpCapturer,
 		Distributed:             distributed,
 		DistributedCallbackHost: distributedCallbackHost,
+		DiffMemoryBudget:        diffMemoryBudget,
 	}).SetupWithManager(m); err != nil {
 		entrypointLogger.Error(err, "unable to create controller", "controller", "ScheduledSnapshot")
 		os.Exit(1)
@@ -142,15 +142,37 @@ const App = () => {
         });
     };
 
+    const unifiedDiffLineClass = (line) => {
+        if (line.startsWith("---") || line.startsWith("+++")) return "font-semibold text-gray-700";
+        if (line.startsWith("@@")) return "text-cyan-700 bg-cyan-50";
+        if (line.startsWith("+")) return "text-green-800 bg-green-50";
+        if (line.startsWith("-")) return "text-red-800 bg-red-50";
+        return "text-gray-800";
+    };
+
     const renderHTMLDiff = (base64Data) => {
         if (!base64Data) return h("div", {class: "text-gray-500"}, "HTMLの差分がありません");
-        const htmlContent = atob(base64Data);
+        const lines = atob(base64Data).replace(/\n$/, "").split("\n");
         return h("div", {
             class: "w-full h-96 border border-gray-300 rounded bg-white p-4 overflow-auto",
-            dangerouslySetInnerHTML: {__html: htmlContent}
+        }, h("pre", {class: "text-xs"}, lines.map((line) => h("div", {class: unifiedDiffLineClass(line)}, line))));
+    };
+
+    // The rendered HTML diff is generated by the controller with all text escaped, so it is inserted as is.
+    const renderRenderedHTMLDiff = (base64Data) => {
+        return h("div", {
+            class: "w-full h-96 border border-gray-300 rounded bg-white p-4 overflow-auto text-xs [&_.diff-header]:font-semibold [&_.diff-header]:text-gray-700 [&_.diff-hunk]:text-cyan-700 [&_.diff-hunk]:bg-cyan-50 [&_.diff-added]:text-green-800 [&_.diff-added]:bg-green-50 [&_.diff-removed]:text-red-800 [&_.diff-removed]:bg-red-50 [&_.diff-marker]:text-gray-500 [&_span]:block [&_ins]:bg-green-200 [&_ins]:no-underline [&_del]:bg-red-200 [&_del]:no-underline",
+            dangerouslySetInnerHTML: {__html: atob(base64Data)},
         });
     };
 
+    const renderTextDiff = (base64Data) => {
+        if (!base64Data) return h("div", {class: "text-gray-500"}, "テキストの差分がありません");
+        return h("div", {
+            class: "w-full h-96 border border-gray-300 rounded bg-white p-4 overflow-auto",
+        }, h("pre", {class: "text-sm whitespace-pre-wrap"}, atob(base64Data)));
+    };
+
     return (
         h("div", {class: "flex flex-col min-h-screen items-center bg-blue-50 p-6 space-y-6"}, [
             h("div", {class: "flex flex-row space-x-6 bg-white p-6 rounded-lg shadow-lg"}, [
@@ -196,19 +218,51 @@ const App = () => {
                     ]),
                     artifacts.diffAmount !== undefined && h("div", {class: "mb-4"}, [
                         h("p", {class: "text-sm text-gray-600"}, `画像差分: ${(artifacts.diffAmount * 100).toFixed(2)}%`),
+                        artifacts.insertedBands && h("p", {class: "text-sm text-gray-600"}, `挿入された行: ${artifacts.insertedBands.map((band) => `${band.y}-${band.y + band.height - 1}px`).join(", ")}`),
+                        artifacts.removedBands && h("p", {class: "text-sm text-gray-600"}, `削除された行: ${artifacts.removedBands.map((band) => `${band.y}-${band.y + band.height - 1}px`).join(", ")}`),
+                        artifacts.dimensionChange && h("p", {class: "text-sm text-gray-600"}, `サイズ変更: ${artifacts.dimensionChange.baselineWidth}x${artifacts.dimensionChange.baselineHeight}px → ${artifacts.dimensionChange.targetWidth}x${artifacts.dimensionChange.targetHeight}px (幅 ${artifacts.dimensionChange.targetWidth - artifacts.dimensionChange.baselineWidth >= 0 ? "+" : ""}${artifacts.dimensionChange.targetWidth - artifacts.dimensionChange.baselineWidth}px, 高さ ${artifacts.dimensionChange.targetHeight - artifacts.dimensionChange.baselineHeight >= 0 ? "+" : ""}${artifacts.dimensionChange.targetHeight - artifacts.dimensionChange.baselineHeight}px)`),
+                        artifacts.screenshotDiffRegions && h("p", {class: "text-sm text-gray-600"}, `変更された領域: ${artifacts.screenshotDiffRegions.map((region) => `(${region.x}, ${region.y}) ${region.width}x${region.height}px ${(region.pixelRatio * 100).toFixed(0)}%`).join(", ")}`),
                     ]),
                     artifacts.htmlDiffAmount !== undefined && h("div", {class: "mb-4"}, [
                         h("p", {class: "text-sm text-gray-600"}, `HTML差分: ${(artifacts.htmlDiffAmount * 100).toFixed(2)}%`),
                     ]),
+                    artifacts.textDiffAmount !== undefined && h("div", {class: "mb-4"}, [
+                        h("p", {class: "text-sm text-gray-600"}, `テキスト差分: ${(artifacts.textDiffAmount * 100).toFixed(2)}%`),
+                    ]),
+                    artifacts.styleDiffAmount !== undefined && h("div", {class: "mb-4"}, [
+                        h("p", {class: "text-sm text-gray-600"}, `スタイル差分: ${(artifacts.styleDiffAmount * 100).toFixed(2)}%`),
+                    ]),
                     showDiff ? (
                         h("div", {class: "space-y-6"}, [
                             h("div", null, [
                                 h("h3", {class: "text-lg font-semibold mb-2"}, "スクリーンショット差分"),
                                 renderScreenshot(artifacts.screenshotDiff, "Screenshot Diff")
                             ]),
+                            artifacts.composites && h("div", null, [
+                                h("h3", {class: "text-lg font-semibold mb-2"}, "合成画像"),
+                                h("div", {class: "space-y-4"}, artifacts.composites.map((composite) => h("div", null, [
+                                    h("p", {class: "text-sm text-gray-600 mb-1"}, composite.format),
+                                    renderScreenshot(composite.data, `${composite.format} Composite`)
+                                ])))
+                            ]),
                             h("div", null, [
                                 h("h3", {class: "text-lg font-semibold mb-2"}, "HTML差分"),
-                                renderHTMLDiff(artifacts.htmlDiff)
+                                artifacts.htmlDiffRendered ? renderRenderedHTMLDiff(artifacts.htmlDiffRendered) : renderHTMLDiff(artifacts.htmlDiff)
+                            ]),
+                            h("div", null, [
+                                h("h3", {class: "text-lg font-semibold mb-2"}, "テキスト差分"),
+                                renderTextDiff(artifacts.textDiff)
+                            ]),
+                            artifacts.pageDiffs && h("div", null, [
+                                h("h3", {class: "text-lg font-semibold mb-2"}, "ページ差分"),
+                                h("div", {class: "space-y-4"}, artifacts.pageDiffs.map((pageDiff) => h("div", null, [
+                                    h("p", {class: "text-sm text-gray-600 mb-1"}, `${pageDiff.page}ページ: ${(pageDiff.diffAmount * 100).toFixed(2)}%`),
+                                    renderScreenshot(pageDiff.diff, `Page ${pageDiff.page} Diff`)
+                                ])))
+                            ]),
+                            artifacts.styleDiff && h("div", null, [
+                                h("h3", {class: "text-lg font-semibold mb-2"}, "スタイル差分"),
+                                renderTextDiff(artifacts.styleDiff)
                             ]),
                         ])
                     ) : (